@@ -2,164 +2,71 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"os"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
-	"github.com/user54778/cyclone/internal/math64"
-	"github.com/user54778/cyclone/internal/physics"
+	"github.com/user54778/cyclone/cmd/demos/internal/ballistic"
+	"github.com/user54778/cyclone/cmd/demos/internal/rlconv"
+	"github.com/user54778/cyclone/internal/physicslog"
 )
 
-// shotType represents the type of ballistic being shot.
-type shotType int
-
-const (
-	Unused shotType = iota
-	Pistol
-	Artillery
-	Fireball
-	Laser
-)
-
-// ammoRound is a type to represent a single ammunition round record.
-type AmmoRound struct {
-	particle  physics.Particle // Every weapon fires a particle.
-	shotType  shotType         // Different bullet types per weapon
-	startTime int
+// BallisticDemo runs the ballistic simulation in real time, fires and switches weapons from user
+// input, and draws the rounds in flight.
+type BallisticDemo struct {
+	*ballistic.Sim
+	logger *physicslog.PhysicsLogger // The simulation's logger, for input events.
 }
 
-func convertToRayVec3(v math64.Vector3) rl.Vector3 {
-	return rl.Vector3{X: float32(v.X), Y: float32(v.Y), Z: float32(v.Z)}
+// Update advances the simulation by the last frame's duration.
+func (demo *BallisticDemo) Update() {
+	duration := rl.GetFrameTime() // Last frame's duration in seconds
+	demo.Step(float64(duration))
 }
 
-// Render draws the ammo round.
-func (r *AmmoRound) Render() {
-	position := r.particle.Position
-	rlPosition := convertToRayVec3(position)
+// renderRound draws an ammo round in flight.
+func renderRound(r *ballistic.AmmoRound) {
+	position := r.Particle().Position
+	rlPosition := rlconv.ToRay(position)
 
 	var color rl.Color
-	switch r.shotType {
-	case Pistol:
+	switch r.Type() {
+	case ballistic.Pistol:
 		color = rl.Black
-	case Artillery:
+	case ballistic.Artillery:
 		color = rl.Brown
-	case Fireball:
+	case ballistic.Fireball:
 		color = rl.Red
-	case Laser:
+	case ballistic.Laser:
 		color = rl.Yellow
 	}
 
 	// Draw a solid black sphere
 	rl.DrawSphereEx(rlPosition, 1, 5, 4, color)
 
+	// Draw the trail of recent positions behind the round.
+	trail := r.Particle().Trail()
+	for i := 1; i < len(trail); i++ {
+		rl.DrawLine3D(rlconv.ToRay(trail[i-1]), rlconv.ToRay(trail[i]), color)
+	}
+
 	// Draw a flattened sphere representing the particle's shadow.
 	gray := rl.NewColor(50, 50, 50, 128)
 	shadowPosition := rl.Vector3{X: rlPosition.X, Y: 0, Z: rlPosition.Z}
 	rl.DrawSphereWires(shadowPosition, 1, 5, 4, gray)
 }
 
-// BallisticDemo holds state of the Weapon ammo and ammo type.
-type BallisticDemo struct {
-	ammo            []AmmoRound
-	currentShotType shotType
-}
-
-func NewBallisticDemo(ammoRounds int) BallisticDemo {
-	return BallisticDemo{
-		ammo:            make([]AmmoRound, ammoRounds),
-		currentShotType: Pistol,
-	}
-}
-
-// fire is a function that deals with the particle specifics of the ballistics.
-func (demo *BallisticDemo) Fire() {
-	for i := range demo.ammo {
-		shot := &demo.ammo[i]
-		// Get the first available round
-		if shot.shotType == Unused {
-			// Set properties of the particle.
-			// Note here that mass of the particle should be exaggerated to more than real life.
-			switch demo.currentShotType {
-			case Pistol:
-				shot.particle.SetMass(2.0)                                     // 2.0kg
-				shot.particle.Velocity = math64.NewVector3(0.0, 0.0, 35.0)     // 35 m/s
-				shot.particle.Acceleration = math64.NewVector3(0.0, -1.0, 0.0) // Effect of gravity
-				shot.particle.Damping = 0.99                                   // No friction
-			case Artillery:
-				shot.particle.SetMass(200.0)                                // 200.0kg
-				shot.particle.Velocity = math64.NewVector3(0.0, 30.0, 40.0) // 50 m/s
-				shot.particle.Acceleration = math64.NewVector3(0.0, -20.0, 0.0)
-				shot.particle.Damping = 0.99
-			case Fireball:
-				shot.particle.SetMass(1.0)                                    // 1.0kg - mostly blast damage
-				shot.particle.Velocity = math64.NewVector3(0.0, 0.0, 10.0)    // 5 m/s
-				shot.particle.Acceleration = math64.NewVector3(0.0, 0.6, 0.0) // Floats up
-				shot.particle.Damping = 0.9
-			case Laser:
-				shot.particle.SetMass(0.1)                                    // 0.1kg; almost no mass. This is the kind of laser as seen in movies, not a realistic one
-				shot.particle.Velocity = math64.NewVector3(0.0, 0.0, 100.0)   // 100 m/s
-				shot.particle.Acceleration = math64.NewVector3(0.0, 0.0, 0.0) // No effect of gravity
-				shot.particle.Damping = 0.99
-			}
-
-			// Data common to all particle types
-			shot.particle.Position = math64.NewVector3(0.0, 1.5, 0.0)
-			shot.startTime = int(rl.GetTime() * 1000) // In ms
-			shot.shotType = demo.currentShotType
-
-			rl.TraceLog(rl.LogInfo, "Particle Type and Properties: %#v", shot)
-			// Exit after firing once
-			return
-		}
-	}
-}
-
-// update is a function that is used to update the particle positions.
-// This is where the integrator is used.
-func (demo *BallisticDemo) Update() {
-	duration := rl.GetFrameTime() // Last frame's duration in seconds
-	if duration <= 0.0 {
-		return
-	}
-
-	for i := range demo.ammo {
-		shot := &demo.ammo[i]
-		if shot.shotType != Unused {
-			shot.particle.Integrate(float64(duration))
-
-			// Special logic for fireball since it's onscreen longer.
-			if shot.shotType == Fireball {
-				if shot.particle.Position.Z > 50.0 || shot.particle.Position.Y > 20.0 {
-					shot.shotType = Unused
-					continue
-				}
-			}
-
-			// Bounds checks
-			// 1) Particle hasn't fallen below ground
-			// 2) Particle's lifetime < 5s
-			// 3) Particle hasn't moved past visible play area
-			if shot.particle.Position.Y < 0.0 || shot.startTime+5000 < int(rl.GetTime()) || shot.particle.Position.Z > 200.0 {
-				shot.shotType = Unused
-			}
-			rl.TraceLog(rl.LogInfo, "Updated particle: %#v", shot)
-		}
-	}
-}
-
 // switchWeapon switchs the users weapon type based on the numbers 1-4.
 func (demo *BallisticDemo) switchWeapon() {
 	switch {
 	case rl.IsKeyPressed(rl.KeyOne), rl.IsKeyPressed(rl.KeyKp1):
-		demo.currentShotType = Pistol
-		rl.TraceLog(rl.LogInfo, "Current Ammo Type: Pistol")
+		demo.SwitchWeapon(ballistic.Pistol)
 	case rl.IsKeyPressed(rl.KeyTwo), rl.IsKeyPressed(rl.KeyKp2):
-		demo.currentShotType = Artillery
-		rl.TraceLog(rl.LogInfo, "Current Ammo Type: Artillery")
+		demo.SwitchWeapon(ballistic.Artillery)
 	case rl.IsKeyPressed(rl.KeyThree), rl.IsKeyPressed(rl.KeyKp3):
-		demo.currentShotType = Fireball
-		rl.TraceLog(rl.LogInfo, "Current Ammo Type: Fireball")
+		demo.SwitchWeapon(ballistic.Fireball)
 	case rl.IsKeyPressed(rl.KeyFour), rl.IsKeyPressed(rl.KeyKp4):
-		demo.currentShotType = Laser
-		rl.TraceLog(rl.LogInfo, "Current Ammo Type: Laser")
+		demo.SwitchWeapon(ballistic.Laser)
 	}
 }
 
@@ -167,17 +74,26 @@ func (demo *BallisticDemo) switchWeapon() {
 func (demo *BallisticDemo) mouse() {
 	if rl.IsMouseButtonPressed(rl.MouseButtonLeft) {
 		demo.Fire()
-		rl.TraceLog(rl.LogInfo, "Fire!")
+		demo.logger.LogInfo("Fire!")
 	}
 }
 
 func main() {
 	var maxRounds int
+	var logLevel string
 	flag.IntVar(&maxRounds, "rounds", 16, "max amount of bullet rounds that can be on screen")
+	flag.StringVar(&logLevel, "loglevel", "info", "minimum level of demo logs to print: info, error, fatal or off")
 
 	flag.Parse()
 
-	demo := NewBallisticDemo(maxRounds)
+	level, err := physicslog.ParseLevel(logLevel)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	logger := physicslog.NewPhysicsLogger(level)
+	demo := &BallisticDemo{Sim: ballistic.New(maxRounds, logger), logger: logger}
 
 	rl.InitWindow(1280, 720, "ballistic")
 	defer rl.CloseWindow()
@@ -218,10 +134,11 @@ func main() {
 		}
 
 		// Render each particle in turn
-		for _, round := range demo.ammo {
-			if round.shotType != Unused {
+		rounds := demo.Rounds()
+		for i := range rounds {
+			if rounds[i].Type() != ballistic.Unused {
 				// rl.TraceLog(rl.LogInfo, "Rendering particle")
-				round.Render()
+				renderRound(&rounds[i])
 			}
 		}
 		// rl.TraceLog(rl.LogInfo, "Demo object: %#v", demo)
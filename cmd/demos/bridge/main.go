@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/user54778/cyclone/cmd/demos/internal/bridge"
+	"github.com/user54778/cyclone/cmd/demos/internal/rlconv"
+)
+
+// massSpeed is the speed the weight moves along the bridge, in units per second.
+const massSpeed = 2.0
+
+// BridgeDemo runs the bridge simulation in real time, moves its weight from keyboard input, and
+// draws it.
+type BridgeDemo struct {
+	*bridge.Sim
+	// accumulator holds frame time not yet consumed by a fixed physics step.
+	accumulator float64
+}
+
+// Update advances the simulation by the last frame's duration, in fixed steps.
+func (demo *BridgeDemo) Update() {
+	duration := rl.GetFrameTime() // Last frame's duration in seconds
+	if duration <= 0.0 {
+		return
+	}
+
+	demo.accumulator += float64(duration)
+	for demo.accumulator >= bridge.TimeStep {
+		if err := demo.Step(); err != nil {
+			rl.TraceLog(rl.LogError, "bridge physics: %v", err)
+		}
+		demo.accumulator -= bridge.TimeStep
+	}
+}
+
+// keyboard moves the weight with the WASD or arrow keys.
+func (demo *BridgeDemo) keyboard() {
+	step := massSpeed * float64(rl.GetFrameTime())
+	switch {
+	case rl.IsKeyDown(rl.KeyW), rl.IsKeyDown(rl.KeyUp):
+		demo.MoveMass(step, 0)
+	case rl.IsKeyDown(rl.KeyS), rl.IsKeyDown(rl.KeyDown):
+		demo.MoveMass(-step, 0)
+	case rl.IsKeyDown(rl.KeyA), rl.IsKeyDown(rl.KeyLeft):
+		demo.MoveMass(0, -step)
+	case rl.IsKeyDown(rl.KeyD), rl.IsKeyDown(rl.KeyRight):
+		demo.MoveMass(0, step)
+	}
+}
+
+// Render draws the bridge and the weight crossing it.
+func (demo *BridgeDemo) Render() {
+	particles := demo.Particles()
+	for i := range particles {
+		rl.DrawSphereEx(rlconv.ToRay(particles[i].Position), 0.1, 5, 4, rl.Black)
+	}
+
+	for _, rod := range demo.Rods() {
+		rl.DrawLine3D(rlconv.ToRay(rod.Particles[0].Position), rlconv.ToRay(rod.Particles[1].Position), rl.DarkBlue)
+	}
+	for _, cable := range demo.Cables() {
+		rl.DrawLine3D(rlconv.ToRay(cable.Particles[0].Position), rlconv.ToRay(cable.Particles[1].Position), rl.DarkGreen)
+	}
+	for _, support := range demo.Supports() {
+		rl.DrawLine3D(rlconv.ToRay(support.Particle.Position), rlconv.ToRay(support.Anchor), rl.Brown)
+	}
+
+	rl.DrawSphereEx(rlconv.ToRay(demo.MassPosition()), 0.25, 20, 10, rl.Red)
+}
+
+func main() {
+	var extraMass float64
+	flag.Float64Var(&extraMass, "mass", 10.0, "mass of the weight crossing the bridge, in kg")
+
+	flag.Parse()
+
+	demo := &BridgeDemo{Sim: bridge.New(extraMass)}
+
+	rl.InitWindow(1280, 720, "bridge")
+	defer rl.CloseWindow()
+
+	camera := &rl.Camera{}
+	camera.Position = rl.NewVector3(0.0, 8.0, 14.0)
+	camera.Target = rl.NewVector3(0.0, 3.0, 0.0) // Camera looking at point
+	camera.Up = rl.NewVector3(0.0, 1.0, 0.0)     // Where it rotates over the y-unit vector
+	camera.Fovy = 45.0                           // How close I am
+
+	rl.SetTargetFPS(60)
+
+	for !rl.WindowShouldClose() {
+		// Input
+		demo.keyboard()
+
+		// Game logic
+		demo.Update()
+
+		// Rendering
+		rl.BeginDrawing()
+		rl.ClearBackground(rl.LightGray)
+
+		rl.BeginMode3D(*camera)
+		rl.DrawGrid(20, 1.0)
+		demo.Render()
+		rl.EndMode3D()
+
+		rl.DrawText("WASD / arrow keys: move the weight", 10, 40, 20, rl.DarkGray)
+		rl.DrawFPS(10, 10)
+
+		rl.EndDrawing()
+	}
+}
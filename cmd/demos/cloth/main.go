@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/user54778/cyclone/cmd/demos/internal/cloth"
+	"github.com/user54778/cyclone/cmd/demos/internal/rlconv"
+)
+
+// ClothDemo runs the cloth simulation in real time and draws it.
+type ClothDemo struct {
+	*cloth.Sim
+	// accumulator holds frame time not yet consumed by a fixed physics step.
+	accumulator float64
+}
+
+// Update advances the simulation by the last frame's duration, in fixed steps.
+func (demo *ClothDemo) Update() {
+	duration := rl.GetFrameTime() // Last frame's duration in seconds
+	if duration <= 0.0 {
+		return
+	}
+
+	demo.accumulator += float64(duration)
+	for demo.accumulator >= cloth.TimeStep {
+		if err := demo.Step(); err != nil {
+			rl.TraceLog(rl.LogError, "cloth physics: %v", err)
+		}
+		demo.accumulator -= cloth.TimeStep
+	}
+}
+
+// Render draws the cloth's links and particles.
+func (demo *ClothDemo) Render() {
+	particles := demo.Particles()
+	for _, link := range demo.Links() {
+		a := rlconv.ToRay(particles[link.A].Position)
+		b := rlconv.ToRay(particles[link.B].Position)
+		rl.DrawLine3D(a, b, rl.DarkBlue)
+	}
+
+	for i := range particles {
+		p := &particles[i]
+		color := rl.Blue
+		if !p.HasFiniteMass() {
+			color = rl.Red // Pinned particles
+		}
+		rl.DrawSphereEx(rlconv.ToRay(p.Position), 0.1, 4, 4, color)
+	}
+}
+
+func main() {
+	var rows, cols int
+	var spacing, stiffness float64
+	var shear, bend bool
+	flag.IntVar(&rows, "rows", 12, "number of particle rows in the cloth")
+	flag.IntVar(&cols, "cols", 12, "number of particle columns in the cloth")
+	flag.Float64Var(&spacing, "spacing", 0.5, "rest distance between neighbouring particles")
+	flag.Float64Var(&stiffness, "k", 50.0, "spring constant of the cloth springs")
+	flag.BoolVar(&shear, "shear", true, "add shear springs across diagonals")
+	flag.BoolVar(&bend, "bend", false, "add bend springs between particles two apart")
+
+	flag.Parse()
+
+	demo := &ClothDemo{Sim: cloth.New(rows, cols, spacing, stiffness, shear, bend)}
+
+	rl.InitWindow(1280, 720, "cloth")
+	defer rl.CloseWindow()
+
+	camera := &rl.Camera{}
+	camera.Position = rl.NewVector3(-12.0, 8.0, -6.0)
+	camera.Target = rl.NewVector3(0.0, 6.0, 2.0) // Camera looking at point
+	camera.Up = rl.NewVector3(0.0, 1.0, 0.0)     // Where it rotates over the y-unit vector
+	camera.Fovy = 45.0                           // How close I am
+
+	rl.SetTargetFPS(60)
+
+	for !rl.WindowShouldClose() {
+		// Game logic
+		demo.Update()
+
+		// Rendering
+		rl.BeginDrawing()
+		rl.ClearBackground(rl.LightGray)
+
+		rl.BeginMode3D(*camera)
+		rl.DrawGrid(20, 1.0)
+		demo.Render()
+		rl.EndMode3D()
+
+		rl.DrawFPS(10, 10)
+
+		rl.EndDrawing()
+	}
+}
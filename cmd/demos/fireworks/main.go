@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/user54778/cyclone/cmd/demos/internal/fireworks"
+	"github.com/user54778/cyclone/cmd/demos/internal/rlconv"
+)
+
+// palette holds the colors rockets are drawn in, indexed by a firework's Color.
+var palette = [fireworks.PaletteSize]rl.Color{rl.Red, rl.Gold, rl.Lime, rl.SkyBlue, rl.Violet, rl.Orange}
+
+// FireworksDemo runs the fireworks simulation in real time, launches rockets from user input, and
+// draws them.
+type FireworksDemo struct {
+	*fireworks.Sim
+	// accumulator holds frame time not yet consumed by a fixed physics step.
+	accumulator float64
+}
+
+// Update advances the simulation by the last frame's duration, in fixed steps.
+func (demo *FireworksDemo) Update() {
+	duration := rl.GetFrameTime() // Last frame's duration in seconds
+	if duration <= 0.0 {
+		return
+	}
+
+	demo.accumulator += float64(duration)
+	for demo.accumulator >= fireworks.TimeStep {
+		if err := demo.Step(); err != nil {
+			rl.TraceLog(rl.LogError, "fireworks physics: %v", err)
+		}
+		demo.accumulator -= fireworks.TimeStep
+	}
+}
+
+// input launches a rocket when the user clicks the left mouse button or presses space.
+func (demo *FireworksDemo) input() {
+	if rl.IsMouseButtonPressed(rl.MouseButtonLeft) || rl.IsKeyPressed(rl.KeySpace) {
+		demo.Launch()
+	}
+}
+
+// Render draws every live firework.
+func (demo *FireworksDemo) Render() {
+	for _, fw := range demo.Fireworks() {
+		radius := float32(0.15)
+		if fw.Type == fireworks.Spark {
+			radius = 0.08
+		}
+		rl.DrawSphereEx(rlconv.ToRay(fw.Particle.Position), radius, 4, 4, palette[fw.Color])
+	}
+}
+
+func main() {
+	var poolSize, sparks int
+	var interval float64
+	flag.IntVar(&poolSize, "pool", 512, "number of particles preallocated in the pool")
+	flag.IntVar(&sparks, "sparks", 40, "number of sparks thrown out by each burst")
+	flag.Float64Var(&interval, "interval", 0.75, "seconds between automatic launches; 0 disables them")
+
+	flag.Parse()
+
+	demo := &FireworksDemo{Sim: fireworks.New(poolSize, sparks, interval, time.Now().UnixNano())}
+
+	rl.InitWindow(1280, 720, "fireworks")
+	defer rl.CloseWindow()
+
+	camera := &rl.Camera{}
+	camera.Position = rl.NewVector3(0.0, 12.0, 35.0)
+	camera.Target = rl.NewVector3(0.0, 14.0, 0.0) // Camera looking at point
+	camera.Up = rl.NewVector3(0.0, 1.0, 0.0)      // Where it rotates over the y-unit vector
+	camera.Fovy = 45.0                            // How close I am
+
+	rl.SetTargetFPS(60)
+
+	for !rl.WindowShouldClose() {
+		// Input
+		demo.input()
+
+		// Game logic
+		demo.Update()
+
+		// Rendering
+		rl.BeginDrawing()
+		rl.ClearBackground(rl.Black)
+
+		rl.BeginMode3D(*camera)
+		rl.DrawGrid(20, 1.0)
+		demo.Render()
+		rl.EndMode3D()
+
+		rl.DrawText("Click or press space to launch", 10, 40, 20, rl.RayWhite)
+		rl.DrawFPS(10, 10)
+
+		rl.EndDrawing()
+	}
+}
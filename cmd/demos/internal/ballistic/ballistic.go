@@ -0,0 +1,174 @@
+// Package ballistic simulates the ballistic demo: rounds fired from a choice of weapons, each
+// flying under its own gravity and damping until it lands, expires or leaves the range.
+package ballistic
+
+import (
+	"github.com/user54778/cyclone/internal/math64"
+	"github.com/user54778/cyclone/internal/physics"
+	"github.com/user54778/cyclone/internal/physicslog"
+)
+
+// ShotType represents the type of ballistic being shot.
+type ShotType int
+
+const (
+	Unused ShotType = iota
+	Pistol
+	Artillery
+	Fireball
+	Laser
+)
+
+// String returns the name of the shot type, for logging.
+func (t ShotType) String() string {
+	switch t {
+	case Pistol:
+		return "Pistol"
+	case Artillery:
+		return "Artillery"
+	case Fireball:
+		return "Fireball"
+	case Laser:
+		return "Laser"
+	default:
+		return "Unused"
+	}
+}
+
+// AmmoRound is a type to represent a single ammunition round record.
+type AmmoRound struct {
+	particle physics.Particle // Every weapon fires a particle.
+	shotType ShotType         // Different bullet types per weapon
+}
+
+// Particle returns the round's particle.
+func (r *AmmoRound) Particle() *physics.Particle {
+	return &r.particle
+}
+
+// Type returns the type of shot the round was fired as, or Unused if it isn't in flight.
+func (r *AmmoRound) Type() ShotType {
+	return r.shotType
+}
+
+// ammoConfigs holds the particle properties of every weapon's rounds.
+// Note here that mass of the particle should be exaggerated to more than real life.
+var ammoConfigs = map[ShotType]physics.SimConfig{
+	Pistol: {
+		Position:     math64.NewVector3(0.0, 1.5, 0.0),
+		Mass:         2.0,                               // 2.0kg
+		Velocity:     math64.NewVector3(0.0, 0.0, 35.0), // 35 m/s
+		Acceleration: math64.NewVector3(0.0, -1.0, 0.0), // Effect of gravity
+		Damping:      0.99,                              // No friction
+		Lifetime:     5.0,                               // In s
+	},
+	Artillery: {
+		Position:     math64.NewVector3(0.0, 1.5, 0.0),
+		Mass:         200.0,                              // 200.0kg
+		Velocity:     math64.NewVector3(0.0, 30.0, 40.0), // 50 m/s
+		Acceleration: math64.NewVector3(0.0, -20.0, 0.0),
+		Damping:      0.99,
+		Lifetime:     5.0,
+	},
+	Fireball: {
+		Position:     math64.NewVector3(0.0, 1.5, 0.0),
+		Mass:         1.0,                               // 1.0kg - mostly blast damage
+		Velocity:     math64.NewVector3(0.0, 0.0, 10.0), // 5 m/s
+		Acceleration: math64.NewVector3(0.0, 0.6, 0.0),  // Floats up
+		Damping:      0.9,
+		Lifetime:     5.0,
+	},
+	Laser: {
+		Position:     math64.NewVector3(0.0, 1.5, 0.0),
+		Mass:         0.1,                                // 0.1kg; almost no mass. This is the kind of laser as seen in movies, not a realistic one
+		Velocity:     math64.NewVector3(0.0, 0.0, 100.0), // 100 m/s
+		Acceleration: math64.NewVector3(0.0, 0.0, 0.0),   // No effect of gravity
+		Damping:      0.99,
+		Lifetime:     5.0,
+	},
+}
+
+// Sim holds state of the Weapon ammo and ammo type.
+type Sim struct {
+	ammo            []AmmoRound
+	currentShotType ShotType
+	logger          *physicslog.PhysicsLogger // Diagnostic logging, quieted with the -loglevel flag.
+}
+
+// New creates a simulation with room for ammoRounds rounds in flight at once, firing pistol
+// rounds, which logs to logger.
+func New(ammoRounds int, logger *physicslog.PhysicsLogger) *Sim {
+	return &Sim{
+		ammo:            make([]AmmoRound, ammoRounds),
+		currentShotType: Pistol,
+		logger:          logger,
+	}
+}
+
+// Rounds returns every round, in flight or not. The slice is the simulation's own, so it reflects
+// every step.
+func (sim *Sim) Rounds() []AmmoRound {
+	return sim.ammo
+}
+
+// ShotType returns the type of round the next Fire shoots.
+func (sim *Sim) ShotType() ShotType {
+	return sim.currentShotType
+}
+
+// SwitchWeapon makes Fire shoot rounds of the given type.
+func (sim *Sim) SwitchWeapon(t ShotType) {
+	sim.currentShotType = t
+	sim.logger.LogInfo("Switched weapon", "type", sim.currentShotType)
+}
+
+// Fire is a function that deals with the particle specifics of the ballistics. It does nothing if
+// every round is already in flight.
+func (sim *Sim) Fire() {
+	for i := range sim.ammo {
+		shot := &sim.ammo[i]
+		// Get the first available round
+		if shot.shotType == Unused {
+			// Start from a fresh particle so no age carries over from the round's last use.
+			shot.particle = physics.NewParticleFromConfig(ammoConfigs[sim.currentShotType])
+			shot.particle.SetTrailLength(30)
+			shot.shotType = sim.currentShotType
+
+			sim.logger.LogInfo("Fired particle", "type", shot.shotType, "position", shot.particle.Position, "velocity", shot.particle.Velocity)
+			// Exit after firing once
+			return
+		}
+	}
+}
+
+// Step is a function that is used to update the particle positions over duration.
+// This is where the integrator is used.
+func (sim *Sim) Step(duration float64) {
+	if duration <= 0.0 {
+		return
+	}
+
+	for i := range sim.ammo {
+		shot := &sim.ammo[i]
+		if shot.shotType != Unused {
+			shot.particle.Integrate(duration)
+
+			// Special logic for fireball since it's onscreen longer.
+			if shot.shotType == Fireball {
+				if shot.particle.Position.Z > 50.0 || shot.particle.Position.Y > 20.0 {
+					shot.shotType = Unused
+					continue
+				}
+			}
+
+			// Bounds checks
+			// 1) Particle hasn't fallen below ground
+			// 2) Particle hasn't outlived its lifetime
+			// 3) Particle hasn't moved past visible play area
+			if shot.particle.Position.Y < 0.0 || shot.particle.Expired() || shot.particle.Position.Z > 200.0 {
+				shot.shotType = Unused
+			}
+			sim.logger.LogInfo("Updated particle", "type", shot.shotType, "position", shot.particle.Position, "velocity", shot.particle.Velocity)
+		}
+	}
+}
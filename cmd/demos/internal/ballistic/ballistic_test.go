@@ -0,0 +1,64 @@
+package ballistic
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/user54778/cyclone/internal/physicslog"
+)
+
+func TestSimLogging(t *testing.T) {
+	tests := []struct {
+		name        string
+		level       physicslog.Level
+		wantEntries int
+	}{
+		{"info logs every shot", physicslog.LevelInfo, 3},
+		{"error hides info", physicslog.LevelError, 0},
+		{"off suppresses everything", physicslog.LevelOff, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			sim := New(3, physicslog.NewPhysicsLoggerMulti(tt.level, &buf))
+
+			// One more shot than there are rounds; the last finds no free round and isn't logged.
+			for i := 0; i < 4; i++ {
+				sim.Fire()
+			}
+
+			if got := strings.Count(buf.String(), "Fired particle"); got != tt.wantEntries {
+				t.Errorf("logged %d shots, want %d; log:\n%s", got, tt.wantEntries, buf.String())
+			}
+			if tt.wantEntries == 0 && buf.Len() != 0 {
+				t.Errorf("logged %q, want nothing", buf.String())
+			}
+		})
+	}
+}
+
+func TestSimStepRetiresRounds(t *testing.T) {
+	for _, shot := range []ShotType{Pistol, Artillery, Fireball, Laser} {
+		t.Run(shot.String(), func(t *testing.T) {
+			sim := New(2, physicslog.NewPhysicsLoggerMulti(physicslog.LevelOff))
+			sim.SwitchWeapon(shot)
+			sim.Fire()
+
+			round := &sim.Rounds()[0]
+			start := round.Particle().Position
+			sim.Step(0)
+			if round.Type() != shot || round.Particle().Position != start {
+				t.Fatalf("a zero step changed the round to %v at %v", round.Type(), round.Particle().Position)
+			}
+
+			// Every round leaves the play area or outlives its five second lifetime.
+			for i := 0; i < 6*60 && round.Type() != Unused; i++ {
+				sim.Step(1.0 / 60)
+			}
+			if round.Type() != Unused {
+				t.Errorf("round still in flight at %v after six seconds", round.Particle().Position)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+// Package bridge simulates the bridge demo: a rope bridge of particles with a weight crossing it.
+// It doesn't depend on raylib, so it builds and tests without a display.
+package bridge
+
+import (
+	"math"
+
+	"github.com/user54778/cyclone/internal/math64"
+	"github.com/user54778/cyclone/internal/physics"
+)
+
+const (
+	// TimeStep is the fixed duration of a single physics step.
+	TimeStep = 1.0 / 120.0
+
+	particleCount = 12    // Two rows of six particles make up the walkway.
+	baseMass      = 1.0   // Mass of each walkway particle, in kg.
+	maxMassX      = 5.0   // The weight can travel from x = 0 to x = 5 along the walkway.
+	maxMassZ      = 1.0   // The weight can travel from z = 0 to z = 1 across the walkway.
+	gravity       = -9.81 // Acceleration due to gravity, in m/s^2.
+)
+
+// Sim holds a rope bridge made of particles. Cables hang the walkway from fixed anchors and
+// join its particles end to end, while rods hold each plank at a fixed width.
+type Sim struct {
+	world     *physics.ParticleWorld
+	particles [particleCount]physics.Particle
+	supports  []*physics.ParticleCableConstraint
+	cables    []*physics.ParticleCable
+	rods      []*physics.ParticleRod
+
+	// extraMass is the mass of the weight crossing the bridge, in kg.
+	extraMass float64
+	// massPos is the position of the weight in walkway coordinates; x runs along the bridge,
+	// z runs across it.
+	massPos math64.Vector3
+	// massDisplayPos is the position of the weight in world space, for rendering.
+	massDisplayPos math64.Vector3
+}
+
+// New builds the bridge with a weight of the given mass starting at its near end.
+func New(extraMass float64) *Sim {
+	// Each support, cable and rod generates at most one contact per frame.
+	sim := &Sim{
+		world:     physics.NewParticleWorld(particleCount+10+6, 0),
+		extraMass: extraMass,
+		massPos:   math64.NewVector3(0.0, 0.0, 0.5),
+	}
+
+	for i := range sim.particles {
+		p := &sim.particles[i]
+		p.Position = math64.NewVector3(float64(i/2)*2.0-5.0, 4.0, float64(i%2)*2.0-1.0)
+		p.Acceleration = math64.NewVector3(0.0, gravity, 0.0) // Effect of gravity
+		p.Damping = 0.9
+		p.SetMass(baseMass)
+		sim.world.AddParticle(p)
+	}
+
+	// Supports hang each particle from an anchor above it. The cables are shorter towards the
+	// ends of the bridge, giving it its sag.
+	for i := range sim.particles {
+		anchor := math64.NewVector3(float64(i/2)*2.2-5.5, 6.0, float64(i%2)*1.6-0.8)
+		var length float64
+		if i < 6 {
+			length = float64(i/2)*0.5 + 3.0
+		} else {
+			length = 5.5 - float64(i/2)*0.5
+		}
+		support := physics.NewParticleCableConstraint(&sim.particles[i], anchor, length, 0.5)
+		sim.supports = append(sim.supports, support)
+		sim.world.AddContactGenerator(support)
+	}
+
+	// Cables join each particle to the next one along its side of the walkway.
+	for i := 0; i < particleCount-2; i++ {
+		cable := physics.NewParticleCable(&sim.particles[i], &sim.particles[i+2], 1.9, 0.3)
+		sim.cables = append(sim.cables, cable)
+		sim.world.AddContactGenerator(cable)
+	}
+
+	// Rods act as the planks, holding both sides of the walkway apart.
+	for i := 0; i < particleCount/2; i++ {
+		rod := physics.NewParticleRod(&sim.particles[i*2], &sim.particles[i*2+1], 2.0)
+		sim.rods = append(sim.rods, rod)
+		sim.world.AddContactGenerator(rod)
+	}
+
+	sim.updateAdditionalMass()
+
+	return sim
+}
+
+// updateAdditionalMass spreads the weight's mass over the four walkway particles surrounding it,
+// in proportion to how close it is to each one.
+func (sim *Sim) updateAdditionalMass() {
+	for i := range sim.particles {
+		sim.particles[i].SetMass(baseMass)
+	}
+
+	// Find the coordinates of the mass as an index and proportion.
+	x := int(sim.massPos.X)
+	xp := math.Mod(sim.massPos.X, 1.0)
+	if x < 0 {
+		x, xp = 0, 0
+	}
+	if x >= int(maxMassX) {
+		x, xp = int(maxMassX), 0
+	}
+
+	z := int(sim.massPos.Z)
+	zp := math.Mod(sim.massPos.Z, 1.0)
+	if z < 0 {
+		z, zp = 0, 0
+	}
+	if z >= int(maxMassZ) {
+		z, zp = int(maxMassZ), 0
+	}
+
+	sim.massDisplayPos = math64.Vector3{}
+	sim.addMass(x*2+z, (1-xp)*(1-zp))
+	if xp > 0 {
+		sim.addMass(x*2+z+2, xp*(1-zp))
+		if zp > 0 {
+			sim.addMass(x*2+z+3, xp*zp)
+		}
+	}
+	if zp > 0 {
+		sim.addMass(x*2+z+1, (1-xp)*zp)
+	}
+}
+
+// addMass gives the particle at index i the given share of the weight's mass.
+func (sim *Sim) addMass(i int, share float64) {
+	p := &sim.particles[i]
+	p.SetMass(baseMass + sim.extraMass*share)
+	sim.massDisplayPos.ScaleAdd(p.Position, share)
+}
+
+// MoveMass moves the weight by the given offset in walkway coordinates, keeping it on the bridge.
+func (sim *Sim) MoveMass(dx, dz float64) {
+	sim.massPos.X = math.Max(0.0, math.Min(maxMassX, sim.massPos.X+dx))
+	sim.massPos.Z = math.Max(0.0, math.Min(maxMassZ, sim.massPos.Z+dz))
+}
+
+// Step advances the simulation by a single fixed time step.
+func (sim *Sim) Step() error {
+	sim.updateAdditionalMass()
+	sim.world.StartFrame()
+	return sim.world.RunPhysics(TimeStep)
+}
+
+// Particles returns the walkway's particles. The slice shares the simulation's storage, so it
+// reflects every step.
+func (sim *Sim) Particles() []physics.Particle {
+	return sim.particles[:]
+}
+
+// Supports returns the cables hanging the walkway from its anchors.
+func (sim *Sim) Supports() []*physics.ParticleCableConstraint {
+	return sim.supports
+}
+
+// Cables returns the cables joining the walkway's particles end to end.
+func (sim *Sim) Cables() []*physics.ParticleCable {
+	return sim.cables
+}
+
+// Rods returns the rods acting as the walkway's planks.
+func (sim *Sim) Rods() []*physics.ParticleRod {
+	return sim.rods
+}
+
+// MassPosition returns the weight's position in world space, as of the last step.
+func (sim *Sim) MassPosition() math64.Vector3 {
+	return sim.massDisplayPos
+}
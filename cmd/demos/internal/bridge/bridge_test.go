@@ -0,0 +1,54 @@
+package bridge
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimSettlesUnderLoad(t *testing.T) {
+	tests := []struct {
+		name       string
+		extraMass  float64
+		massX      float64
+		massZ      float64
+		stepsToRun int
+	}{
+		{"unloaded", 0, 0, 0, 2400},
+		{"near end", 10, 0, 0.5, 2400},
+		{"middle", 10, 2.5, 0.5, 2400},
+		{"heavy middle", 50, 2.5, 1, 2400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := New(tt.extraMass)
+			sim.MoveMass(tt.massX, tt.massZ)
+
+			for i := 0; i < tt.stepsToRun; i++ {
+				if err := sim.Step(); err != nil {
+					t.Fatalf("step %d: %v", i, err)
+				}
+			}
+
+			for i := range sim.particles {
+				p := &sim.particles[i]
+				if !p.Position.IsFinite() || !p.Velocity.IsFinite() {
+					t.Fatalf("particle %d exploded: position %v, velocity %v", i, p.Position, p.Velocity)
+				}
+				// A settled bridge only jitters, and hanging below their anchors.
+				if speed := p.Velocity.Magnitude(); speed > 2 {
+					t.Errorf("particle %d still moving at %v after %d steps", i, speed, tt.stepsToRun)
+				}
+				if p.Position.Y > 6 || p.Position.Y < -10 {
+					t.Errorf("particle %d at height %v, want it hanging from the supports", i, p.Position.Y)
+				}
+			}
+
+			for i, rod := range sim.rods {
+				length := rod.Particles[0].Position.Distance(rod.Particles[1].Position)
+				if math.Abs(length-rod.Length) > 0.05 {
+					t.Errorf("rod %d has length %v, want %v", i, length, rod.Length)
+				}
+			}
+		})
+	}
+}
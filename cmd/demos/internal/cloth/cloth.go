@@ -0,0 +1,129 @@
+// Package cloth simulates the cloth demo: a grid of particles joined by springs, hanging from its
+// first row. It doesn't depend on raylib, so it builds and tests without a display.
+package cloth
+
+import (
+	"math"
+
+	"github.com/user54778/cyclone/internal/math64"
+	"github.com/user54778/cyclone/internal/physics"
+)
+
+// TimeStep is the fixed duration of a single physics step. Frame durations are split into
+// steps of this size so the springs stay stable at low frame rates.
+const TimeStep = 1.0 / 240.0
+
+// Link records a pair of connected particle indices so they can be drawn.
+type Link struct {
+	A, B int
+}
+
+// Sim holds a grid of particles connected by springs, simulated by a ParticleWorld.
+type Sim struct {
+	world     *physics.ParticleWorld
+	particles []physics.Particle // Row-major grid of rows*cols particles.
+	links     []Link
+	rows      int
+	cols      int
+}
+
+// New builds a rows x cols grid of particles spaced spacing apart on the X-Z plane.
+// Neighbours are joined by structural springs, with a cable limiting how far each one can stretch.
+// Shear springs join diagonal neighbours, and bend springs join particles two apart. The first
+// row is pinned in place with infinite mass.
+func New(rows, cols int, spacing, springConstant float64, shear, bend bool) *Sim {
+	sim := &Sim{
+		// Every structural link can generate at most one cable contact per frame.
+		world:     physics.NewParticleWorld(2*rows*cols, 0),
+		particles: make([]physics.Particle, rows*cols),
+		rows:      rows,
+		cols:      cols,
+	}
+
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			p := &sim.particles[sim.index(r, c)]
+			p.Position = math64.NewVector3((float64(c)-float64(cols-1)/2)*spacing, 10.0, float64(r)*spacing)
+			p.Acceleration = math64.NewVector3(0.0, -9.81, 0.0) // Effect of gravity
+			p.Damping = 0.5
+			if r == 0 {
+				p.SetInverseMass(0.0) // Pinned anchor; infinite mass.
+			} else {
+				p.SetMass(0.5)
+			}
+			sim.world.AddParticle(p)
+		}
+	}
+
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			// Structural springs to the right and below, with a cable so the cloth can't over-stretch.
+			if c+1 < cols {
+				sim.connect(r, c, r, c+1, springConstant, spacing, true)
+			}
+			if r+1 < rows {
+				sim.connect(r, c, r+1, c, springConstant, spacing, true)
+			}
+
+			// Shear springs across both diagonals.
+			if shear && r+1 < rows {
+				if c+1 < cols {
+					sim.connect(r, c, r+1, c+1, springConstant, spacing*math.Sqrt2, false)
+				}
+				if c > 0 {
+					sim.connect(r, c, r+1, c-1, springConstant, spacing*math.Sqrt2, false)
+				}
+			}
+
+			// Bend springs skipping a particle, which resist folding.
+			if bend {
+				if c+2 < cols {
+					sim.connect(r, c, r, c+2, springConstant, 2*spacing, false)
+				}
+				if r+2 < rows {
+					sim.connect(r, c, r+2, c, springConstant, 2*spacing, false)
+				}
+			}
+		}
+	}
+
+	return sim
+}
+
+// index returns the index into the particle slice for the given row and column.
+func (sim *Sim) index(r, c int) int {
+	return r*sim.cols + c
+}
+
+// connect joins two particles with a spring acting on both ends, and optionally a cable that
+// stops the spring stretching past 110% of its rest length.
+func (sim *Sim) connect(r1, c1, r2, c2 int, springConstant, restLength float64, cable bool) {
+	i, j := sim.index(r1, c1), sim.index(r2, c2)
+	a, b := &sim.particles[i], &sim.particles[j]
+
+	sim.world.Registry.AddForce(a, physics.NewSpringForceGenerator(b, springConstant, restLength))
+	sim.world.Registry.AddForce(b, physics.NewSpringForceGenerator(a, springConstant, restLength))
+
+	if cable {
+		sim.world.AddContactGenerator(physics.NewParticleCable(a, b, restLength*1.1, 0.0))
+	}
+
+	sim.links = append(sim.links, Link{A: i, B: j})
+}
+
+// Step advances the simulation by a single fixed time step.
+func (sim *Sim) Step() error {
+	sim.world.StartFrame()
+	return sim.world.RunPhysics(TimeStep)
+}
+
+// Particles returns the cloth's particles, row by row. The slice is the simulation's own, so it
+// reflects every step.
+func (sim *Sim) Particles() []physics.Particle {
+	return sim.particles
+}
+
+// Links returns the pairs of particles joined by springs.
+func (sim *Sim) Links() []Link {
+	return sim.links
+}
@@ -0,0 +1,52 @@
+package cloth
+
+import (
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+func TestSimSmoke(t *testing.T) {
+	tests := []struct {
+		name        string
+		rows, cols  int
+		shear, bend bool
+		steps       int
+	}{
+		{"structural only", 6, 6, false, false, 480},
+		{"shear", 6, 6, true, false, 480},
+		{"shear and bend", 8, 5, true, true, 480},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := New(tt.rows, tt.cols, 0.5, 50, tt.shear, tt.bend)
+
+			pinned := make([]math64.Vector3, tt.cols)
+			for c := range pinned {
+				pinned[c] = sim.particles[sim.index(0, c)].Position
+			}
+
+			for i := 0; i < tt.steps; i++ {
+				if err := sim.Step(); err != nil {
+					t.Fatalf("step %d: %v", i, err)
+				}
+			}
+
+			for i, p := range sim.particles {
+				if !p.Position.IsFinite() || !p.Velocity.IsFinite() {
+					t.Fatalf("particle %d has position %v and velocity %v after %d steps", i, p.Position, p.Velocity, tt.steps)
+				}
+			}
+			for c, want := range pinned {
+				if got := sim.particles[sim.index(0, c)].Position; got != want {
+					t.Errorf("pinned particle %d moved from %v to %v", c, want, got)
+				}
+			}
+
+			// The free rows should have fallen below the pinned row.
+			if bottom := sim.particles[sim.index(tt.rows-1, 0)].Position.Y; bottom >= pinned[0].Y {
+				t.Errorf("bottom row at y = %v, want below the pinned row at %v", bottom, pinned[0].Y)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+// Package fireworks simulates the fireworks demo: rockets that burst into sparks, recycled through
+// a particle pool. It doesn't depend on raylib, so it builds and tests without a display.
+package fireworks
+
+import (
+	"math/rand"
+
+	"github.com/user54778/cyclone/internal/math64"
+	"github.com/user54778/cyclone/internal/physics"
+)
+
+// Type represents the stage of a firework.
+type Type int
+
+const (
+	Rocket Type = iota // Launched from the ground; bursts into sparks when its fuse runs out.
+	Spark              // Thrown out by a burst; fades when its fuse runs out.
+)
+
+// TimeStep is the fixed duration of a single physics step.
+const TimeStep = 1.0 / 60.0
+
+// PaletteSize is the number of colors a rocket is picked from. A firework's Color indexes a
+// palette of this size.
+const PaletteSize = 6
+
+// Firework is a type to represent a single live firework record.
+type Firework struct {
+	Particle *physics.Particle // Every firework is a particle owned by the world.
+	Type     Type
+	Color    int // Index into the palette. Sparks take the color of the rocket they burst from.
+
+	fuse float64 // Seconds left before the firework bursts or fades.
+}
+
+// Sim holds the live fireworks and the world simulating them.
+type Sim struct {
+	world     *physics.ParticleWorld
+	fireworks []Firework
+	rng       *rand.Rand
+
+	sparksPerBurst int
+	// launchInterval is the time between automatic launches; zero or less disables them.
+	launchInterval float64
+	sinceLaunch    float64
+}
+
+// New creates a fireworks simulation whose world recycles particles through a pool sized for
+// poolSize live fireworks.
+func New(poolSize, sparksPerBurst int, launchInterval float64, seed int64) *Sim {
+	world := physics.NewParticleWorld(0, 0)
+	world.Pool = physics.NewParticlePool(poolSize)
+
+	return &Sim{
+		world:          world,
+		rng:            rand.New(rand.NewSource(seed)),
+		sparksPerBurst: sparksPerBurst,
+		launchInterval: launchInterval,
+	}
+}
+
+// ActiveParticles returns the number of particles currently live in the world.
+func (sim *Sim) ActiveParticles() int {
+	return len(sim.world.Particles())
+}
+
+// Fireworks returns the live fireworks. The slice is only valid until the next Step or Launch.
+func (sim *Sim) Fireworks() []Firework {
+	return sim.fireworks
+}
+
+// Launch fires a rocket from a random point on the ground. The launch itself is a single impulse.
+func (sim *Sim) Launch() {
+	p := sim.world.SpawnParticle()
+	p.Position = math64.NewVector3(sim.rng.Float64()*10.0-5.0, 0.0, sim.rng.Float64()*4.0-2.0)
+	p.Acceleration = math64.NewVector3(0.0, -9.81, 0.0) // Effect of gravity
+	p.Damping = 0.99
+	p.SetMass(1.0)
+
+	impulse := math64.NewVector3(sim.rng.Float64()*4.0-2.0, 18.0+sim.rng.Float64()*4.0, sim.rng.Float64()*2.0-1.0)
+	sim.world.Registry.AddForce(p, physics.NewImpulseForceGenerator(impulse))
+
+	sim.fireworks = append(sim.fireworks, Firework{
+		Particle: p,
+		Type:     Rocket,
+		Color:    sim.rng.Intn(PaletteSize),
+		fuse:     1.2 + sim.rng.Float64()*0.8,
+	})
+}
+
+// burst spawns sparks around the rocket's position and registers them with a single explosion,
+// which throws them out radially.
+func (sim *Sim) burst(rocket Firework) []Firework {
+	center := rocket.Particle.Position
+	velocity := rocket.Particle.Velocity
+	explosion := physics.NewExplosionForceGenerator(center, 1.0, 1.5)
+
+	sparks := make([]Firework, 0, sim.sparksPerBurst)
+	for i := 0; i < sim.sparksPerBurst; i++ {
+		// Offset each spark slightly so the explosion has a direction to push it in.
+		direction := math64.NewVector3(sim.rng.NormFloat64(), sim.rng.NormFloat64(), sim.rng.NormFloat64()).Normalize()
+
+		p := sim.world.SpawnParticle()
+		p.Position = center.ScaleAddCopy(direction, 0.05)
+		p.Velocity = velocity.ScaleCopy(0.5) // Sparks carry on with some of the rocket's momentum.
+		p.Acceleration = math64.NewVector3(0.0, -4.0, 0.0)
+		p.Damping = 0.5
+		p.SetMass(0.2)
+		sim.world.Registry.AddForce(p, explosion)
+
+		sparks = append(sparks, Firework{
+			Particle: p,
+			Type:     Spark,
+			Color:    rocket.Color,
+			fuse:     1.0 + sim.rng.Float64(),
+		})
+	}
+
+	return sparks
+}
+
+// Step advances the simulation by a single fixed time step, launching, bursting and retiring
+// fireworks as their fuses run out. Fuses burn down even if the physics step fails, and the
+// error is returned once they have.
+func (sim *Sim) Step() error {
+	if sim.launchInterval > 0 {
+		sim.sinceLaunch += TimeStep
+		if sim.sinceLaunch >= sim.launchInterval {
+			sim.sinceLaunch -= sim.launchInterval
+			sim.Launch()
+		}
+	}
+
+	sim.world.StartFrame()
+	err := sim.world.RunPhysics(TimeStep)
+
+	live := sim.fireworks[:0]
+	var spawned []Firework
+	for _, fw := range sim.fireworks {
+		fw.fuse -= TimeStep
+		if fw.fuse > 0 && fw.Particle.Position.Y >= 0 {
+			live = append(live, fw)
+			continue
+		}
+
+		// Only a rocket that runs out of fuse in the air bursts; one that hits the ground is a dud.
+		if fw.Type == Rocket && fw.fuse <= 0 {
+			spawned = append(spawned, sim.burst(fw)...)
+		}
+		sim.world.DespawnParticle(fw.Particle)
+	}
+	sim.fireworks = append(live, spawned...)
+
+	return err
+}
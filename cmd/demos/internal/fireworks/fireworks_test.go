@@ -0,0 +1,45 @@
+package fireworks
+
+import "testing"
+
+func TestSimLifecycle(t *testing.T) {
+	tests := []struct {
+		name           string
+		rockets        int
+		sparksPerBurst int
+	}{
+		{"single rocket", 1, 10},
+		{"several rockets", 3, 20},
+		{"pool smaller than the burst", 2, 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := New(8, tt.sparksPerBurst, 0, 1)
+			for i := 0; i < tt.rockets; i++ {
+				sim.Launch()
+			}
+			if got := sim.ActiveParticles(); got != tt.rockets {
+				t.Fatalf("%d particles live after launching, want %d", got, tt.rockets)
+			}
+
+			// Every rocket bursts within 2s and every spark fades within 2s of that.
+			peak := 0
+			for i := 0; i < int(5/TimeStep); i++ {
+				if err := sim.Step(); err != nil {
+					t.Fatalf("step %d: %v", i, err)
+				}
+				peak = max(peak, sim.ActiveParticles())
+			}
+
+			if want := tt.rockets * tt.sparksPerBurst; peak != want {
+				t.Errorf("peak of %d live particles, want %d once every rocket has burst", peak, want)
+			}
+			if got := sim.ActiveParticles(); got != 0 {
+				t.Errorf("%d particles still live after every fuse ran out, want 0", got)
+			}
+			if len(sim.fireworks) != 0 {
+				t.Errorf("%d fireworks still tracked, want 0", len(sim.fireworks))
+			}
+		})
+	}
+}
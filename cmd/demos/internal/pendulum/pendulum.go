@@ -0,0 +1,64 @@
+// Package pendulum simulates the pendulum demo: a bob swinging from a fixed anchor on a rigid rod.
+// It doesn't depend on raylib, so it builds and tests without a display.
+package pendulum
+
+import (
+	"math"
+
+	"github.com/user54778/cyclone/internal/math64"
+	"github.com/user54778/cyclone/internal/physics"
+)
+
+const (
+	// TimeStep is the fixed duration of a single physics step.
+	TimeStep = 1.0 / 120.0
+
+	bobMass = 1.0   // Mass of the pendulum's bob, in kg.
+	gravity = -9.81 // Acceleration due to gravity, in m/s^2.
+)
+
+// Sim holds a single bob hung from a fixed anchor by a rigid rod, swinging under gravity.
+type Sim struct {
+	world *physics.ParticleWorld
+	bob   physics.Particle
+	rod   *physics.ParticleRodConstraint
+}
+
+// New builds a pendulum of the given length, released from rest at the given angle from the
+// vertical, in degrees.
+func New(length, angle float64) *Sim {
+	// The rod generates at most one contact per frame.
+	sim := &Sim{
+		world: physics.NewParticleWorld(1, 0),
+	}
+
+	anchor := math64.NewVector3(0.0, length+1.0, 0.0)
+	theta := math64.DegToRad(angle)
+
+	sim.bob.Position = anchor.AddCopy(math64.NewVector3(length*math.Sin(theta), -length*math.Cos(theta), 0.0))
+	sim.bob.Acceleration = math64.NewVector3(0.0, gravity, 0.0) // Effect of gravity
+	sim.bob.Damping = 0.999
+	sim.bob.SetMass(bobMass)
+	sim.world.AddParticle(&sim.bob)
+
+	sim.rod = physics.NewParticleRodConstraint(&sim.bob, anchor, length)
+	sim.world.AddContactGenerator(sim.rod)
+
+	return sim
+}
+
+// Step advances the simulation by a single fixed time step.
+func (sim *Sim) Step() error {
+	sim.world.StartFrame()
+	return sim.world.RunPhysics(TimeStep)
+}
+
+// Bob returns the pendulum's bob, as of the last step.
+func (sim *Sim) Bob() *physics.Particle {
+	return &sim.bob
+}
+
+// Anchor returns the fixed point the rod hangs from.
+func (sim *Sim) Anchor() math64.Vector3 {
+	return sim.rod.Anchor
+}
@@ -0,0 +1,39 @@
+package pendulum
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimKeepsRodLength(t *testing.T) {
+	tests := []struct {
+		name   string
+		length float64
+		angle  float64
+	}{
+		{"default", 4, 60},
+		{"short", 1, 30},
+		{"long horizontal", 8, 90},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := New(tt.length, tt.angle)
+
+			// Ten seconds of swinging.
+			for i := 0; i < 1200; i++ {
+				if err := sim.Step(); err != nil {
+					t.Fatalf("step %d: %v", i, err)
+				}
+
+				length := sim.bob.Position.Distance(sim.rod.Anchor)
+				if math.Abs(length-tt.length) > 1e-9 {
+					t.Fatalf("step %d: rod has length %v, want %v", i, length, tt.length)
+				}
+			}
+			// The bob swings rather than coming to rest.
+			if sim.bob.Velocity.Magnitude() == 0 {
+				t.Error("bob is at rest after ten seconds")
+			}
+		})
+	}
+}
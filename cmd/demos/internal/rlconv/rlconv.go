@@ -0,0 +1,59 @@
+// Package rlconv converts vectors between the physics engine and raylib, so every demo maps
+// coordinates the same way.
+//
+// Both the engine and raylib use a right-handed coordinate system with Y pointing up, so by
+// default vectors map across directly. A Converter can flip the Z axis for scenes authored in a
+// left-handed system.
+package rlconv
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+// Handedness represents the handedness of the coordinate system engine vectors are expressed in.
+type Handedness int
+
+const (
+	RightHanded Handedness = iota // Matches raylib; vectors map across directly.
+	LeftHanded                    // Z points the opposite way to raylib's, and is flipped on conversion.
+)
+
+// Converter converts vectors between engine and raylib coordinates.
+type Converter struct {
+	Handedness Handedness
+}
+
+// zSign returns the factor Z components are multiplied by when converting.
+func (c Converter) zSign() float64 {
+	if c.Handedness == LeftHanded {
+		return -1
+	}
+	return 1
+}
+
+// ToRay converts an engine vector to a raylib vector.
+//
+// raylib stores components as float32, so precision beyond float32 is lost. Vectors whose
+// components are exactly representable as float32 round-trip through FromRay unchanged.
+func (c Converter) ToRay(v math64.Vector3) rl.Vector3 {
+	return rl.Vector3{X: float32(v.X), Y: float32(v.Y), Z: float32(v.Z * c.zSign())}
+}
+
+// FromRay converts a raylib vector to an engine vector. It is the inverse of ToRay.
+func (c Converter) FromRay(v rl.Vector3) math64.Vector3 {
+	return math64.NewVector3(float64(v.X), float64(v.Y), float64(v.Z)*c.zSign())
+}
+
+// Default is the converter used by the package-level ToRay and FromRay.
+var Default = Converter{Handedness: RightHanded}
+
+// ToRay converts an engine vector to a raylib vector using the Default converter.
+func ToRay(v math64.Vector3) rl.Vector3 {
+	return Default.ToRay(v)
+}
+
+// FromRay converts a raylib vector to an engine vector using the Default converter.
+func FromRay(v rl.Vector3) math64.Vector3 {
+	return Default.FromRay(v)
+}
@@ -0,0 +1,51 @@
+package rlconv
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+func TestConverterRoundTrip(t *testing.T) {
+	// Components are exactly representable as float32, so they survive the narrowing to raylib.
+	vectors := []math64.Vector3{
+		{},
+		math64.NewVector3(1, 2, 3),
+		math64.NewVector3(-0.5, 1.25, -200),
+		math64.NewVector3(1024, -0.125, 0.75),
+	}
+	tests := []struct {
+		name      string
+		converter Converter
+		wantZSign float32
+	}{
+		{"right-handed", Converter{Handedness: RightHanded}, 1},
+		{"left-handed", Converter{Handedness: LeftHanded}, -1},
+		{"default", Default, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, v := range vectors {
+				ray := tt.converter.ToRay(v)
+				want := rl.Vector3{X: float32(v.X), Y: float32(v.Y), Z: tt.wantZSign * float32(v.Z)}
+				if ray != want {
+					t.Errorf("ToRay(%v) = %v, want %v", v, ray, want)
+				}
+				if got := tt.converter.FromRay(ray); got != v {
+					t.Errorf("FromRay(ToRay(%v)) = %v, want it unchanged", v, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPackageConvertersUseDefault(t *testing.T) {
+	v := math64.NewVector3(1, 2, 3)
+	if got, want := ToRay(v), Default.ToRay(v); got != want {
+		t.Errorf("ToRay(%v) = %v, want %v", v, got, want)
+	}
+	if got := FromRay(ToRay(v)); got != v {
+		t.Errorf("FromRay(ToRay(%v)) = %v, want it unchanged", v, got)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/user54778/cyclone/cmd/demos/internal/pendulum"
+	"github.com/user54778/cyclone/cmd/demos/internal/rlconv"
+)
+
+// PendulumDemo runs the pendulum simulation in real time and draws it.
+type PendulumDemo struct {
+	*pendulum.Sim
+	// accumulator holds frame time not yet consumed by a fixed physics step.
+	accumulator float64
+}
+
+// Update advances the simulation by the last frame's duration, in fixed steps.
+func (demo *PendulumDemo) Update() {
+	duration := rl.GetFrameTime() // Last frame's duration in seconds
+	if duration <= 0.0 {
+		return
+	}
+
+	demo.accumulator += float64(duration)
+	for demo.accumulator >= pendulum.TimeStep {
+		if err := demo.Step(); err != nil {
+			rl.TraceLog(rl.LogError, "pendulum physics: %v", err)
+		}
+		demo.accumulator -= pendulum.TimeStep
+	}
+}
+
+// Render draws the anchor, the rod and the bob.
+func (demo *PendulumDemo) Render() {
+	anchor := rlconv.ToRay(demo.Anchor())
+	bob := rlconv.ToRay(demo.Bob().Position)
+
+	rl.DrawCube(anchor, 0.2, 0.2, 0.2, rl.DarkGray)
+	rl.DrawLine3D(anchor, bob, rl.DarkBlue)
+	rl.DrawSphereEx(bob, 0.25, 20, 10, rl.Red)
+
+	// Draw a flattened sphere representing the bob's shadow.
+	gray := rl.NewColor(50, 50, 50, 128)
+	rl.DrawSphereWires(rl.Vector3{X: bob.X, Y: 0, Z: bob.Z}, 0.25, 5, 4, gray)
+}
+
+func main() {
+	var length, angle float64
+	flag.Float64Var(&length, "length", 4.0, "length of the pendulum's rod")
+	flag.Float64Var(&angle, "angle", 60.0, "starting angle of the pendulum from the vertical, in degrees")
+
+	flag.Parse()
+
+	demo := &PendulumDemo{Sim: pendulum.New(length, angle)}
+
+	rl.InitWindow(1280, 720, "pendulum")
+	defer rl.CloseWindow()
+
+	camera := &rl.Camera{}
+	camera.Position = rl.NewVector3(0.0, 4.0, 12.0)
+	camera.Target = rl.NewVector3(0.0, 3.0, 0.0) // Camera looking at point
+	camera.Up = rl.NewVector3(0.0, 1.0, 0.0)     // Where it rotates over the y-unit vector
+	camera.Fovy = 45.0                           // How close I am
+
+	rl.SetTargetFPS(60)
+
+	for !rl.WindowShouldClose() {
+		// Game logic
+		demo.Update()
+
+		// Rendering
+		rl.BeginDrawing()
+		rl.ClearBackground(rl.LightGray)
+
+		rl.BeginMode3D(*camera)
+		rl.DrawGrid(20, 1.0)
+		demo.Render()
+		rl.EndMode3D()
+
+		rl.DrawFPS(10, 10)
+
+		rl.EndDrawing()
+	}
+}
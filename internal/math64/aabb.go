@@ -0,0 +1,41 @@
+package math64
+
+// AABB represents an axis-aligned bounding box, spanning from Min to Max on every axis.
+type AABB struct {
+	Min, Max Vector3
+}
+
+// NewAABB creates an AABB with the given corners.
+func NewAABB(min, max Vector3) AABB {
+	return AABB{
+		Min: min,
+		Max: max,
+	}
+}
+
+// Contains reports whether the point lies inside the box, or on its surface.
+func (b AABB) Contains(point Vector3) bool {
+	return point.X >= b.Min.X && point.X <= b.Max.X &&
+		point.Y >= b.Min.Y && point.Y <= b.Max.Y &&
+		point.Z >= b.Min.Z && point.Z <= b.Max.Z
+}
+
+// Clamp returns the point inside the box closest to the given point.
+func (b AABB) Clamp(point Vector3) Vector3 {
+	return Vector3{
+		X: clamp(point.X, b.Min.X, b.Max.X),
+		Y: clamp(point.Y, b.Min.Y, b.Max.Y),
+		Z: clamp(point.Z, b.Min.Z, b.Max.Z),
+	}
+}
+
+// clamp limits f to the range [min, max].
+func clamp(f, min, max float64) float64 {
+	if f < min {
+		return min
+	}
+	if f > max {
+		return max
+	}
+	return f
+}
@@ -0,0 +1,58 @@
+package math64
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math"
+	"testing"
+)
+
+func TestVector3GobRoundTrip(t *testing.T) {
+	vectors := []Vector3{
+		{},
+		NewVector3(1, -2, 3.5),
+		NewVector3(math.Pi, 1.0/3, -math.SmallestNonzeroFloat64),
+		NewVector3(math.Inf(1), math.Inf(-1), math.MaxFloat64),
+	}
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	for _, v := range vectors {
+		if err := enc.Encode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dec := gob.NewDecoder(&buf)
+	for _, want := range vectors {
+		var got Vector3
+		if err := dec.Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("decoded %v, want %v exactly", got, want)
+		}
+	}
+}
+
+func TestVector3GobNaN(t *testing.T) {
+	data, err := NewVector3(math.NaN(), 0, 0).GobEncode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Vector3
+	if err := got.GobDecode(data); err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsNaN(got.X) {
+		t.Errorf("decoded X = %v, want NaN", got.X)
+	}
+}
+
+func TestVector3GobDecodeWrongSize(t *testing.T) {
+	for _, n := range []int{0, 8, vectorGobSize - 1, vectorGobSize + 1} {
+		var v Vector3
+		if err := v.GobDecode(make([]byte, n)); err == nil {
+			t.Errorf("GobDecode of %d bytes succeeded, want an error", n)
+		}
+	}
+}
@@ -8,6 +8,17 @@ const (
 	radDegRatio = 180.0 / Pi
 )
 
+// Epsilon is the engine-wide default tolerance for floating-point comparisons. Values whose
+// magnitude is below Epsilon are treated as zero. Functions with an Epsilon suffix, such as
+// CrossEpsilon, accept their own tolerance for precision-sensitive callers.
+const Epsilon = 1e-9
+
+// ApproxEqual reports whether a and b differ by no more than epsilon. Pass Epsilon for the
+// engine-wide default tolerance.
+func ApproxEqual(a, b, epsilon float64) bool {
+	return math.Abs(a-b) <= epsilon
+}
+
 // DegToRad converts a scalar from degrees to radians.
 func DegToRad(degrees float64) float64 {
 	return degrees * degRadRatio
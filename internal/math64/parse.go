@@ -0,0 +1,51 @@
+package math64
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrVectorSyntax is returned by ParseVector3 for a string that isn't a vector.
+var ErrVectorSyntax = errors.New("invalid vector syntax")
+
+// ParseVector3 parses a vector from a string of three comma-separated numbers, "x,y,z", such as
+// one read from a config file. Whitespace around the components is ignored, and the whole vector
+// may be wrapped in parentheses: "1,2,3", "1, 2, 3" and "( 1, 2, 3 )" are all the same vector.
+//
+// Errors wrap ErrVectorSyntax.
+func ParseVector3(s string) (Vector3, error) {
+	trimmed := strings.TrimSpace(s)
+	if strings.HasPrefix(trimmed, "(") != strings.HasSuffix(trimmed, ")") {
+		return Vector3{}, fmt.Errorf("%w: unbalanced parentheses in %q", ErrVectorSyntax, s)
+	}
+	trimmed = strings.TrimSuffix(strings.TrimPrefix(trimmed, "("), ")")
+
+	fields := strings.Split(trimmed, ",")
+	if len(fields) != 3 {
+		return Vector3{}, fmt.Errorf("%w: want 3 components, got %d in %q", ErrVectorSyntax, len(fields), s)
+	}
+
+	var components [3]float64
+	for i, field := range fields {
+		f, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return Vector3{}, fmt.Errorf("%w: component %d of %q: %w", ErrVectorSyntax, i, s, err)
+		}
+		components[i] = f
+	}
+
+	return NewVector3(components[0], components[1], components[2]), nil
+}
+
+// Format returns the canonical string form of the vector, "x,y,z", which ParseVector3 reads back
+// exactly. Each component uses the shortest representation that round-trips.
+func (v Vector3) Format() string {
+	return formatComponent(v.X) + "," + formatComponent(v.Y) + "," + formatComponent(v.Z)
+}
+
+// formatComponent formats a single vector component for Format.
+func formatComponent(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
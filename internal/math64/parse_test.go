@@ -0,0 +1,74 @@
+package math64
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseVector3(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Vector3
+	}{
+		{"plain", "1,2,3", NewVector3(1, 2, 3)},
+		{"spaces", " 1.5 , -2 ,\t3e2 ", NewVector3(1.5, -2, 300)},
+		{"parentheses", "(0,-9.81,0)", NewVector3(0, -9.81, 0)},
+		{"parentheses and spaces", "  ( -1, 0.25 , 7 )  ", NewVector3(-1, 0.25, 7)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVector3(tt.input)
+			if err != nil {
+				t.Fatalf("ParseVector3(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseVector3(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVector3Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty", ""},
+		{"two components", "1,2"},
+		{"four components", "1,2,3,4"},
+		{"non-numeric", "1,two,3"},
+		{"empty component", "1,,3"},
+		{"unbalanced parentheses", "(1,2,3"},
+		{"space separated", "1 2 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := ParseVector3(tt.input); !errors.Is(err, ErrVectorSyntax) {
+				t.Errorf("ParseVector3(%q) = %v, %v, want ErrVectorSyntax", tt.input, got, err)
+			}
+		})
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	tests := []struct {
+		v    Vector3
+		want string
+	}{
+		{NewVector3(1, 2, 3), "1,2,3"},
+		{NewVector3(0, -9.81, 0), "0,-9.81,0"},
+		{NewVector3(0.1, 1e-12, -2.5e20), "0.1,1e-12,-2.5e+20"},
+		{NewVector3(math.Pi, 1.0/3, math.MaxFloat64), ""},
+	}
+	for _, tt := range tests {
+		s := tt.v.Format()
+		if tt.want != "" && s != tt.want {
+			t.Errorf("%v.Format() = %q, want %q", tt.v, s, tt.want)
+		}
+		if got, err := ParseVector3(s); err != nil || got != tt.v {
+			t.Errorf("ParseVector3(%q) = %v, %v, want %v exactly", s, got, err, tt.v)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package math64
+
+import (
+	"math"
+	"math/rand"
+)
+
+// RandomDirection returns a unit vector pointing in a random direction, uniformly distributed
+// over the unit sphere. The random source is passed in so results can be made reproducible.
+//
+// The height z is uniform in [-1, 1] and the angle around the Z axis uniform in [0, 2pi), which by
+// Archimedes' hat-box theorem covers the sphere uniformly.
+func RandomDirection(r *rand.Rand) Vector3 {
+	z := 2*r.Float64() - 1
+	phi := 2 * Pi * r.Float64()
+	ring := math.Sqrt(1 - z*z)
+	return NewVector3(ring*math.Cos(phi), ring*math.Sin(phi), z)
+}
+
+// RandomInSphere returns a random point inside the sphere of the given radius about the origin,
+// uniformly distributed through its volume.
+func RandomInSphere(r *rand.Rand, radius float64) Vector3 {
+	// Volume grows with the cube of the distance, so the cube root keeps the density uniform.
+	distance := radius * math.Cbrt(r.Float64())
+	return RandomDirection(r).ScaleCopy(distance)
+}
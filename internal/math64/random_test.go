@@ -0,0 +1,93 @@
+package math64
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRandomDirection(t *testing.T) {
+	const samples = 100000
+
+	rng := rand.New(rand.NewSource(1))
+	var sum Vector3
+	var counts [3][2]int // Samples on the negative and positive side of each axis.
+	for i := 0; i < samples; i++ {
+		d := RandomDirection(rng)
+		if !ApproxEqual(d.Magnitude(), 1, 1e-12) {
+			t.Fatalf("RandomDirection() = %v with length %v, want a unit vector", d, d.Magnitude())
+		}
+		sum.Add(d)
+		for axis, c := range []float64{d.X, d.Y, d.Z} {
+			if c >= 0 {
+				counts[axis][1]++
+			} else {
+				counts[axis][0]++
+			}
+		}
+	}
+
+	// A uniform distribution has a mean of zero, with a standard error of sqrt(1/3/samples), about
+	// 0.002, on each axis.
+	if mean := sum.ScaleCopy(1.0 / samples); mean.Magnitude() > 0.01 {
+		t.Errorf("mean direction = %v, want near zero", mean)
+	}
+	for axis, c := range counts {
+		if frac := float64(c[1]) / samples; math.Abs(frac-0.5) > 0.01 {
+			t.Errorf("%v of directions point along +axis %d, want about half", frac, axis)
+		}
+	}
+}
+
+func TestRandomInSphere(t *testing.T) {
+	tests := []struct {
+		name   string
+		radius float64
+	}{
+		{"unit", 1},
+		{"large", 250},
+		{"small", 0.01},
+		{"zero", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const samples = 20000
+
+			rng := rand.New(rand.NewSource(1))
+			var sum Vector3
+			inner := 0
+			for i := 0; i < samples; i++ {
+				p := RandomInSphere(rng, tt.radius)
+				if d := p.Magnitude(); d > tt.radius {
+					t.Fatalf("RandomInSphere(%v) = %v, %v from the center", tt.radius, p, d)
+				}
+				if p.Magnitude() <= tt.radius/2 {
+					inner++
+				}
+				sum.Add(p)
+			}
+
+			if mean := sum.ScaleCopy(1.0 / samples); mean.Magnitude() > 0.02*tt.radius {
+				t.Errorf("mean point = %v, want near the center", mean)
+			}
+			// Points spread evenly through the volume, so an eighth fall within half the radius.
+			if tt.radius > 0 {
+				if frac := float64(inner) / samples; math.Abs(frac-0.125) > 0.01 {
+					t.Errorf("%v of points within half the radius, want about 1/8", frac)
+				}
+			}
+		})
+	}
+}
+
+func TestRandomDeterministic(t *testing.T) {
+	a, b := rand.New(rand.NewSource(42)), rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		if da, db := RandomDirection(a), RandomDirection(b); da != db {
+			t.Fatalf("sample %d: RandomDirection gave %v and %v from equal seeds", i, da, db)
+		}
+		if pa, pb := RandomInSphere(a, 3), RandomInSphere(b, 3); pa != pb {
+			t.Fatalf("sample %d: RandomInSphere gave %v and %v from equal seeds", i, pa, pb)
+		}
+	}
+}
@@ -1,10 +1,23 @@
 package math64
 
 import (
+	"errors"
 	"fmt"
 	"math"
 )
 
+// ErrParallelVectors is returned by helpers that require two non-parallel vectors.
+var ErrParallelVectors = errors.New("a and b are parallel")
+
+// ErrZeroVector is returned when a direction is requested from a vector with (near) zero magnitude.
+var ErrZeroVector = errors.New("vector has zero magnitude")
+
+// ErrNonFinite is returned by checked operations given a NaN or infinite input.
+var ErrNonFinite = errors.New("non-finite value")
+
+// ErrShortSlice is returned by FromSlice when given fewer than three components.
+var ErrShortSlice = errors.New("slice too short for a vector")
+
 // Vector3 represents a vector in the 3D cartesian vector space.
 type Vector3 struct {
 	X, Y, Z float64
@@ -28,7 +41,36 @@ func NewZeroVector3() Vector3 {
 	}
 }
 
+// Array returns the components of v as an array, in X, Y, Z order.
+func (v Vector3) Array() [3]float64 {
+	return [3]float64{v.X, v.Y, v.Z}
+}
+
+// FromArray creates a Vector3 from an array of its X, Y and Z components.
+func FromArray(a [3]float64) Vector3 {
+	return Vector3{a[0], a[1], a[2]}
+}
+
+// AppendTo appends the X, Y and Z components of v to dst and returns the extended slice, like
+// append. Appending many vectors to one slice packs them into a flat buffer, such as for a GPU upload.
+func (v Vector3) AppendTo(dst []float64) []float64 {
+	return append(dst, v.X, v.Y, v.Z)
+}
+
+// FromSlice creates a Vector3 from the first three elements of s, in X, Y, Z order, so a vector
+// can be read back from a flat buffer built with AppendTo with FromSlice(buf[3*i:]). Any further
+// elements are ignored. A slice with fewer than three elements returns an error wrapping ErrShortSlice.
+func FromSlice(s []float64) (Vector3, error) {
+	if len(s) < 3 {
+		return Vector3{}, fmt.Errorf("%w: want 3 components, got %d", ErrShortSlice, len(s))
+	}
+	return Vector3{s[0], s[1], s[2]}, nil
+}
+
 // Multiplies a Vector3 by a scalar k.
+//
+// Scale does no checking: a NaN or infinite k spreads to every component, and keeping the scalar
+// finite is the caller's responsibility.
 func (v *Vector3) Scale(k float64) {
 	v.X *= k
 	v.Y *= k
@@ -85,13 +127,113 @@ func (v Vector3) Invert() Vector3 {
 	}
 }
 
+// Abs returns a copy of the vector with the absolute value of each component.
+func (v Vector3) Abs() Vector3 {
+	return Vector3{
+		X: math.Abs(v.X),
+		Y: math.Abs(v.Y),
+		Z: math.Abs(v.Z),
+	}
+}
+
+// Sign returns a vector holding the sign of each component: -1 if negative, 1 if positive,
+// and 0 if zero.
+func (v Vector3) Sign() Vector3 {
+	return Vector3{
+		X: sign(v.X),
+		Y: sign(v.Y),
+		Z: sign(v.Z),
+	}
+}
+
+// sign returns -1, 0 or 1 according to the sign of f.
+func sign(f float64) float64 {
+	switch {
+	case f > 0:
+		return 1
+	case f < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// Floor returns a copy of the vector with each component rounded down to an integer.
+func (v Vector3) Floor() Vector3 {
+	return Vector3{
+		X: math.Floor(v.X),
+		Y: math.Floor(v.Y),
+		Z: math.Floor(v.Z),
+	}
+}
+
+// Ceil returns a copy of the vector with each component rounded up to an integer.
+func (v Vector3) Ceil() Vector3 {
+	return Vector3{
+		X: math.Ceil(v.X),
+		Y: math.Ceil(v.Y),
+		Z: math.Ceil(v.Z),
+	}
+}
+
+// Round returns a copy of the vector with each component rounded to the nearest integer. Halves
+// round away from zero, so 0.5 rounds to 1 and -0.5 rounds to -1.
+func (v Vector3) Round() Vector3 {
+	return Vector3{
+		X: math.Round(v.X),
+		Y: math.Round(v.Y),
+		Z: math.Round(v.Z),
+	}
+}
+
+// MaxComponent returns which axis (0 = X, 1 = Y, 2 = Z) holds the largest component of the
+// vector, and that component's value. Ties go to the lowest axis.
+func (v Vector3) MaxComponent() (axis int, value float64) {
+	axis, value = 0, v.X
+	if v.Y > value {
+		axis, value = 1, v.Y
+	}
+	if v.Z > value {
+		axis, value = 2, v.Z
+	}
+	return axis, value
+}
+
+// MinComponent returns which axis (0 = X, 1 = Y, 2 = Z) holds the smallest component of the
+// vector, and that component's value. Ties go to the lowest axis.
+func (v Vector3) MinComponent() (axis int, value float64) {
+	axis, value = 0, v.X
+	if v.Y < value {
+		axis, value = 1, v.Y
+	}
+	if v.Z < value {
+		axis, value = 2, v.Z
+	}
+	return axis, value
+}
+
 // AddScaledVector adds the components of s to v, scaled by k.
+//
+// ScaleAdd sits in the integration hot path and does no checking: a NaN or infinite k, or
+// component of s, spreads into v. Keeping its inputs finite is the caller's responsibility; use
+// ScaleAddChecked where they can't be trusted.
 func (v *Vector3) ScaleAdd(s Vector3, k float64) {
 	v.X += s.X * k
 	v.Y += s.Y * k
 	v.Z += s.Z * k
 }
 
+// ScaleAddChecked adds the components of s to v, scaled by k, like ScaleAdd, but first checks
+// that v, s and k are all finite. If any is NaN or infinite, it returns ErrNonFinite and leaves v
+// unchanged.
+func (v *Vector3) ScaleAddChecked(s Vector3, k float64) error {
+	if !isFinite(k) || !s.IsFinite() || !v.IsFinite() {
+		return ErrNonFinite
+	}
+	v.ScaleAdd(s, k)
+	return nil
+}
+
 func (v Vector3) ScaleAddCopy(s Vector3, k float64) Vector3 {
 	return Vector3{
 		X: v.X + s.X*k,
@@ -115,14 +257,68 @@ func (v Vector3) ComponentCopy(s Vector3) Vector3 {
 	}
 }
 
+// DivComponent divides each component of v by the matching component of s.
+// A zero component in s yields a zero component in v rather than Inf or NaN.
+func (v *Vector3) DivComponent(s Vector3) {
+	v.X = safeDiv(v.X, s.X)
+	v.Y = safeDiv(v.Y, s.Y)
+	v.Z = safeDiv(v.Z, s.Z)
+}
+
+// DivComponentCopy returns a copy of v with each component divided by the matching
+// component of s. A zero component in s yields a zero component in the result.
+func (v Vector3) DivComponentCopy(s Vector3) Vector3 {
+	return Vector3{
+		X: safeDiv(v.X, s.X),
+		Y: safeDiv(v.Y, s.Y),
+		Z: safeDiv(v.Z, s.Z),
+	}
+}
+
+// safeDiv divides a by b, returning 0 when b is zero.
+func safeDiv(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
+
+// ApproxEqual reports whether every component of v differs from the matching component of s by no
+// more than epsilon. Pass Epsilon for the engine-wide default tolerance.
+func (v Vector3) ApproxEqual(s Vector3, epsilon float64) bool {
+	return ApproxEqual(v.X, s.X, epsilon) && ApproxEqual(v.Y, s.Y, epsilon) && ApproxEqual(v.Z, s.Z, epsilon)
+}
+
+// IsFinite reports whether every component of v is finite, i.e. neither NaN nor ±Inf.
+func (v Vector3) IsFinite() bool {
+	return isFinite(v.X) && isFinite(v.Y) && isFinite(v.Z)
+}
+
+// isFinite reports whether f is neither NaN nor ±Inf.
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// IsZero reports whether every component of v is exactly zero. Use ApproxEqual against the zero
+// vector for a tolerant check.
+func (v Vector3) IsZero() bool {
+	return v.X == 0 && v.Y == 0 && v.Z == 0
+}
+
 // Dot computes the dot product of two vectors and returns its scalar.
 func (v Vector3) Dot(s Vector3) float64 {
 	return v.X*s.X + v.Y*s.Y + v.Z*s.Z
 }
 
-// Cross computes the cross product of two vectors and returns the vector.
+// Cross computes the cross product of two vectors and returns the vector. Components smaller
+// than Epsilon are snapped to zero.
 func (v Vector3) Cross(s Vector3) Vector3 {
-	epsilon := 1e-9
+	return v.CrossEpsilon(s, Epsilon)
+}
+
+// CrossEpsilon computes the cross product of two vectors like Cross, snapping components smaller
+// than epsilon to zero.
+func (v Vector3) CrossEpsilon(s Vector3, epsilon float64) Vector3 {
 	cross := Vector3{
 		X: v.Y*s.Z - v.Z*s.Y,
 		Y: v.Z*s.X - v.X*s.Z,
@@ -143,11 +339,31 @@ func (v Vector3) Cross(s Vector3) Vector3 {
 	return cross
 }
 
+// ScalarTripleProduct computes v · (b × c) and returns that scalar. It is the signed volume of
+// the parallelepiped spanned by the three vectors, and is zero when they are coplanar.
+//
+// The determinant is computed directly, rather than through Cross, so that small components of
+// b × c aren't snapped to zero and tiny parallelepipeds keep their volume.
+func (v Vector3) ScalarTripleProduct(b, c Vector3) float64 {
+	return v.X*(b.Y*c.Z-b.Z*c.Y) + v.Y*(b.Z*c.X-b.X*c.Z) + v.Z*(b.X*c.Y-b.Y*c.X)
+}
+
 // Magnitude computes the magnitude of a Vector3 and returns that scalar.
 func (v Vector3) Magnitude() float64 {
 	return math.Sqrt(v.lengthSquared())
 }
 
+// Distance returns the distance between the points v and s.
+func (v Vector3) Distance(s Vector3) float64 {
+	return math.Sqrt(v.DistanceSquared(s))
+}
+
+// DistanceSquared returns the squared distance between the points v and s. It avoids the square
+// root of Distance, so prefer it for comparing distances.
+func (v Vector3) DistanceSquared(s Vector3) float64 {
+	return v.SubCopy(s).lengthSquared()
+}
+
 // lengthSquared computes the squared magnitude of a Vector3.
 func (v Vector3) lengthSquared() float64 {
 	return v.X*v.X + v.Y*v.Y + v.Z*v.Z
@@ -155,25 +371,125 @@ func (v Vector3) lengthSquared() float64 {
 
 // Normalize resizes a Vector3 with a unit length of 1, i.e., turns it into a unit vector, and
 // returns a copy of this normalized vector.
+//
+// A vector with a magnitude below Epsilon has no meaningful direction, and normalizes to the zero vector.
 func (v Vector3) Normalize() Vector3 {
 	n := v.Magnitude()
-	if n > 0 {
+	if n >= Epsilon {
 		return Vector3{v.X / n, v.Y / n, v.Z / n}
 	} else {
 		return Vector3{}
 	}
 }
 
-// makeOrthonormalBasis offers a primitive orthogonalization algorithm for three vectors.
+// NormalizeFast returns a unit vector in the direction of v, like Normalize, but computes the
+// reciprocal of the magnitude with the "fast inverse square root" bit trick, refined by two
+// Newton-Raphson steps, rather than a square root and division. The result's length is within a
+// relative error of 5e-6 of 1, and its direction is exact.
+//
+// Whether this is actually faster than Normalize depends on the hardware; most modern CPUs have
+// a fast square root instruction. Compare BenchmarkNormalize and BenchmarkNormalizeFast on the
+// target machine before opting in. Like Normalize, a vector with a magnitude below Epsilon
+// normalizes to the zero vector.
+func (v Vector3) NormalizeFast() Vector3 {
+	lengthSquared := v.lengthSquared()
+	if lengthSquared < Epsilon*Epsilon {
+		return Vector3{}
+	}
+
+	inverse := fastInverseSqrt(lengthSquared)
+	return Vector3{v.X * inverse, v.Y * inverse, v.Z * inverse}
+}
+
+// fastInverseSqrt approximates 1/sqrt(x) for a positive x, using the float64 analogue of the
+// magic constant from Quake III followed by two Newton-Raphson refinements.
+func fastInverseSqrt(x float64) float64 {
+	const magic = 0x5FE6EB50C7B537A9
+
+	y := math.Float64frombits(magic - math.Float64bits(x)>>1)
+	half := 0.5 * x
+	y *= 1.5 - half*y*y
+	y *= 1.5 - half*y*y
+	return y
+}
+
+// Hash quantizes the vector to the cube of side cellSize containing it, and returns a hash of
+// that cell's integer coordinates. Every vector in the same cell has the same hash, so the result
+// can be used as a map key for spatial hashing. Adjacent cells hash differently with high probability.
+//
+// cellSize must be positive; Hash panics otherwise, as there is no grid to quantize to.
+func (v Vector3) Hash(cellSize float64) uint64 {
+	if !(cellSize > 0) {
+		panic(fmt.Sprintf("math64: Vector3.Hash cell size %v is not positive", cellSize))
+	}
+
+	x := uint64(int64(math.Floor(v.X / cellSize)))
+	y := uint64(int64(math.Floor(v.Y / cellSize)))
+	z := uint64(int64(math.Floor(v.Z / cellSize)))
+
+	// Fold in one coordinate at a time. Combining prime multiples of the coordinates with XOR, as
+	// in "Optimized Spatial Hashing for Collision Detection of Deformable Objects" (Teschner et al.),
+	// collides for cells mirrored through the origin, such as (0, -1, 7) and (0, 1, -7).
+	return mix64(mix64(mix64(x)^y) ^ z)
+}
+
+// mix64 scrambles h with the splitmix64 finalizer, so nearby inputs spread across all 64 bits.
+func mix64(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// RotateAround rotates v by angleRad radians about axis using Rodrigues' rotation formula, and
+// returns the rotated copy. The axis is normalized first; a zero axis returns v unchanged.
+//
+// Rotation follows the right-hand rule, so rotating (1, 0, 0) by Pi/2 about (0, 1, 0) gives (0, 0, -1).
+func (v Vector3) RotateAround(axis Vector3, angleRad float64) Vector3 {
+	k := axis.Normalize()
+	if k.lengthSquared() == 0 {
+		return v
+	}
+
+	cos := math.Cos(angleRad)
+	sin := math.Sin(angleRad)
+
+	// v_rot = v*cos + (k x v)*sin + k*(k . v)*(1 - cos)
+	rotated := v.ScaleCopy(cos)
+	rotated.ScaleAdd(k.Cross(v), sin)
+	rotated.ScaleAdd(k, k.Dot(v)*(1-cos))
+
+	return rotated
+}
+
+// NormalizeChecked behaves like Normalize, but returns ErrZeroVector instead of silently returning
+// the zero vector when the magnitude of v is too small to define a direction.
+func (v Vector3) NormalizeChecked() (Vector3, error) {
+	return v.NormalizeCheckedEpsilon(Epsilon)
+}
+
+// NormalizeCheckedEpsilon behaves like NormalizeChecked, treating any magnitude below epsilon as zero.
+func (v Vector3) NormalizeCheckedEpsilon(epsilon float64) (Vector3, error) {
+	n := v.Magnitude()
+	if n < epsilon {
+		return Vector3{}, ErrZeroVector
+	}
+	return Vector3{v.X / n, v.Y / n, v.Z / n}, nil
+}
+
+// MakeOrthonormalBasis offers a primitive orthogonalization algorithm for three vectors.
 // This refactored version avoids modifying the parameters as pointers and instead returns
-// the orthonormal basis vectors themselves.
-func makeOrthonormalBasis(a, b Vector3) (Vector3, Vector3, Vector3, error) {
+// the orthonormal basis vectors themselves: a normalized, b made orthogonal to it, and a × b.
+// Parallel a and b return ErrParallelVectors.
+func MakeOrthonormalBasis(a, b Vector3) (Vector3, Vector3, Vector3, error) {
 	normA := a.Normalize()
 	c := normA.Cross(b)
 
 	// A and B can NOT be parallel.
 	if c.lengthSquared() == 0 {
-		return Vector3{}, Vector3{}, Vector3{}, fmt.Errorf("a and b are parallel")
+		return Vector3{}, Vector3{}, Vector3{}, ErrParallelVectors
 	}
 
 	normC := c.Normalize()
@@ -183,3 +499,119 @@ func makeOrthonormalBasis(a, b Vector3) (Vector3, Vector3, Vector3, error) {
 
 	return normA, normB, normC, nil
 }
+
+// SlerpDirection spherically interpolates between the unit directions from and to by t, where
+// t = 0 returns from and t = 1 returns to. Both inputs are normalized before interpolating.
+//
+// Nearly parallel directions fall back to a normalized linear interpolation, and anti-parallel
+// directions rotate about an arbitrary axis perpendicular to from so the result is never NaN.
+func SlerpDirection(from, to Vector3, t float64) Vector3 {
+	const epsilon = 1e-6
+
+	a := from.Normalize()
+	b := to.Normalize()
+
+	dot := math.Max(-1, math.Min(1, a.Dot(b)))
+
+	switch {
+	case dot > 1-epsilon:
+		// Directions are nearly parallel; sin(theta) is too small to divide by.
+		return a.ScaleCopy(1 - t).AddCopy(b.ScaleCopy(t)).Normalize()
+	case dot < -1+epsilon:
+		// Directions are opposite, so any perpendicular axis is a valid great circle.
+		p := a.AnyPerpendicular()
+		theta := Pi * t
+		return a.ScaleCopy(math.Cos(theta)).AddCopy(p.ScaleCopy(math.Sin(theta)))
+	}
+
+	theta := math.Acos(dot)
+	sinTheta := math.Sin(theta)
+	wa := math.Sin((1-t)*theta) / sinTheta
+	wb := math.Sin(t*theta) / sinTheta
+
+	return a.ScaleCopy(wa).AddCopy(b.ScaleCopy(wb))
+}
+
+// RotateToward rotates v towards the direction of target by at most maxRadians, keeping the
+// magnitude of v. If v is already within maxRadians of target, it snaps to target's direction.
+// A zero v or target has no direction, and v is returned unchanged, as it is for a maxRadians of
+// zero or less.
+func (v Vector3) RotateToward(target Vector3, maxRadians float64) Vector3 {
+	magnitude := v.Magnitude()
+	from, err := v.NormalizeChecked()
+	if err != nil {
+		return v
+	}
+	to, err := target.NormalizeChecked()
+	if err != nil || maxRadians <= 0 {
+		return v
+	}
+
+	angle := math.Acos(math.Max(-1, math.Min(1, from.Dot(to))))
+	if angle > maxRadians {
+		to = SlerpDirection(from, to, maxRadians/angle)
+	}
+	return to.ScaleCopy(magnitude)
+}
+
+// AnyPerpendicular returns an arbitrary unit vector perpendicular to v, such as for building a
+// tangent frame around it. v is crossed with the world axis along which its smallest component
+// lies, which is never close to parallel with v. Every direction is perpendicular to a zero
+// vector, so it returns the X axis.
+func (v Vector3) AnyPerpendicular() Vector3 {
+	unit, err := v.NormalizeChecked()
+	if err != nil {
+		return NewVector3(1, 0, 0)
+	}
+
+	var axis Vector3
+	switch smallest, _ := unit.Abs().MinComponent(); smallest {
+	case 0:
+		axis = NewVector3(1, 0, 0)
+	case 1:
+		axis = NewVector3(0, 1, 0)
+	default:
+		axis = NewVector3(0, 0, 1)
+	}
+	return unit.Cross(axis).Normalize()
+}
+
+// Midpoint returns the point halfway between a and b.
+func Midpoint(a, b Vector3) Vector3 {
+	return Vector3{
+		X: (a.X + b.X) / 2,
+		Y: (a.Y + b.Y) / 2,
+		Z: (a.Z + b.Z) / 2,
+	}
+}
+
+// Centroid returns the average of the given points, such as the center of a group of particles.
+// With no points, it returns the zero vector.
+func Centroid(points ...Vector3) Vector3 {
+	if len(points) == 0 {
+		return Vector3{}
+	}
+
+	var sum Vector3
+	for _, p := range points {
+		sum.Add(p)
+	}
+	sum.Scale(1 / float64(len(points)))
+	return sum
+}
+
+// MeanVector3 returns the component-wise mean of the vectors, such as the average velocity over a
+// recorded trajectory. It is the same as Centroid, and returns the zero vector for an empty slice.
+func MeanVector3(vs []Vector3) Vector3 {
+	return Centroid(vs...)
+}
+
+// MaxMagnitude returns the largest magnitude among the vectors, such as the peak speed over a
+// recorded trajectory. It returns 0 for an empty slice.
+func MaxMagnitude(vs []Vector3) float64 {
+	maxSquared := 0.0
+	for _, v := range vs {
+		maxSquared = math.Max(maxSquared, v.lengthSquared())
+	}
+	return math.Sqrt(maxSquared)
+}
@@ -0,0 +1,866 @@
+package math64
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// vectorsClose reports whether a and b are within 1e-9 of each other on every axis.
+func vectorsClose(a, b Vector3) bool {
+	return a.ApproxEqual(b, 1e-9)
+}
+
+func TestDivComponentCopy(t *testing.T) {
+	tests := []struct {
+		name string
+		v, s Vector3
+		want Vector3
+	}{
+		{"normal", NewVector3(6, -8, 9), NewVector3(2, 4, -3), NewVector3(3, -2, -3)},
+		{"zero x", NewVector3(6, -8, 9), NewVector3(0, 4, -3), NewVector3(0, -2, -3)},
+		{"zero y", NewVector3(6, -8, 9), NewVector3(2, 0, -3), NewVector3(3, 0, -3)},
+		{"zero z", NewVector3(6, -8, 9), NewVector3(2, 4, 0), NewVector3(3, -2, 0)},
+		{"all zero", NewVector3(6, -8, 9), Vector3{}, Vector3{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v.DivComponentCopy(tt.s); got != tt.want {
+				t.Errorf("DivComponentCopy(%v, %v) = %v, want %v", tt.v, tt.s, got, tt.want)
+			}
+
+			v := tt.v
+			v.DivComponent(tt.s)
+			if v != tt.want {
+				t.Errorf("DivComponent(%v, %v) = %v, want %v", tt.v, tt.s, v, tt.want)
+			}
+		})
+	}
+}
+
+func TestScalarTripleProduct(t *testing.T) {
+	x, y, z := NewVector3(1, 0, 0), NewVector3(0, 1, 0), NewVector3(0, 0, 1)
+	tests := []struct {
+		name    string
+		a, b, c Vector3
+		want    float64
+	}{
+		{"right-handed basis", x, y, z, 1},
+		{"left-handed basis", x, z, y, -1},
+		{"cyclic permutation", y, z, x, 1},
+		{"coplanar", NewVector3(1, 2, 0), NewVector3(3, -1, 0), NewVector3(-2, 5, 0), 0},
+		{"repeated vector", NewVector3(1, 2, 3), NewVector3(1, 2, 3), NewVector3(4, 5, 6), 0},
+		{"box", NewVector3(2, 0, 0), NewVector3(0, 3, 0), NewVector3(0, 0, 4), 24},
+		{"sheared box", NewVector3(2, 0, 0), NewVector3(1, 3, 0), NewVector3(5, -2, 4), 24},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.ScalarTripleProduct(tt.b, tt.c); !ApproxEqual(got, tt.want, 1e-12) {
+				t.Errorf("%v.ScalarTripleProduct(%v, %v) = %v, want %v", tt.a, tt.b, tt.c, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScalarTripleProductSmallScale(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b, c Vector3
+		want    float64
+	}{
+		{"tiny box", NewVector3(1e-5, 0, 0), NewVector3(0, 1e-5, 0), NewVector3(0, 0, 1e-5), 1e-15},
+		{"tiny sheared box", NewVector3(2e-5, 0, 0), NewVector3(1e-5, 3e-5, 0), NewVector3(5e-5, -2e-5, 4e-5), 24e-15},
+		{"tiny left-handed", NewVector3(0, 0, 1e-6), NewVector3(0, 1e-6, 0), NewVector3(1e-6, 0, 0), -1e-18},
+		{"thin slab", NewVector3(1, 0, 0), NewVector3(0, 1e-6, 0), NewVector3(0, 0, 1e-6), 1e-12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.a.ScalarTripleProduct(tt.b, tt.c)
+			if !ApproxEqual(got, tt.want, 1e-9*math.Abs(tt.want)) {
+				t.Errorf("%v.ScalarTripleProduct(%v, %v) = %v, want %v", tt.a, tt.b, tt.c, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlerpDirection(t *testing.T) {
+	x, y := NewVector3(1, 0, 0), NewVector3(0, 1, 0)
+	tests := []struct {
+		name     string
+		from, to Vector3
+		t        float64
+		want     Vector3
+	}{
+		{"t=0", x, y, 0, x},
+		{"t=1", x, y, 1, y},
+		{"half angle", x, y, 0.5, NewVector3(math.Sqrt2/2, math.Sqrt2/2, 0)},
+		{"quarter angle", x, y, 0.25, NewVector3(math.Cos(Pi/8), math.Sin(Pi/8), 0)},
+		{"inputs normalized", x.ScaleCopy(3), y.ScaleCopy(0.5), 0.5, NewVector3(math.Sqrt2/2, math.Sqrt2/2, 0)},
+		{"parallel", x, x, 0.5, x},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SlerpDirection(tt.from, tt.to, tt.t); !vectorsClose(got, tt.want) {
+				t.Errorf("SlerpDirection(%v, %v, %v) = %v, want %v", tt.from, tt.to, tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlerpDirectionAntiParallel(t *testing.T) {
+	from, to := NewVector3(0, 0, 1), NewVector3(0, 0, -1)
+	for _, tt := range []float64{0, 0.25, 0.5, 0.75, 1} {
+		got := SlerpDirection(from, to, tt)
+		if !got.IsFinite() {
+			t.Fatalf("SlerpDirection(%v, %v, %v) = %v, want a finite vector", from, to, tt, got)
+		}
+		if !ApproxEqual(got.Magnitude(), 1, 1e-9) {
+			t.Errorf("SlerpDirection(%v, %v, %v) has magnitude %v, want 1", from, to, tt, got.Magnitude())
+		}
+		if angle := math.Acos(got.Dot(from)); !ApproxEqual(angle, Pi*tt, 1e-6) {
+			t.Errorf("SlerpDirection(%v, %v, %v) is %v radians from the start, want %v", from, to, tt, angle, Pi*tt)
+		}
+	}
+}
+
+func TestRotateAround(t *testing.T) {
+	x, y, z := NewVector3(1, 0, 0), NewVector3(0, 1, 0), NewVector3(0, 0, 1)
+	v := NewVector3(1, -2, 3)
+	tests := []struct {
+		name  string
+		v     Vector3
+		axis  Vector3
+		angle float64
+		want  Vector3
+	}{
+		{"x about y by 90", x, y, Pi / 2, NewVector3(0, 0, -1)},
+		{"x about z by 90", x, z, Pi / 2, y},
+		{"x about y by -90", x, y, -Pi / 2, z},
+		{"axis is normalized", x, y.ScaleCopy(5), Pi / 2, NewVector3(0, 0, -1)},
+		{"zero angle", v, NewVector3(1, 1, 1), 0, v},
+		{"full turn", v, NewVector3(1, 1, 1), 2 * Pi, v},
+		{"about itself", v, v, 1.3, v},
+		{"zero axis", v, Vector3{}, 1.3, v},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v.RotateAround(tt.axis, tt.angle); !vectorsClose(got, tt.want) {
+				t.Errorf("%v.RotateAround(%v, %v) = %v, want %v", tt.v, tt.axis, tt.angle, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeOrthonormalBasis(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Vector3
+		wantErr error
+	}{
+		{"axes", NewVector3(2, 0, 0), NewVector3(0, 3, 0), nil},
+		{"skewed", NewVector3(1, 1, 0), NewVector3(0, 1, 1), nil},
+		{"parallel", NewVector3(1, 2, 3), NewVector3(2, 4, 6), ErrParallelVectors},
+		{"anti-parallel", NewVector3(1, 2, 3), NewVector3(-1, -2, -3), ErrParallelVectors},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b, c, err := MakeOrthonormalBasis(tt.a, tt.b)
+			if err != tt.wantErr {
+				t.Fatalf("MakeOrthonormalBasis(%v, %v) error = %v, want %v", tt.a, tt.b, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			for _, v := range []Vector3{a, b, c} {
+				if !ApproxEqual(v.Magnitude(), 1, 1e-9) {
+					t.Errorf("basis vector %v is not unit length", v)
+				}
+			}
+			if !ApproxEqual(a.Dot(b), 0, 1e-9) || !ApproxEqual(a.Dot(c), 0, 1e-9) || !ApproxEqual(b.Dot(c), 0, 1e-9) {
+				t.Errorf("basis %v, %v, %v is not orthogonal", a, b, c)
+			}
+		})
+	}
+}
+
+func TestNormalizeChecked(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       Vector3
+		want    Vector3
+		wantErr error
+	}{
+		{"axis", NewVector3(0, 5, 0), NewVector3(0, 1, 0), nil},
+		{"3-4-5", NewVector3(3, 0, -4), NewVector3(0.6, 0, -0.8), nil},
+		{"zero", Vector3{}, Vector3{}, ErrZeroVector},
+		{"below epsilon", NewVector3(Epsilon/10, 0, 0), Vector3{}, ErrZeroVector},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.v.NormalizeChecked()
+			if err != tt.wantErr {
+				t.Fatalf("%v.NormalizeChecked() error = %v, want %v", tt.v, err, tt.wantErr)
+			}
+			if !vectorsClose(got, tt.want) {
+				t.Errorf("%v.NormalizeChecked() = %v, want %v", tt.v, got, tt.want)
+			}
+			if normalized := tt.v.Normalize(); !vectorsClose(normalized, tt.want) {
+				t.Errorf("%v.Normalize() = %v, want %v", tt.v, normalized, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeCheckedEpsilon(t *testing.T) {
+	v := NewVector3(0.01, 0, 0)
+	if _, err := v.NormalizeCheckedEpsilon(0.1); err != ErrZeroVector {
+		t.Errorf("%v.NormalizeCheckedEpsilon(0.1) error = %v, want %v", v, err, ErrZeroVector)
+	}
+	if got, err := v.NormalizeCheckedEpsilon(0.001); err != nil || !vectorsClose(got, NewVector3(1, 0, 0)) {
+		t.Errorf("%v.NormalizeCheckedEpsilon(0.001) = %v, %v, want (1, 0, 0), nil", v, got, err)
+	}
+}
+
+func TestEpsilonConsistency(t *testing.T) {
+	tests := []struct {
+		name     string
+		v        Vector3
+		wantZero bool
+	}{
+		{"zero", Vector3{}, true},
+		{"below epsilon", NewVector3(0, Epsilon/2, 0), true},
+		{"at epsilon", NewVector3(0, 0, Epsilon), false},
+		{"above epsilon", NewVector3(2*Epsilon, 0, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v.Normalize().IsZero(); got != tt.wantZero {
+				t.Errorf("%v.Normalize() is zero = %v, want %v", tt.v, got, tt.wantZero)
+			}
+			if _, err := tt.v.NormalizeChecked(); (err == ErrZeroVector) != tt.wantZero {
+				t.Errorf("%v.NormalizeChecked() error = %v, want zero vector error %v", tt.v, err, tt.wantZero)
+			}
+			if got := tt.v.NormalizeFast().IsZero(); got != tt.wantZero {
+				t.Errorf("%v.NormalizeFast() is zero = %v, want %v", tt.v, got, tt.wantZero)
+			}
+		})
+	}
+}
+
+func TestCrossEpsilon(t *testing.T) {
+	a, b := NewVector3(1, 0, 0), NewVector3(1, 1e-6, 0)
+	tests := []struct {
+		name    string
+		epsilon float64
+		want    Vector3
+	}{
+		{"default keeps small results", Epsilon, NewVector3(0, 0, 1e-6)},
+		{"coarse epsilon snaps", 1e-3, Vector3{}},
+		{"zero epsilon keeps everything", 0, NewVector3(0, 0, 1e-6)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := a.CrossEpsilon(b, tt.epsilon); got != tt.want {
+				t.Errorf("%v.CrossEpsilon(%v, %v) = %v, want %v", a, b, tt.epsilon, got, tt.want)
+			}
+		})
+	}
+	if got, want := a.Cross(b), a.CrossEpsilon(b, Epsilon); got != want {
+		t.Errorf("Cross = %v, want CrossEpsilon with Epsilon = %v", got, want)
+	}
+}
+
+func TestApproxEqual(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    float64
+		epsilon float64
+		want    bool
+	}{
+		{"equal", 1, 1, 0, true},
+		{"within default", 1, 1 + Epsilon/2, Epsilon, true},
+		{"outside default", 1, 1 + 3*Epsilon, Epsilon, false},
+		{"within override", 1, 1.01, 0.1, true},
+		{"symmetric", 1.01, 1, 0.001, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ApproxEqual(tt.a, tt.b, tt.epsilon); got != tt.want {
+				t.Errorf("ApproxEqual(%v, %v, %v) = %v, want %v", tt.a, tt.b, tt.epsilon, got, tt.want)
+			}
+			va, vb := NewVector3(0, tt.a, 0), NewVector3(0, tt.b, 0)
+			if got := va.ApproxEqual(vb, tt.epsilon); got != tt.want {
+				t.Errorf("%v.ApproxEqual(%v, %v) = %v, want %v", va, vb, tt.epsilon, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAbsSign(t *testing.T) {
+	tests := []struct {
+		name     string
+		v        Vector3
+		wantAbs  Vector3
+		wantSign Vector3
+	}{
+		{"mixed signs", NewVector3(-3, 2, -0.5), NewVector3(3, 2, 0.5), NewVector3(-1, 1, -1)},
+		{"zero component", NewVector3(4, 0, -7), NewVector3(4, 0, 7), NewVector3(1, 0, -1)},
+		{"zero", Vector3{}, Vector3{}, Vector3{}},
+		{"negative zero", NewVector3(math.Copysign(0, -1), 1, 1), NewVector3(0, 1, 1), NewVector3(0, 1, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v.Abs(); got != tt.wantAbs {
+				t.Errorf("%v.Abs() = %v, want %v", tt.v, got, tt.wantAbs)
+			}
+			if got := tt.v.Sign(); got != tt.wantSign {
+				t.Errorf("%v.Sign() = %v, want %v", tt.v, got, tt.wantSign)
+			}
+		})
+	}
+}
+
+func TestMaxMinComponent(t *testing.T) {
+	tests := []struct {
+		name        string
+		v           Vector3
+		wantMaxAxis int
+		wantMax     float64
+		wantMinAxis int
+		wantMin     float64
+	}{
+		{"x largest", NewVector3(5, 1, -2), 0, 5, 2, -2},
+		{"y largest", NewVector3(-4, 9, 3), 1, 9, 0, -4},
+		{"z largest", NewVector3(2, -6, 8), 2, 8, 1, -6},
+		{"all equal", NewVector3(3, 3, 3), 0, 3, 0, 3},
+		{"tie on y and z", NewVector3(1, 4, 4), 1, 4, 0, 1},
+		{"tie on x and z", NewVector3(-2, 0, -2), 1, 0, 0, -2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if axis, value := tt.v.MaxComponent(); axis != tt.wantMaxAxis || value != tt.wantMax {
+				t.Errorf("%v.MaxComponent() = %d, %v, want %d, %v", tt.v, axis, value, tt.wantMaxAxis, tt.wantMax)
+			}
+			if axis, value := tt.v.MinComponent(); axis != tt.wantMinAxis || value != tt.wantMin {
+				t.Errorf("%v.MinComponent() = %d, %v, want %d, %v", tt.v, axis, value, tt.wantMinAxis, tt.wantMin)
+			}
+		})
+	}
+}
+
+func TestHashSameCell(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Vector3
+	}{
+		{"positive", NewVector3(0.1, 0.2, 0.3), NewVector3(0.9, 0.5, 0.99)},
+		{"negative", NewVector3(-0.1, -0.2, -0.3), NewVector3(-0.9, -0.99, -0.01)},
+		{"mixed", NewVector3(2.5, -3.5, 7.01), NewVector3(2.01, -3.99, 7.99)},
+		{"cell corner", NewVector3(-1, 0, 5), NewVector3(-0.01, 0.99, 5.5)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ha, hb := tt.a.Hash(1), tt.b.Hash(1); ha != hb {
+				t.Errorf("%v and %v are in the same cell but hash to %x and %x", tt.a, tt.b, ha, hb)
+			}
+		})
+	}
+}
+
+func TestHashAdjacentCells(t *testing.T) {
+	// Every cell in a block straddling the origin, so negative cells and the cells either side of
+	// zero are covered, must hash differently from every other.
+	const cellSize = 0.5
+	seen := make(map[uint64]Vector3)
+	for x := -8; x < 8; x++ {
+		for y := -8; y < 8; y++ {
+			for z := -8; z < 8; z++ {
+				center := NewVector3(float64(x)+0.5, float64(y)+0.5, float64(z)+0.5).ScaleCopy(cellSize)
+				h := center.Hash(cellSize)
+				if other, ok := seen[h]; ok {
+					t.Fatalf("cells containing %v and %v both hash to %x", other, center, h)
+				}
+				seen[h] = center
+			}
+		}
+	}
+}
+
+func TestHashInvalidCellSize(t *testing.T) {
+	for _, cellSize := range []float64{0, -1, math.NaN()} {
+		t.Run(fmt.Sprint(cellSize), func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Hash(%v) did not panic", cellSize)
+				}
+			}()
+			NewVector3(1, 2, 3).Hash(cellSize)
+		})
+	}
+}
+
+func TestFloorCeilRound(t *testing.T) {
+	tests := []struct {
+		name      string
+		v         Vector3
+		wantFloor Vector3
+		wantCeil  Vector3
+		wantRound Vector3
+	}{
+		{"positive fractions", NewVector3(1.2, 2.5, 3.7), NewVector3(1, 2, 3), NewVector3(2, 3, 4), NewVector3(1, 3, 4)},
+		{"negative fractions", NewVector3(-1.2, -2.5, -3.7), NewVector3(-2, -3, -4), NewVector3(-1, -2, -3), NewVector3(-1, -3, -4)},
+		{"halves round away from zero", NewVector3(0.5, -0.5, 1.5), NewVector3(0, -1, 1), NewVector3(1, 0, 2), NewVector3(1, -1, 2)},
+		{"integers unchanged", NewVector3(4, -7, 0), NewVector3(4, -7, 0), NewVector3(4, -7, 0), NewVector3(4, -7, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v.Floor(); got != tt.wantFloor {
+				t.Errorf("%v.Floor() = %v, want %v", tt.v, got, tt.wantFloor)
+			}
+			if got := tt.v.Ceil(); got != tt.wantCeil {
+				t.Errorf("%v.Ceil() = %v, want %v", tt.v, got, tt.wantCeil)
+			}
+			if got := tt.v.Round(); got != tt.wantRound {
+				t.Errorf("%v.Round() = %v, want %v", tt.v, got, tt.wantRound)
+			}
+		})
+	}
+}
+
+func TestIsFiniteIsZero(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	tests := []struct {
+		name       string
+		v          Vector3
+		wantFinite bool
+		wantZero   bool
+	}{
+		{"finite", NewVector3(1, -2, 3.5), true, false},
+		{"zero", Vector3{}, true, true},
+		{"negative zero", NewVector3(math.Copysign(0, -1), 0, 0), true, true},
+		{"tiny", NewVector3(0, 0, math.SmallestNonzeroFloat64), true, false},
+		{"NaN", NewVector3(0, nan, 0), false, false},
+		{"+Inf", NewVector3(inf, 0, 0), false, false},
+		{"-Inf", NewVector3(0, 0, -inf), false, false},
+		{"huge but finite", NewVector3(math.MaxFloat64, -math.MaxFloat64, 0), true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v.IsFinite(); got != tt.wantFinite {
+				t.Errorf("%v.IsFinite() = %v, want %v", tt.v, got, tt.wantFinite)
+			}
+			if got := tt.v.IsZero(); got != tt.wantZero {
+				t.Errorf("%v.IsZero() = %v, want %v", tt.v, got, tt.wantZero)
+			}
+		})
+	}
+}
+
+func TestMidpoint(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Vector3
+		want Vector3
+	}{
+		{"axis", NewVector3(0, 0, 0), NewVector3(4, 0, 0), NewVector3(2, 0, 0)},
+		{"mixed signs", NewVector3(-1, 2, -3), NewVector3(3, -2, 5), NewVector3(1, 0, 1)},
+		{"same point", NewVector3(1, 2, 3), NewVector3(1, 2, 3), NewVector3(1, 2, 3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Midpoint(tt.a, tt.b); got != tt.want {
+				t.Errorf("Midpoint(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if got := Midpoint(tt.b, tt.a); got != tt.want {
+				t.Errorf("Midpoint(%v, %v) = %v, want %v", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCentroid(t *testing.T) {
+	center := NewVector3(5, -1, 2)
+	cube := make([]Vector3, 0, 8)
+	for _, x := range []float64{-1, 1} {
+		for _, y := range []float64{-1, 1} {
+			for _, z := range []float64{-1, 1} {
+				cube = append(cube, center.AddCopy(NewVector3(x, y, z)))
+			}
+		}
+	}
+	tests := []struct {
+		name   string
+		points []Vector3
+		want   Vector3
+	}{
+		{"empty", nil, Vector3{}},
+		{"single point", []Vector3{NewVector3(7, 8, 9)}, NewVector3(7, 8, 9)},
+		{"two points", []Vector3{NewVector3(-1, 2, -3), NewVector3(3, -2, 5)}, NewVector3(1, 0, 1)},
+		{"cube corners", cube, center},
+		{"triangle", []Vector3{NewVector3(0, 0, 0), NewVector3(3, 0, 0), NewVector3(0, 3, 0)}, NewVector3(1, 1, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Centroid(tt.points...); !vectorsClose(got, tt.want) {
+				t.Errorf("Centroid(%v) = %v, want %v", tt.points, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScaleAdd(t *testing.T) {
+	v, s := NewVector3(1, 2, 3), NewVector3(2, -4, 0.5)
+	tests := []struct {
+		name      string
+		k         float64
+		wantScale Vector3
+		wantAdd   Vector3
+	}{
+		{"positive", 2, NewVector3(2, 4, 6), NewVector3(5, -6, 4)},
+		{"negative", -0.5, NewVector3(-0.5, -1, -1.5), NewVector3(0, 4, 2.75)},
+		{"zero", 0, Vector3{}, v},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scaled := v
+			scaled.Scale(tt.k)
+			if scaled != tt.wantScale || v.ScaleCopy(tt.k) != tt.wantScale {
+				t.Errorf("%v scaled by %v = %v, want %v", v, tt.k, scaled, tt.wantScale)
+			}
+
+			added := v
+			added.ScaleAdd(s, tt.k)
+			if added != tt.wantAdd || v.ScaleAddCopy(s, tt.k) != tt.wantAdd {
+				t.Errorf("%v + %v*%v = %v, want %v", v, s, tt.k, added, tt.wantAdd)
+			}
+		})
+	}
+
+	// The unchecked variants spread a NaN scalar into every component, by contract.
+	nan := v
+	nan.ScaleAdd(s, math.NaN())
+	if !math.IsNaN(nan.X) || !math.IsNaN(nan.Y) || !math.IsNaN(nan.Z) {
+		t.Errorf("ScaleAdd with a NaN scalar = %v, want NaN in every component", nan)
+	}
+}
+
+func TestScaleAddChecked(t *testing.T) {
+	finite, inf, nan := NewVector3(1, 2, 3), math.Inf(1), math.NaN()
+	tests := []struct {
+		name    string
+		v, s    Vector3
+		k       float64
+		want    Vector3
+		wantErr error
+	}{
+		{"finite", finite, NewVector3(1, 1, 1), 2, NewVector3(3, 4, 5), nil},
+		{"NaN scalar", finite, NewVector3(1, 1, 1), nan, finite, ErrNonFinite},
+		{"+Inf scalar", finite, NewVector3(1, 1, 1), inf, finite, ErrNonFinite},
+		{"-Inf scalar", finite, NewVector3(1, 1, 1), -inf, finite, ErrNonFinite},
+		{"non-finite vector added", finite, NewVector3(0, nan, 0), 1, finite, ErrNonFinite},
+		{"non-finite receiver", NewVector3(inf, 0, 0), NewVector3(1, 1, 1), 1, NewVector3(inf, 0, 0), ErrNonFinite},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := tt.v
+			if err := v.ScaleAddChecked(tt.s, tt.k); err != tt.wantErr {
+				t.Fatalf("ScaleAddChecked error = %v, want %v", err, tt.wantErr)
+			}
+			if v != tt.want {
+				t.Errorf("ScaleAddChecked left %v, want %v", v, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeanVector3MaxMagnitude(t *testing.T) {
+	tests := []struct {
+		name     string
+		vs       []Vector3
+		wantMean Vector3
+		wantMax  float64
+	}{
+		{"empty", nil, Vector3{}, 0},
+		{"single", []Vector3{NewVector3(3, 0, -4)}, NewVector3(3, 0, -4), 5},
+		{"known set", []Vector3{NewVector3(1, 2, 3), NewVector3(-3, 0, 4), NewVector3(5, 1, -1)}, NewVector3(1, 1, 2), math.Sqrt(27)},
+		{"largest is negative", []Vector3{NewVector3(0, 1, 0), NewVector3(0, -10, 0), NewVector3(2, 2, 1)}, NewVector3(2.0/3, -7.0/3, 1.0/3), 10},
+		{"all zero", []Vector3{{}, {}}, Vector3{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MeanVector3(tt.vs); !vectorsClose(got, tt.wantMean) {
+				t.Errorf("MeanVector3(%v) = %v, want %v", tt.vs, got, tt.wantMean)
+			}
+			if got := MaxMagnitude(tt.vs); !ApproxEqual(got, tt.wantMax, 1e-12) {
+				t.Errorf("MaxMagnitude(%v) = %v, want %v", tt.vs, got, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestRotateToward(t *testing.T) {
+	x, y := NewVector3(1, 0, 0), NewVector3(0, 1, 0)
+	tests := []struct {
+		name       string
+		v, target  Vector3
+		maxRadians float64
+		want       Vector3
+	}{
+		{"partial rotation", x, y, Pi / 4, NewVector3(math.Sqrt2/2, math.Sqrt2/2, 0)},
+		{"small step", x, y, 0.1, NewVector3(math.Cos(0.1), math.Sin(0.1), 0)},
+		{"exactly the angle snaps", x, y, Pi / 2, y},
+		{"large angle snaps", x, y, 10, y},
+		{"magnitude kept", x.ScaleCopy(3), y.ScaleCopy(0.5), Pi / 4, NewVector3(3*math.Sqrt2/2, 3*math.Sqrt2/2, 0)},
+		{"magnitude kept on snap", x.ScaleCopy(3), y.ScaleCopy(0.5), Pi, y.ScaleCopy(3)},
+		{"already aligned", x.ScaleCopy(2), x.ScaleCopy(5), 0.1, x.ScaleCopy(2)},
+		{"zero max angle", x, y, 0, x},
+		{"negative max angle", x, y, -1, x},
+		{"zero vector", Vector3{}, y, 1, Vector3{}},
+		{"zero target", x, Vector3{}, 1, x},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v.RotateToward(tt.target, tt.maxRadians); !vectorsClose(got, tt.want) {
+				t.Errorf("%v.RotateToward(%v, %v) = %v, want %v", tt.v, tt.target, tt.maxRadians, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotateTowardAntiParallel(t *testing.T) {
+	v, target := NewVector3(0, 0, 2), NewVector3(0, 0, -1)
+	for _, maxRadians := range []float64{0.5, Pi / 2, 3} {
+		got := v.RotateToward(target, maxRadians)
+		if !got.IsFinite() || !ApproxEqual(got.Magnitude(), 2, 1e-9) {
+			t.Fatalf("%v.RotateToward(%v, %v) = %v, want a finite vector of magnitude 2", v, target, maxRadians, got)
+		}
+		if angle := math.Acos(got.Normalize().Dot(v.Normalize())); !ApproxEqual(angle, maxRadians, 1e-6) {
+			t.Errorf("%v.RotateToward(%v, %v) turned %v radians, want %v", v, target, maxRadians, angle, maxRadians)
+		}
+	}
+}
+
+func TestNormalizeFast(t *testing.T) {
+	// NormalizeFast documents a relative error in length of at most 5e-6.
+	const maxRelativeError = 5e-6
+
+	tests := []struct {
+		name string
+		v    Vector3
+	}{
+		{"unit axis", NewVector3(1, 0, 0)},
+		{"small", NewVector3(1e-4, -2e-4, 3e-4)},
+		{"ordinary", NewVector3(1, 2, 3)},
+		{"large", NewVector3(-3e8, 4e8, 1e7)},
+		{"huge", NewVector3(1e150, 1e150, -1e150)},
+		{"one large component", NewVector3(1e6, 1e-3, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exact, fast := tt.v.Normalize(), tt.v.NormalizeFast()
+			if !ApproxEqual(fast.Magnitude(), 1, maxRelativeError) {
+				t.Errorf("%v.NormalizeFast() has length %v, want within %v of 1", tt.v, fast.Magnitude(), maxRelativeError)
+			}
+			if !fast.ApproxEqual(exact, maxRelativeError) {
+				t.Errorf("%v.NormalizeFast() = %v, want within %v of %v", tt.v, fast, maxRelativeError, exact)
+			}
+			// The direction is exact: every component is scaled by the same factor.
+			if cross := fast.Cross(exact); !cross.ApproxEqual(Vector3{}, 1e-15) {
+				t.Errorf("%v.NormalizeFast() = %v, not parallel to %v", tt.v, fast, exact)
+			}
+		})
+	}
+}
+
+func TestNormalizeFastRandom(t *testing.T) {
+	const maxRelativeError = 5e-6
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		// Spread the magnitudes, from well above Epsilon, over many orders of magnitude, as the bit
+		// trick's first guess depends on the exponent.
+		scale := math.Pow(10, rng.Float64()*16-4)
+		v := NewVector3(rng.NormFloat64(), rng.NormFloat64(), rng.NormFloat64()).ScaleCopy(scale)
+		if length := v.NormalizeFast().Magnitude(); !ApproxEqual(length, 1, maxRelativeError) {
+			t.Fatalf("%v.NormalizeFast() has length %v, want within %v of 1", v, length, maxRelativeError)
+		}
+	}
+}
+
+func TestNormalizeFastZero(t *testing.T) {
+	for _, v := range []Vector3{{}, NewVector3(Epsilon/2, 0, 0), NewVector3(0, -Epsilon/4, Epsilon/4)} {
+		if got := v.NormalizeFast(); got != (Vector3{}) {
+			t.Errorf("%v.NormalizeFast() = %v, want the zero vector", v, got)
+		}
+	}
+}
+
+func TestAnyPerpendicular(t *testing.T) {
+	tests := []struct {
+		name string
+		v    Vector3
+	}{
+		{"x axis", NewVector3(1, 0, 0)},
+		{"y axis", NewVector3(0, 1, 0)},
+		{"z axis", NewVector3(0, 0, 1)},
+		{"negative axis", NewVector3(0, -3, 0)},
+		{"diagonal", NewVector3(1, 1, 1)},
+		{"nearly x", NewVector3(1, 1e-9, -1e-9)},
+		{"large", NewVector3(-4e6, 2e6, 7e5)},
+		{"tiny", NewVector3(3e-6, -1e-6, 2e-6)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.v.AnyPerpendicular()
+			if !ApproxEqual(got.Magnitude(), 1, 1e-12) {
+				t.Errorf("%v.AnyPerpendicular() = %v with length %v, want a unit vector", tt.v, got, got.Magnitude())
+			}
+			if dot := got.Dot(tt.v.Normalize()); !ApproxEqual(dot, 0, 1e-12) {
+				t.Errorf("%v.AnyPerpendicular() = %v, dot product %v, want 0", tt.v, got, dot)
+			}
+		})
+	}
+}
+
+func TestAnyPerpendicularZero(t *testing.T) {
+	if got := (Vector3{}).AnyPerpendicular(); got != NewVector3(1, 0, 0) {
+		t.Errorf("zero vector AnyPerpendicular() = %v, want the X axis", got)
+	}
+}
+
+func TestVectorArrayRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		v    Vector3
+	}{
+		{"zero", Vector3{}},
+		{"unit axes", NewVector3(1, 0, 0)},
+		{"mixed", NewVector3(1.5, -2.25, 3e10)},
+		{"tiny", NewVector3(5e-324, -1e-300, 0)},
+		{"infinite", NewVector3(math.Inf(1), math.Inf(-1), 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v.Array(); got != [3]float64{tt.v.X, tt.v.Y, tt.v.Z} {
+				t.Errorf("Array() = %v, want X, Y, Z order", got)
+			}
+			if got := FromArray(tt.v.Array()); got != tt.v {
+				t.Errorf("FromArray(Array()) = %v, want %v", got, tt.v)
+			}
+
+			buf := tt.v.AppendTo([]float64{7})
+			if len(buf) != 4 || buf[0] != 7 {
+				t.Fatalf("AppendTo = %v, want 7 followed by the components", buf)
+			}
+			got, err := FromSlice(buf[1:])
+			if err != nil || got != tt.v {
+				t.Errorf("FromSlice(AppendTo()) = %v, %v, want %v", got, err, tt.v)
+			}
+		})
+	}
+}
+
+func TestVectorFlatBuffer(t *testing.T) {
+	vectors := []Vector3{NewVector3(1, 2, 3), NewVector3(-4, 5, -6), {}, NewVector3(0.5, 0.25, 0.125)}
+	var buf []float64
+	for _, v := range vectors {
+		buf = v.AppendTo(buf)
+	}
+	if len(buf) != 3*len(vectors) {
+		t.Fatalf("buffer has %d elements, want %d", len(buf), 3*len(vectors))
+	}
+	for i, want := range vectors {
+		got, err := FromSlice(buf[3*i:])
+		if err != nil || got != want {
+			t.Errorf("vector %d: FromSlice = %v, %v, want %v", i, got, err, want)
+		}
+	}
+}
+
+func TestFromSlice(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       []float64
+		want    Vector3
+		wantErr bool
+	}{
+		{"nil", nil, Vector3{}, true},
+		{"empty", []float64{}, Vector3{}, true},
+		{"one", []float64{1}, Vector3{}, true},
+		{"two", []float64{1, 2}, Vector3{}, true},
+		{"three", []float64{1, 2, 3}, NewVector3(1, 2, 3), false},
+		{"extra ignored", []float64{1, 2, 3, 4}, NewVector3(1, 2, 3), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromSlice(tt.s)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Fatalf("FromSlice(%v) = %v, %v, want %v with error %v", tt.s, got, err, tt.want, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrShortSlice) {
+				t.Errorf("FromSlice(%v) error %v doesn't wrap ErrShortSlice", tt.s, err)
+			}
+		})
+	}
+}
+
+// sink keeps the results of benchmarked vector operations alive so they aren't optimized away.
+var sink Vector3
+
+// The copy-style and in-place benchmarks are paired, to compare returning a new vector with
+// updating one through a pointer receiver.
+
+func BenchmarkAddCopy(b *testing.B) {
+	v, s := NewVector3(1, 2, 3), NewVector3(0.1, 0.2, 0.3)
+	for i := 0; i < b.N; i++ {
+		v = v.AddCopy(s)
+	}
+	sink = v
+}
+
+func BenchmarkAdd(b *testing.B) {
+	v, s := NewVector3(1, 2, 3), NewVector3(0.1, 0.2, 0.3)
+	for i := 0; i < b.N; i++ {
+		v.Add(s)
+	}
+	sink = v
+}
+
+func BenchmarkScaleAddCopy(b *testing.B) {
+	v, s := NewVector3(1, 2, 3), NewVector3(0.1, 0.2, 0.3)
+	for i := 0; i < b.N; i++ {
+		v = v.ScaleAddCopy(s, 0.5)
+	}
+	sink = v
+}
+
+func BenchmarkScaleAdd(b *testing.B) {
+	v, s := NewVector3(1, 2, 3), NewVector3(0.1, 0.2, 0.3)
+	for i := 0; i < b.N; i++ {
+		v.ScaleAdd(s, 0.5)
+	}
+	sink = v
+}
+
+func BenchmarkCross(b *testing.B) {
+	v, s := NewVector3(1, 2, 3), NewVector3(0.3, -0.2, 0.1)
+	for i := 0; i < b.N; i++ {
+		sink = v.Cross(s)
+	}
+}
+
+func BenchmarkNormalize(b *testing.B) {
+	v := NewVector3(1, 2, 3)
+	for i := 0; i < b.N; i++ {
+		sink = v.Normalize()
+	}
+}
+
+func BenchmarkNormalizeFast(b *testing.B) {
+	v := NewVector3(1, 2, 3)
+	for i := 0; i < b.N; i++ {
+		sink = v.NormalizeFast()
+	}
+}
@@ -0,0 +1,111 @@
+package physics
+
+import (
+	"math"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+// SolveLaunchVelocity computes the launch velocities that carry a projectile fired from the from
+// position, at the given speed, to the to position under constant gravity. Drag is ignored.
+//
+// There are generally two solutions: low is the flatter, faster arc, and high is the lofted
+// one. They coincide when the target sits exactly at the edge of the projectile's range, and
+// ok is false when the target is out of range, or speed isn't positive.
+//
+// The flight time t satisfies |d - g*t^2/2| = speed * t, where d = to - from. Squaring both
+// sides leaves a quadratic in t^2:
+//
+//	(|g|^2/4) t^4 - (d.g + speed^2) t^2 + |d|^2 = 0
+//
+// whose two positive roots are the flight times of the low and high arcs. The velocity for a
+// flight time t is then v = d/t - g*t/2.
+func SolveLaunchVelocity(from, to math64.Vector3, speed float64, gravity math64.Vector3) (low, high math64.Vector3, ok bool) {
+	if speed <= 0 {
+		return math64.Vector3{}, math64.Vector3{}, false
+	}
+
+	delta := to.SubCopy(from)
+	distanceSquared := delta.Dot(delta)
+	gravitySquared := gravity.Dot(gravity)
+
+	// Without gravity, the projectile flies straight at the target and there's only one arc.
+	if gravitySquared < math64.Epsilon {
+		direction, err := delta.NormalizeChecked()
+		if err != nil {
+			return math64.Vector3{}, math64.Vector3{}, false
+		}
+		direction.Scale(speed)
+		return direction, direction, true
+	}
+
+	a := gravitySquared / 4
+	b := -(delta.Dot(gravity) + speed*speed)
+	c := distanceSquared
+
+	discriminant := b*b - 4*a*c
+	if discriminant < 0 {
+		return math64.Vector3{}, math64.Vector3{}, false
+	}
+
+	root := math.Sqrt(discriminant)
+	tLowSquared := (-b - root) / (2 * a)
+	tHighSquared := (-b + root) / (2 * a)
+	if tHighSquared <= 0 {
+		return math64.Vector3{}, math64.Vector3{}, false
+	}
+
+	velocity := func(tSquared float64) math64.Vector3 {
+		t := math.Sqrt(tSquared)
+		v := delta.ScaleCopy(1 / t)
+		v.ScaleAdd(gravity, -t/2)
+		return v
+	}
+
+	high = velocity(tHighSquared)
+	// A target at the launch point has a zero-time low "arc"; only firing straight against
+	// gravity brings the projectile back, so both solutions are the high one.
+	if tLowSquared <= 0 {
+		return high, high, true
+	}
+	return velocity(tLowSquared), high, true
+}
+
+// TimeToReachHeight returns how long, from now, the particle takes to reach the height targetY
+// under constant gravity, ignoring drag and the particle's own Acceleration. ok is false if it
+// never reaches that height.
+//
+// A projectile usually crosses a height twice, once rising and once falling; the later crossing
+// is returned, which under downward gravity is the falling one. So for a ground height this is
+// the time until the projectile lands, even if it starts below the ground on its way up.
+//
+// The height follows y(t) = y0 + vy*t + gy*t^2/2, which is solved for y(t) = targetY.
+func TimeToReachHeight(p Particle, targetY float64, gravity math64.Vector3) (float64, bool) {
+	a := gravity.Y / 2
+	b := p.Velocity.Y
+	c := p.Position.Y - targetY
+
+	// Without vertical gravity the height changes linearly, if at all.
+	if a == 0 {
+		if b == 0 {
+			return 0, c == 0
+		}
+		t := -c / b
+		if t < 0 {
+			return 0, false
+		}
+		return t, true
+	}
+
+	discriminant := b*b - 4*a*c
+	if discriminant < 0 {
+		return 0, false
+	}
+
+	root := math.Sqrt(discriminant)
+	t := math.Max((-b-root)/(2*a), (-b+root)/(2*a))
+	if t < 0 {
+		return 0, false
+	}
+	return t, true
+}
@@ -0,0 +1,119 @@
+package physics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+// closestApproach simulates a projectile launched from from with the given velocity under gravity,
+// and returns the closest it comes to target within maxTime seconds.
+func closestApproach(from, velocity, target, gravity math64.Vector3, maxTime float64) float64 {
+	const step = 0.0005
+	p := NewParticleMass(from, velocity, gravity, 1, 1)
+	closest := p.Position.Distance(target)
+	for elapsed := 0.0; elapsed < maxTime; elapsed += step {
+		p.Integrate(step)
+		closest = math.Min(closest, p.Position.Distance(target))
+	}
+	return closest
+}
+
+func TestSolveLaunchVelocityReachable(t *testing.T) {
+	gravity := math64.NewVector3(0, -9.81, 0)
+	tests := []struct {
+		name     string
+		from, to math64.Vector3
+		speed    float64
+	}{
+		{"level", math64.Vector3{}, math64.NewVector3(20, 0, 0), 20},
+		{"uphill", math64.NewVector3(1, 0, 1), math64.NewVector3(10, 5, -8), 18},
+		{"downhill", math64.NewVector3(0, 10, 0), math64.NewVector3(0, 0, 30), 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			low, high, ok := SolveLaunchVelocity(tt.from, tt.to, tt.speed, gravity)
+			if !ok {
+				t.Fatalf("SolveLaunchVelocity found no arc to a reachable target")
+			}
+			if low == high {
+				t.Errorf("low and high arcs are both %v, want two distinct arcs", low)
+			}
+			if low.Y >= high.Y {
+				t.Errorf("low arc %v launches no flatter than the high arc %v", low, high)
+			}
+			for name, v := range map[string]math64.Vector3{"low": low, "high": high} {
+				if !math64.ApproxEqual(v.Magnitude(), tt.speed, 1e-9) {
+					t.Errorf("%s arc speed = %v, want %v", name, v.Magnitude(), tt.speed)
+				}
+				if miss := closestApproach(tt.from, v, tt.to, gravity, 10); miss > 0.1 {
+					t.Errorf("%s arc %v misses the target by %v", name, v, miss)
+				}
+			}
+		})
+	}
+}
+
+func TestSolveLaunchVelocityUnreachable(t *testing.T) {
+	gravity := math64.NewVector3(0, -9.81, 0)
+	tests := []struct {
+		name     string
+		from, to math64.Vector3
+		speed    float64
+	}{
+		{"out of range", math64.Vector3{}, math64.NewVector3(100, 0, 0), 10},
+		{"too high", math64.Vector3{}, math64.NewVector3(0, 50, 0), 10},
+		{"zero speed", math64.Vector3{}, math64.NewVector3(1, 0, 0), 0},
+		{"negative speed", math64.Vector3{}, math64.NewVector3(1, 0, 0), -5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if low, high, ok := SolveLaunchVelocity(tt.from, tt.to, tt.speed, gravity); ok {
+				t.Errorf("SolveLaunchVelocity = %v, %v, true, want ok false", low, high)
+			}
+		})
+	}
+}
+
+func TestSolveLaunchVelocityNoGravity(t *testing.T) {
+	low, high, ok := SolveLaunchVelocity(math64.Vector3{}, math64.NewVector3(3, 0, 4), 10, math64.Vector3{})
+	want := math64.NewVector3(6, 0, 8)
+	if !ok || !low.ApproxEqual(want, 1e-12) || !high.ApproxEqual(want, 1e-12) {
+		t.Errorf("SolveLaunchVelocity without gravity = %v, %v, %v, want %v twice", low, high, ok, want)
+	}
+}
+
+func TestTimeToReachHeight(t *testing.T) {
+	gravity := math64.NewVector3(0, -10, 0)
+	tests := []struct {
+		name     string
+		position math64.Vector3
+		velocity math64.Vector3
+		gravity  math64.Vector3
+		targetY  float64
+		want     float64
+		wantOK   bool
+	}{
+		// Thrown up at 20 m/s, it peaks at 20 m after 2 s and is back down at 15 m after 3 s.
+		{"descending crossing", math64.Vector3{}, math64.NewVector3(3, 20, 0), gravity, 15, 3, true},
+		{"lands", math64.Vector3{}, math64.NewVector3(0, 20, 0), gravity, 0, 4, true},
+		{"at the peak", math64.Vector3{}, math64.NewVector3(0, 20, 0), gravity, 20, 2, true},
+		{"dropped", math64.NewVector3(0, 45, 0), math64.Vector3{}, gravity, 0, 3, true},
+		{"below ground rising", math64.NewVector3(0, -5, 0), math64.NewVector3(0, 10, 0), gravity, 0, 1, true},
+		{"never that high", math64.Vector3{}, math64.NewVector3(0, 20, 0), gravity, 25, 0, false},
+		{"already fallen past", math64.NewVector3(0, -1, 0), math64.NewVector3(0, -5, 0), gravity, 0, 0, false},
+		{"no gravity, moving toward", math64.Vector3{}, math64.NewVector3(0, 2, 0), math64.Vector3{}, 10, 5, true},
+		{"no gravity, moving away", math64.Vector3{}, math64.NewVector3(0, -2, 0), math64.Vector3{}, 10, 0, false},
+		{"no gravity, already there", math64.NewVector3(0, 10, 0), math64.Vector3{}, math64.Vector3{}, 10, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(tt.position, tt.velocity, 1)
+			got, ok := TimeToReachHeight(*p, tt.targetY, tt.gravity)
+			if ok != tt.wantOK || !math64.ApproxEqual(got, tt.want, 1e-9) {
+				t.Errorf("TimeToReachHeight = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
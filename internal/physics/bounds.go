@@ -0,0 +1,38 @@
+package physics
+
+import (
+	"math"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+// BoundingSphere returns a sphere enclosing the positions of every particle, for camera framing or
+// a broad-phase test. The sphere is centered on the middle of the particles' axis-aligned bounding
+// box, with the radius reaching the farthest particle. It isn't the smallest possible sphere, but
+// is never more than about 1.7 times its radius.
+//
+// A single particle gives a sphere of zero radius at its position, and no particles give a zero
+// sphere at the origin.
+func BoundingSphere(particles []*Particle) (center math64.Vector3, radius float64) {
+	if len(particles) == 0 {
+		return math64.Vector3{}, 0
+	}
+
+	box := math64.NewAABB(particles[0].Position, particles[0].Position)
+	for _, p := range particles[1:] {
+		box.Min.X = math.Min(box.Min.X, p.Position.X)
+		box.Min.Y = math.Min(box.Min.Y, p.Position.Y)
+		box.Min.Z = math.Min(box.Min.Z, p.Position.Z)
+		box.Max.X = math.Max(box.Max.X, p.Position.X)
+		box.Max.Y = math.Max(box.Max.Y, p.Position.Y)
+		box.Max.Z = math.Max(box.Max.Z, p.Position.Z)
+	}
+
+	center = math64.Midpoint(box.Min, box.Max)
+
+	radiusSquared := 0.0
+	for _, p := range particles {
+		radiusSquared = math.Max(radiusSquared, p.Position.DistanceSquared(center))
+	}
+	return center, math.Sqrt(radiusSquared)
+}
@@ -0,0 +1,73 @@
+package physics
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+func TestBoundingSphere(t *testing.T) {
+	tests := []struct {
+		name       string
+		positions  []math64.Vector3
+		wantCenter math64.Vector3
+		wantRadius float64
+	}{
+		{"no particles", nil, math64.Vector3{}, 0},
+		{"single particle", []math64.Vector3{math64.NewVector3(1, -2, 3)}, math64.NewVector3(1, -2, 3), 0},
+		{"pair", []math64.Vector3{math64.NewVector3(-1, 0, 0), math64.NewVector3(3, 0, 0)}, math64.NewVector3(1, 0, 0), 2},
+		{"coincident", []math64.Vector3{math64.NewVector3(2, 2, 2), math64.NewVector3(2, 2, 2)}, math64.NewVector3(2, 2, 2), 0},
+		{
+			"cube corners",
+			[]math64.Vector3{math64.NewVector3(0, 0, 0), math64.NewVector3(2, 2, 2), math64.NewVector3(2, 0, 0), math64.NewVector3(0, 2, 2)},
+			math64.NewVector3(1, 1, 1), math.Sqrt(3),
+		},
+		{
+			"interior points don't widen it",
+			[]math64.Vector3{math64.NewVector3(0, -4, 0), math64.NewVector3(0, 4, 0), math64.NewVector3(0, 1, 0)},
+			math64.Vector3{}, 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var particles []*Particle
+			for _, pos := range tt.positions {
+				particles = append(particles, newTestParticle(pos, math64.Vector3{}, 1))
+			}
+
+			center, radius := BoundingSphere(particles)
+			if !center.ApproxEqual(tt.wantCenter, 1e-12) || !math64.ApproxEqual(radius, tt.wantRadius, 1e-12) {
+				t.Errorf("BoundingSphere() = %v, %v, want %v, %v", center, radius, tt.wantCenter, tt.wantRadius)
+			}
+		})
+	}
+}
+
+func TestBoundingSphereEnclosesRandomPoints(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, n := range []int{2, 10, 100} {
+		for trial := 0; trial < 20; trial++ {
+			var particles []*Particle
+			for i := 0; i < n; i++ {
+				pos := math64.RandomInSphere(rng, 50).AddCopy(math64.NewVector3(10, -20, 30))
+				particles = append(particles, newTestParticle(pos, math64.Vector3{}, 1))
+			}
+
+			center, radius := BoundingSphere(particles)
+			touching := false
+			for i, p := range particles {
+				d := p.Position.Distance(center)
+				if d > radius+1e-9 {
+					t.Fatalf("%d particles: particle %d at %v is %v from %v, outside radius %v", n, i, p.Position, d, center, radius)
+				}
+				touching = touching || math64.ApproxEqual(d, radius, 1e-9)
+			}
+			// The sphere is no larger than it needs to be about its center.
+			if !touching {
+				t.Errorf("%d particles: no particle on the sphere of radius %v", n, radius)
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package physics
+
+import (
+	"math"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+// ParticleBuffer stores many particles as a struct of arrays, rather than as a slice of Particle
+// objects. Each vector quantity is kept in a flat slice of interleaved X, Y, Z components, so the
+// integration loop walks contiguous memory and is friendly to the cache and to vectorization.
+//
+// Particle i occupies indices 3*i, 3*i+1 and 3*i+2 of the vector slices, and index i of the
+// scalar slices. Motion trails and collision layers are not stored.
+type ParticleBuffer struct {
+	Positions     []float64
+	Velocities    []float64
+	Accelerations []float64
+	Forces        []float64 // Accumulated forces, zeroed after each integration.
+	InverseMasses []float64
+	Dampings      []float64
+	DampingAxes   []float64 // Per-axis dampings, replacing Dampings for particles where non-zero.
+	Lifetimes     []float64
+	ages          []float64
+}
+
+// NewParticleBuffer creates an empty buffer with room for capacity particles before it reallocates.
+func NewParticleBuffer(capacity int) *ParticleBuffer {
+	return &ParticleBuffer{
+		Positions:     make([]float64, 0, 3*capacity),
+		Velocities:    make([]float64, 0, 3*capacity),
+		Accelerations: make([]float64, 0, 3*capacity),
+		Forces:        make([]float64, 0, 3*capacity),
+		InverseMasses: make([]float64, 0, capacity),
+		Dampings:      make([]float64, 0, capacity),
+		DampingAxes:   make([]float64, 0, 3*capacity),
+		Lifetimes:     make([]float64, 0, capacity),
+		ages:          make([]float64, 0, capacity),
+	}
+}
+
+// Len returns the number of particles in the buffer.
+func (b *ParticleBuffer) Len() int {
+	return len(b.InverseMasses)
+}
+
+// Append copies the particle into the buffer and returns its index.
+func (b *ParticleBuffer) Append(p Particle) int {
+	b.Positions = appendVector(b.Positions, p.Position)
+	b.Velocities = appendVector(b.Velocities, p.Velocity)
+	b.Accelerations = appendVector(b.Accelerations, p.Acceleration)
+	b.Forces = appendVector(b.Forces, p.forceAccumulator)
+	b.InverseMasses = append(b.InverseMasses, p.inverseMass)
+	b.Dampings = append(b.Dampings, p.Damping)
+	b.DampingAxes = appendVector(b.DampingAxes, p.DampingAxes)
+	b.Lifetimes = append(b.Lifetimes, p.Lifetime)
+	b.ages = append(b.ages, p.age)
+
+	return b.Len() - 1
+}
+
+// Particle returns a copy of the particle at index i.
+func (b *ParticleBuffer) Particle(i int) Particle {
+	return Particle{
+		Position:         vectorAt(b.Positions, i),
+		Velocity:         vectorAt(b.Velocities, i),
+		Acceleration:     vectorAt(b.Accelerations, i),
+		Damping:          b.Dampings[i],
+		DampingAxes:      vectorAt(b.DampingAxes, i),
+		inverseMass:      b.InverseMasses[i],
+		Lifetime:         b.Lifetimes[i],
+		forceAccumulator: vectorAt(b.Forces, i),
+		age:              b.ages[i],
+	}
+}
+
+// SetParticle overwrites the particle at index i with a copy of p.
+func (b *ParticleBuffer) SetParticle(i int, p Particle) {
+	setVectorAt(b.Positions, i, p.Position)
+	setVectorAt(b.Velocities, i, p.Velocity)
+	setVectorAt(b.Accelerations, i, p.Acceleration)
+	setVectorAt(b.Forces, i, p.forceAccumulator)
+	b.InverseMasses[i] = p.inverseMass
+	b.Dampings[i] = p.Damping
+	setVectorAt(b.DampingAxes, i, p.DampingAxes)
+	b.Lifetimes[i] = p.Lifetime
+	b.ages[i] = p.age
+}
+
+// AddForce adds force to the particle at index i, to be applied at the next integration.
+func (b *ParticleBuffer) AddForce(i int, force math64.Vector3) {
+	b.Forces[3*i] += force.X
+	b.Forces[3*i+1] += force.Y
+	b.Forces[3*i+2] += force.Z
+}
+
+// IntegrateAll integrates every particle in the buffer forward by duration, using the same
+// equations as Particle.Integrate. Particles with infinite mass are skipped.
+func (b *ParticleBuffer) IntegrateAll(duration float64) error {
+	if duration <= 0.0 {
+		return newPhysicsError(ErrNegativeDuration, "can not perform integration on a negative duration")
+	}
+
+	pos, vel, acc, force, axes := b.Positions, b.Velocities, b.Accelerations, b.Forces, b.DampingAxes
+	for i, inverseMass := range b.InverseMasses {
+		if inverseMass <= 0.0 {
+			continue
+		}
+
+		// As in Particle.Integrate, non-zero per-axis damping replaces the scalar damping.
+		damping := math.Pow(b.Dampings[i], duration)
+		dampings := [3]float64{damping, damping, damping}
+		if axes[3*i] != 0 || axes[3*i+1] != 0 || axes[3*i+2] != 0 {
+			for k := range dampings {
+				dampings[k] = math.Pow(axes[3*i+k], duration)
+			}
+		}
+
+		for k, damping := range dampings {
+			j := 3*i + k
+			// Update position based on velocity, then velocity based on the resulting acceleration.
+			pos[j] += vel[j] * duration
+			resultingAcceleration := acc[j] + force[j]*inverseMass
+			vel[j] = (vel[j] + resultingAcceleration*duration) * damping
+			force[j] = 0
+		}
+
+		b.ages[i] += duration
+	}
+
+	return nil
+}
+
+// appendVector appends the components of v to s.
+func appendVector(s []float64, v math64.Vector3) []float64 {
+	return append(s, v.X, v.Y, v.Z)
+}
+
+// vectorAt returns the vector stored at index i of s.
+func vectorAt(s []float64, i int) math64.Vector3 {
+	return math64.NewVector3(s[3*i], s[3*i+1], s[3*i+2])
+}
+
+// setVectorAt stores v at index i of s.
+func setVectorAt(s []float64, i int, v math64.Vector3) {
+	s[3*i], s[3*i+1], s[3*i+2] = v.X, v.Y, v.Z
+}
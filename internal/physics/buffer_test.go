@@ -0,0 +1,126 @@
+package physics
+
+import (
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+// newBufferTestParticles returns a mix of particles covering damping, per-axis damping, forces,
+// gravity and infinite mass, so the buffer and Particle.Integrate are compared on every branch.
+func newBufferTestParticles() []Particle {
+	waterSurface := NewParticleMass(math64.NewVector3(2, 0, 2), math64.NewVector3(3, 3, 3), math64.NewVector3(0, -9.81, 0), 0.99, 1)
+	waterSurface.DampingAxes = math64.NewVector3(0.9, 0.2, 0.9)
+	return []Particle{
+		waterSurface,
+		NewParticleMass(math64.NewVector3(1, 2, 3), math64.NewVector3(4, -5, 6), math64.NewVector3(0, -9.81, 0), 0.99, 2),
+		NewParticleMass(math64.Vector3{}, math64.NewVector3(0, 0, 35), math64.NewVector3(0, -1, 0), 0.5, 200),
+		NewParticleMass(math64.NewVector3(-7, 0, 1), math64.Vector3{}, math64.Vector3{}, 1, 0.25),
+		NewParticleMass(math64.NewVector3(0, 10, 0), math64.NewVector3(1, 1, 1), math64.NewVector3(0, -9.81, 0), 0.9, 0),
+	}
+}
+
+func TestParticleBufferMatchesIntegrate(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration float64
+		steps    int
+		force    math64.Vector3
+	}{
+		{"single step", 0.016, 1, math64.Vector3{}},
+		{"many steps", 0.016, 100, math64.Vector3{}},
+		{"large steps", 0.5, 10, math64.Vector3{}},
+		{"with force", 0.016, 50, math64.NewVector3(3, -2, 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			particles := newBufferTestParticles()
+			buffer := NewParticleBuffer(len(particles))
+			for _, p := range particles {
+				buffer.Append(p)
+			}
+
+			for step := 0; step < tt.steps; step++ {
+				for i := range particles {
+					particles[i].AddForce(tt.force)
+					buffer.AddForce(i, tt.force)
+					if particles[i].HasFiniteMass() {
+						if err := particles[i].Integrate(tt.duration); err != nil {
+							t.Fatal(err)
+						}
+					}
+				}
+				if err := buffer.IntegrateAll(tt.duration); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for i, want := range particles {
+				got := buffer.Particle(i)
+				if got.Position != want.Position || got.Velocity != want.Velocity {
+					t.Errorf("particle %d: buffer has position %v velocity %v, Integrate gives %v %v",
+						i, got.Position, got.Velocity, want.Position, want.Velocity)
+				}
+				if got.Age() != want.Age() {
+					t.Errorf("particle %d: buffer age %v, Integrate age %v", i, got.Age(), want.Age())
+				}
+			}
+		})
+	}
+}
+
+func TestParticleBufferRoundTrip(t *testing.T) {
+	p := NewParticleMass(math64.NewVector3(1, 2, 3), math64.NewVector3(4, 5, 6), math64.NewVector3(7, 8, 9), 0.75, 4)
+	p.DampingAxes = math64.NewVector3(0.5, 0.25, 1)
+	p.Lifetime = 3
+	p.AddForce(math64.NewVector3(1, 0, -1))
+
+	buffer := NewParticleBuffer(0)
+	buffer.Append(Particle{})
+	i := buffer.Append(p)
+	if i != 1 || buffer.Len() != 2 {
+		t.Fatalf("Append returned index %d with Len %d, want 1 and 2", i, buffer.Len())
+	}
+
+	got := buffer.Particle(i)
+	if got.Position != p.Position || got.Velocity != p.Velocity || got.Acceleration != p.Acceleration ||
+		got.Damping != p.Damping || got.DampingAxes != p.DampingAxes || got.inverseMass != p.inverseMass || got.Lifetime != p.Lifetime ||
+		got.forceAccumulator != p.forceAccumulator {
+		t.Errorf("Particle(%d) = %+v, want %+v", i, got, p)
+	}
+
+	buffer.SetParticle(0, p)
+	if got := buffer.Particle(0); got.Position != p.Position || got.inverseMass != p.inverseMass {
+		t.Errorf("after SetParticle, Particle(0) = %+v, want %+v", got, p)
+	}
+}
+
+// benchParticleCount is the number of particles the AoS and SoA integration benchmarks step.
+const benchParticleCount = 10000
+
+func BenchmarkIntegrateAoS(b *testing.B) {
+	particles := make([]*Particle, benchParticleCount)
+	for i := range particles {
+		p := NewParticleMass(math64.NewVector3(float64(i), 0, 0), math64.NewVector3(1, 2, 3), math64.NewVector3(0, -9.81, 0), 0.99, 1)
+		particles[i] = &p
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, p := range particles {
+			p.Integrate(0.016)
+		}
+	}
+}
+
+func BenchmarkIntegrateSoA(b *testing.B) {
+	buffer := NewParticleBuffer(benchParticleCount)
+	for i := 0; i < benchParticleCount; i++ {
+		buffer.Append(NewParticleMass(math64.NewVector3(float64(i), 0, 0), math64.NewVector3(1, 2, 3), math64.NewVector3(0, -9.81, 0), 0.99, 1))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buffer.IntegrateAll(0.016)
+	}
+}
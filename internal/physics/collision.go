@@ -0,0 +1,63 @@
+package physics
+
+import "github.com/user54778/cyclone/internal/math64"
+
+// ParticleCollisionGenerator generates contacts between every pair of its particles that overlap,
+// treating each particle as a sphere of Radius. Only particles sharing a collision layer collide;
+// see Particle.CollisionLayer.
+//
+// Every pair is tested, so it suits small sets of particles.
+type ParticleCollisionGenerator struct {
+	Particles   []*Particle
+	Radius      float64 // Radius of every particle.
+	Restitution float64 // Restitution of every collision.
+}
+
+// NewParticleCollisionGenerator creates a collision generator for the given particles.
+func NewParticleCollisionGenerator(particles []*Particle, radius, restitution float64) *ParticleCollisionGenerator {
+	return &ParticleCollisionGenerator{
+		Particles:   particles,
+		Radius:      radius,
+		Restitution: restitution,
+	}
+}
+
+// AddContact writes a contact for every overlapping pair of particles on a shared layer, up to
+// len(contacts).
+func (g *ParticleCollisionGenerator) AddContact(contacts []ParticleContact) int {
+	used := 0
+	minDistance := 2 * g.Radius
+	for i, a := range g.Particles {
+		for _, b := range g.Particles[i+1:] {
+			if used >= len(contacts) {
+				// We've run out of contacts to fill.
+				return used
+			}
+
+			if !a.CollidesWith(b) {
+				continue
+			}
+
+			d := a.Position.SubCopy(b.Position)
+			distance := d.Magnitude()
+			if distance >= minDistance {
+				continue
+			}
+
+			// Coincident particles have no separating direction, so push them apart vertically.
+			normal, err := d.NormalizeChecked()
+			if err != nil {
+				normal = math64.NewVector3(0, 1, 0)
+			}
+
+			contacts[used] = ParticleContact{
+				Particles:     [2]*Particle{a, b},
+				ContactNormal: normal,
+				Penetration:   minDistance - distance,
+				Restitution:   g.Restitution,
+			}
+			used++
+		}
+	}
+	return used
+}
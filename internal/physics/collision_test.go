@@ -0,0 +1,107 @@
+package physics
+
+import (
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+func TestParticleCollisionGeneratorLayers(t *testing.T) {
+	tests := []struct {
+		name         string
+		layerA       uint32
+		layerB       uint32
+		wantContacts int
+	}{
+		{"both default", 0, 0, 1},
+		{"default matches layer one", 0, 1, 1},
+		{"same layer", 4, 4, 1},
+		{"shared layer", 0b011, 0b110, 1},
+		{"different layers", 2, 4, 0},
+		{"default and another layer", 0, 2, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The particles overlap by 0.5.
+			a := newTestParticle(math64.NewVector3(0, 0, 0), math64.Vector3{}, 1)
+			b := newTestParticle(math64.NewVector3(1.5, 0, 0), math64.Vector3{}, 1)
+			a.CollisionLayer, b.CollisionLayer = tt.layerA, tt.layerB
+			g := NewParticleCollisionGenerator([]*Particle{a, b}, 1, 0.5)
+
+			contacts := make([]ParticleContact, 2)
+			used := g.AddContact(contacts)
+			if used != tt.wantContacts {
+				t.Fatalf("AddContact() = %d contacts, want %d", used, tt.wantContacts)
+			}
+			if used == 0 {
+				return
+			}
+
+			c := contacts[0]
+			if c.Particles != [2]*Particle{a, b} {
+				t.Errorf("contact particles = %v, want %v", c.Particles, [2]*Particle{a, b})
+			}
+			if want := math64.NewVector3(-1, 0, 0); !c.ContactNormal.ApproxEqual(want, 1e-12) {
+				t.Errorf("contact normal = %v, want %v", c.ContactNormal, want)
+			}
+			if !math64.ApproxEqual(c.Penetration, 0.5, 1e-12) {
+				t.Errorf("penetration = %v, want 0.5", c.Penetration)
+			}
+			if c.Restitution != 0.5 {
+				t.Errorf("restitution = %v, want 0.5", c.Restitution)
+			}
+		})
+	}
+}
+
+func TestGroundContactGeneratorLayers(t *testing.T) {
+	tests := []struct {
+		name         string
+		groundLayers uint32
+		layer        uint32
+		wantContacts int
+	}{
+		{"unlimited ground", 0, 8, 1},
+		{"default particle on default layer", DefaultCollisionLayer, 0, 1},
+		{"matching layer", 0b100, 0b110, 1},
+		{"no matching layer", 0b100, 0b011, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(math64.NewVector3(0, -0.25, 0), math64.Vector3{}, 1)
+			p.CollisionLayer = tt.layer
+			g := NewGroundContactGenerator([]*Particle{p}, 0, 0, 0)
+			g.Layers = tt.groundLayers
+
+			if used := g.AddContact(make([]ParticleContact, 1)); used != tt.wantContacts {
+				t.Errorf("AddContact() = %d contacts, want %d", used, tt.wantContacts)
+			}
+		})
+	}
+}
+
+func TestParticleCollisionLayers(t *testing.T) {
+	tests := []struct {
+		name       string
+		layer      uint32
+		wantLayers uint32
+		mask       uint32
+		wantIn     bool
+	}{
+		{"unset is the default layer", 0, DefaultCollisionLayer, DefaultCollisionLayer, true},
+		{"unset is not on other layers", 0, DefaultCollisionLayer, 0b110, false},
+		{"on one of several", 0b101, 0b101, 0b100, true},
+		{"empty mask", 0b101, 0b101, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Particle{CollisionLayer: tt.layer}
+			if got := p.CollisionLayers(); got != tt.wantLayers {
+				t.Errorf("CollisionLayers() = %b, want %b", got, tt.wantLayers)
+			}
+			if got := p.InLayer(tt.mask); got != tt.wantIn {
+				t.Errorf("InLayer(%b) = %v, want %v", tt.mask, got, tt.wantIn)
+			}
+		})
+	}
+}
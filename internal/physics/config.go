@@ -0,0 +1,26 @@
+package physics
+
+import "github.com/user54778/cyclone/internal/math64"
+
+// SimConfig describes a particle's physical properties and initial state declaratively, so a
+// scenario can be written as data, or decoded from a config file by the caller, instead of as a
+// series of setter calls.
+type SimConfig struct {
+	Position     math64.Vector3
+	Velocity     math64.Vector3
+	Acceleration math64.Vector3 // Constant acceleration, usually gravity.
+	// Damping is the proportion of velocity retained each second, in (0, 1]; set it explicitly,
+	// to DefaultDamping for no damping. Values Particle.SetDamping rejects, including the zero
+	// value, fall back to DefaultDamping as in NewParticleMass.
+	Damping float64
+	// Mass is the particle's mass. Zero or less means infinite mass, an immovable particle.
+	Mass     float64
+	Lifetime float64 // Seconds until the particle expires; zero or less never expires.
+}
+
+// NewParticleFromConfig creates a particle with the properties and initial state in cfg.
+func NewParticleFromConfig(cfg SimConfig) Particle {
+	p := NewParticleMass(cfg.Position, cfg.Velocity, cfg.Acceleration, cfg.Damping, cfg.Mass)
+	p.Lifetime = cfg.Lifetime
+	return p
+}
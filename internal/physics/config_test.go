@@ -0,0 +1,73 @@
+package physics
+
+import (
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+func TestNewParticleFromConfig(t *testing.T) {
+	tests := []struct {
+		name            string
+		cfg             SimConfig
+		wantDamping     float64
+		wantInverseMass float64
+	}{
+		{
+			name: "all fields",
+			cfg: SimConfig{
+				Position:     math64.NewVector3(0, 1.5, 0),
+				Velocity:     math64.NewVector3(0, 30, 40),
+				Acceleration: math64.NewVector3(0, -20, 0),
+				Damping:      0.99,
+				Mass:         200,
+				Lifetime:     5,
+			},
+			wantDamping:     0.99,
+			wantInverseMass: 1.0 / 200,
+		},
+		{
+			name:            "explicit no damping",
+			cfg:             SimConfig{Damping: DefaultDamping, Mass: 2},
+			wantDamping:     1,
+			wantInverseMass: 0.5,
+		},
+		{
+			name:            "zero damping falls back to default",
+			cfg:             SimConfig{Mass: 2},
+			wantDamping:     DefaultDamping,
+			wantInverseMass: 0.5,
+		},
+		{
+			name:            "damping above one is clamped",
+			cfg:             SimConfig{Damping: 1.5, Mass: 2},
+			wantDamping:     1,
+			wantInverseMass: 0.5,
+		},
+		{
+			name:            "zero mass is infinite",
+			cfg:             SimConfig{Damping: 0.5},
+			wantDamping:     0.5,
+			wantInverseMass: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParticleFromConfig(tt.cfg)
+
+			if p.Position != tt.cfg.Position || p.Velocity != tt.cfg.Velocity || p.Acceleration != tt.cfg.Acceleration {
+				t.Errorf("kinematics = %v, %v, %v, want %v, %v, %v", p.Position, p.Velocity, p.Acceleration,
+					tt.cfg.Position, tt.cfg.Velocity, tt.cfg.Acceleration)
+			}
+			if p.Damping != tt.wantDamping {
+				t.Errorf("Damping = %v, want %v", p.Damping, tt.wantDamping)
+			}
+			if p.inverseMass != tt.wantInverseMass {
+				t.Errorf("inverse mass = %v, want %v", p.inverseMass, tt.wantInverseMass)
+			}
+			if p.Lifetime != tt.cfg.Lifetime {
+				t.Errorf("Lifetime = %v, want %v", p.Lifetime, tt.cfg.Lifetime)
+			}
+		})
+	}
+}
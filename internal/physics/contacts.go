@@ -0,0 +1,420 @@
+package physics
+
+import (
+	"math"
+	"sort"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+// ParticleContact represents two objects in contact, in this case two particles, or a particle and
+// the immovable scenery (when the second particle is nil).
+//
+// Resolving a contact removes their interpenetration and applies a sufficient impulse to keep
+// them apart. Colliding bodies may also rebound.
+type ParticleContact struct {
+	// Particles holds the particles involved in the contact. The second may be nil for
+	// contacts with the scenery.
+	Particles [2]*Particle
+	// Restitution is the normal restitution coefficient at the contact.
+	Restitution float64
+	// RestitutionFunc, if set, replaces Restitution with a coefficient that depends on the impact
+	// speed, the closing velocity along the normal, so real materials can bounce less when struck
+	// harder. Restitution is used when it is nil.
+	RestitutionFunc func(impactSpeed float64) float64
+	// Friction is the Coulomb friction coefficient at the contact. The tangential impulse that
+	// slows sliding is limited to Friction times the normal impulse. Zero means frictionless.
+	Friction float64
+	// ContactNormal is the direction of the contact in world coordinates, from the
+	// point of view of the first particle.
+	ContactNormal math64.Vector3
+	// Penetration is the depth of penetration at the contact.
+	Penetration float64
+	// particleMovement holds the amount each particle is moved by during interpenetration resolution.
+	particleMovement [2]math64.Vector3
+	// normalImpulse accumulates the impulse applied along the normal while resolving, for warm starting.
+	normalImpulse float64
+}
+
+// resolve resolves this contact for velocity, and corrects the given fraction of its interpenetration.
+func (c *ParticleContact) resolve(duration, correction float64) {
+	c.resolveVelocity(duration)
+	c.resolveInterpenetration(correction)
+}
+
+// separatingVelocity calculates the separating velocity at this contact.
+//
+// v_s = (v_a - v_b) . n, where a negative value means the particles are closing.
+func (c *ParticleContact) separatingVelocity() float64 {
+	relativeVelocity := c.Particles[0].Velocity
+	if c.Particles[1] != nil {
+		relativeVelocity.Sub(c.Particles[1].Velocity)
+	}
+	return relativeVelocity.Dot(c.ContactNormal)
+}
+
+// totalInverseMass returns the sum of the inverse masses of the particles in the contact.
+func (c *ParticleContact) totalInverseMass() float64 {
+	total := c.Particles[0].inverseMass
+	if c.Particles[1] != nil {
+		total += c.Particles[1].inverseMass
+	}
+	return total
+}
+
+// resolveVelocity handles the impulse calculations for this collision.
+func (c *ParticleContact) resolveVelocity(duration float64) {
+	separatingVelocity := c.separatingVelocity()
+
+	// The contact is either separating, or stationary; no impulse is required.
+	if separatingVelocity > 0 {
+		return
+	}
+
+	restitution := c.restitution(-separatingVelocity)
+	newSepVelocity := -separatingVelocity * restitution
+
+	// Check the velocity build-up due to acceleration only. If we've got a closing velocity
+	// due to acceleration build-up, remove it from the new separating velocity. This keeps
+	// objects resting on each other from vibrating.
+	accCausedVelocity := c.Particles[0].Acceleration
+	if c.Particles[1] != nil {
+		accCausedVelocity.Sub(c.Particles[1].Acceleration)
+	}
+	accCausedSepVelocity := accCausedVelocity.Dot(c.ContactNormal) * duration
+
+	if accCausedSepVelocity < 0 {
+		newSepVelocity += restitution * accCausedSepVelocity
+		// Make sure we haven't removed more than there was to remove.
+		if newSepVelocity < 0 {
+			newSepVelocity = 0
+		}
+	}
+
+	deltaVelocity := newSepVelocity - separatingVelocity
+
+	// Apply the change in velocity to each object in proportion to its inverse mass.
+	totalInverseMass := c.totalInverseMass()
+
+	// If all particles have infinite mass, impulses have no effect.
+	if totalInverseMass <= 0 {
+		return
+	}
+
+	impulse := deltaVelocity / totalInverseMass
+	impulsePerIMass := c.ContactNormal.ScaleCopy(impulse)
+
+	c.applyImpulse(impulsePerIMass)
+	c.normalImpulse += impulse
+
+	if c.Friction > 0 {
+		c.resolveFriction(impulse, totalInverseMass)
+	}
+}
+
+// restitution returns the restitution coefficient for a collision at the given impact speed.
+func (c *ParticleContact) restitution(impactSpeed float64) float64 {
+	if c.RestitutionFunc != nil {
+		return c.RestitutionFunc(impactSpeed)
+	}
+	return c.Restitution
+}
+
+// applyImpulse applies the impulse to the first particle, and the opposite impulse to the
+// second, each scaled by its inverse mass.
+func (c *ParticleContact) applyImpulse(impulse math64.Vector3) {
+	c.Particles[0].Velocity.ScaleAdd(impulse, c.Particles[0].inverseMass)
+	if c.Particles[1] != nil {
+		// Particle 1 goes in the opposite direction.
+		c.Particles[1].Velocity.ScaleAdd(impulse, -c.Particles[1].inverseMass)
+	}
+}
+
+// resolveFriction applies a tangential impulse opposing the particles' sliding, clamped by
+// Coulomb's law to Friction times the normal impulse.
+func (c *ParticleContact) resolveFriction(normalImpulse, totalInverseMass float64) {
+	relativeVelocity := c.Particles[0].Velocity
+	if c.Particles[1] != nil {
+		relativeVelocity.Sub(c.Particles[1].Velocity)
+	}
+
+	// Remove the normal component, leaving the sliding velocity.
+	tangent := relativeVelocity.ScaleAddCopy(c.ContactNormal, -relativeVelocity.Dot(c.ContactNormal))
+	slidingSpeed := tangent.Magnitude()
+	tangent, err := tangent.NormalizeChecked()
+	if err != nil {
+		return // Not sliding.
+	}
+
+	// The impulse that would stop the sliding entirely, limited by the friction cone.
+	frictionImpulse := math.Min(slidingSpeed/totalInverseMass, c.Friction*math.Abs(normalImpulse))
+
+	c.applyImpulse(tangent.ScaleCopy(-frictionImpulse))
+}
+
+// ContactBasis returns an orthonormal frame for a contact: the unit normal, and two unit tangents
+// spanning the contact plane, the first in the plane of normal and reference. It suits directions
+// measured in the contact plane, such as anisotropic friction. A reference parallel to normal
+// doesn't pick out a tangent, and returns an error with code ErrParallelVectors.
+func ContactBasis(normal, reference math64.Vector3) (n, tangent, bitangent math64.Vector3, err error) {
+	n, tangent, bitangent, err = math64.MakeOrthonormalBasis(normal, reference)
+	if err != nil {
+		return math64.Vector3{}, math64.Vector3{}, math64.Vector3{}, wrapPhysicsError(ErrParallelVectors, "contact basis", err)
+	}
+	return n, tangent, bitangent, nil
+}
+
+// resolveInterpenetration moves the particles apart along the contact normal, in proportion
+// to their inverse masses, removing the given fraction of their overlap. A correction of 1
+// separates them completely.
+func (c *ParticleContact) resolveInterpenetration(correction float64) {
+	c.particleMovement = [2]math64.Vector3{}
+
+	if c.Penetration <= 0 || correction <= 0 {
+		return
+	}
+
+	totalInverseMass := c.totalInverseMass()
+	if totalInverseMass <= 0 {
+		return
+	}
+
+	movePerIMass := c.ContactNormal.ScaleCopy(correction * c.Penetration / totalInverseMass)
+
+	c.particleMovement[0] = movePerIMass.ScaleCopy(c.Particles[0].inverseMass)
+	c.Particles[0].Position.Add(c.particleMovement[0])
+
+	if c.Particles[1] != nil {
+		c.particleMovement[1] = movePerIMass.ScaleCopy(-c.Particles[1].inverseMass)
+		c.Particles[1].Position.Add(c.particleMovement[1])
+	}
+}
+
+// ContactSolver resolves a frame's particle contacts. ParticleContactResolver is the built-in
+// solver; other strategies, such as a Gauss-Seidel or sequential-impulse solver, can be plugged
+// into a ParticleWorld through its Solver field.
+type ContactSolver interface {
+	// Resolve resolves the contacts, generated for a step of the given duration.
+	Resolve(contacts []ParticleContact, duration float64)
+}
+
+// ParticleContactResolver is the contact resolution routine for particle contacts. One
+// resolver instance can be shared for the entire simulation.
+type ParticleContactResolver struct {
+	// Iterations is the number of iterations allowed per call to ResolveContacts.
+	Iterations int
+	// Coalesce, if true, runs a pre-pass before resolution that sorts the contacts by severity
+	// and merges near-duplicates: contacts between the same particles with nearly the same normal.
+	// Only the deepest of a set of duplicates is kept, so it isn't resolved several times over.
+	Coalesce bool
+	// CoalesceTolerance is how far the dot product of two unit normals may fall below 1 for the
+	// contacts to be merged. Zero or less uses a default of 1e-3, roughly 2.5 degrees.
+	CoalesceTolerance float64
+	// Beta, if between 0 and 1, enables Baumgarte-style soft position correction: each contact has
+	// only that fraction of its penetration removed per call to ResolveContacts, rather than all of
+	// it, so deep overlaps are pushed apart gradually over several steps instead of in a single
+	// energetic jump. Zero or less, or 1 or more, corrects penetration fully.
+	Beta float64
+	// WarmStart, if true, makes the resolver remember the impulse each pair of particles needed
+	// in the last call to ResolveContacts, and apply WarmStartFactor of it up front the next time
+	// the pair is in contact. Resting contacts then start each frame already supported, rather than
+	// being rebuilt from nothing, so stacks settle with less jitter.
+	//
+	// Pairs are identified by their particle pointers, which stay the same from frame to frame.
+	// If a pair has several contacts, their total impulse is cached, and reapplied through the
+	// first of them.
+	WarmStart bool
+	// WarmStartFactor is the fraction of the cached impulse reapplied when warm starting. Applying
+	// slightly less than all of it keeps an old impulse from pushing particles apart. Zero or less
+	// uses a default of 0.8.
+	WarmStartFactor float64
+	// warmImpulses holds the impulse each particle pair needed in the last call, for warm starting.
+	warmImpulses map[[2]*Particle]float64
+	// iterationsUsed records the actual number of iterations used in the last call.
+	iterationsUsed int
+}
+
+// defaultWarmStartFactor is the WarmStartFactor used when none is set.
+const defaultWarmStartFactor = 0.8
+
+// defaultCoalesceTolerance is the CoalesceTolerance used when none is set.
+const defaultCoalesceTolerance = 1e-3
+
+// NewParticleContactResolver creates a contact resolver allowed to use the given number of iterations.
+func NewParticleContactResolver(iterations int) *ParticleContactResolver {
+	return &ParticleContactResolver{
+		Iterations: iterations,
+	}
+}
+
+// IterationsUsed returns the number of iterations used by the last call to ResolveContacts.
+func (r *ParticleContactResolver) IterationsUsed() int {
+	return r.iterationsUsed
+}
+
+// ResolveContacts resolves a set of particle contacts for both penetration and velocity.
+//
+// Each iteration resolves the contact with the most negative separating velocity, so the
+// most severe collisions are handled first.
+func (r *ParticleContactResolver) ResolveContacts(contacts []ParticleContact, duration float64) {
+	if r.Coalesce {
+		contacts = r.coalesce(contacts)
+	}
+
+	if r.WarmStart {
+		r.warmStart(contacts)
+	}
+
+	// With soft correction, each contact's penetration is only corrected once per call; otherwise
+	// later iterations would keep correcting the remainder, and undo the softening.
+	correction := 1.0
+	var corrected []bool
+	if r.Beta > 0 && r.Beta < 1 {
+		correction = r.Beta
+		corrected = make([]bool, len(contacts))
+	}
+
+	r.iterationsUsed = 0
+	for r.iterationsUsed < r.Iterations {
+		// Find the contact with the largest closing velocity.
+		maxVelocity := math.Inf(1)
+		maxIndex := -1
+		for i := range contacts {
+			sepVel := contacts[i].separatingVelocity()
+			penetrating := contacts[i].Penetration > 0 && (corrected == nil || !corrected[i])
+			if sepVel < maxVelocity && (sepVel < 0 || penetrating) {
+				maxVelocity = sepVel
+				maxIndex = i
+			}
+		}
+
+		// Nothing left worth resolving.
+		if maxIndex < 0 {
+			break
+		}
+
+		resolved := &contacts[maxIndex]
+		fraction := correction
+		if corrected != nil {
+			if corrected[maxIndex] {
+				fraction = 0 // Only the velocity is left to resolve.
+			}
+			corrected[maxIndex] = true
+		}
+		resolved.resolve(duration, fraction)
+
+		// Update the interpenetrations of every contact that shares a particle with the resolved one.
+		move := resolved.particleMovement
+		for i := range contacts {
+			c := &contacts[i]
+			for j, p := range resolved.Particles {
+				if p == nil {
+					continue
+				}
+				if c.Particles[0] == p {
+					c.Penetration -= move[j].Dot(c.ContactNormal)
+				} else if c.Particles[1] == p {
+					c.Penetration += move[j].Dot(c.ContactNormal)
+				}
+			}
+		}
+
+		r.iterationsUsed++
+	}
+
+	if r.WarmStart {
+		r.cacheImpulses(contacts)
+	}
+}
+
+// warmStart applies a fraction of the impulse each contact's particle pair needed last time.
+func (r *ParticleContactResolver) warmStart(contacts []ParticleContact) {
+	factor := r.WarmStartFactor
+	if factor <= 0 {
+		factor = defaultWarmStartFactor
+	}
+
+	for i := range contacts {
+		c := &contacts[i]
+		c.normalImpulse = 0
+
+		impulse := r.warmImpulses[c.Particles] * factor
+		totalInverseMass := c.totalInverseMass()
+		if impulse <= 0 || totalInverseMass <= 0 {
+			continue
+		}
+		// Never push the pair apart; the cached impulse only gives a head start on stopping them.
+		impulse = math.Min(impulse, -c.separatingVelocity()/totalInverseMass)
+		if impulse <= 0 {
+			continue
+		}
+		// The cache is rebuilt after resolution, so removing the pair here just stops its impulse
+		// being applied again through its other contacts.
+		delete(r.warmImpulses, c.Particles)
+
+		c.applyImpulse(c.ContactNormal.ScaleCopy(impulse))
+		c.normalImpulse = impulse
+	}
+}
+
+// cacheImpulses records the total impulse each particle pair needed, replacing the last call's
+// cache so pairs no longer in contact are forgotten.
+func (r *ParticleContactResolver) cacheImpulses(contacts []ParticleContact) {
+	if r.warmImpulses == nil {
+		r.warmImpulses = make(map[[2]*Particle]float64, len(contacts))
+	} else {
+		clear(r.warmImpulses)
+	}
+
+	for i := range contacts {
+		c := &contacts[i]
+		if c.normalImpulse > 0 {
+			r.warmImpulses[c.Particles] += c.normalImpulse
+		}
+	}
+}
+
+// Resolve implements ContactSolver by calling ResolveContacts.
+func (r *ParticleContactResolver) Resolve(contacts []ParticleContact, duration float64) {
+	r.ResolveContacts(contacts, duration)
+}
+
+// coalesce sorts the contacts from most to least severe closing velocity, then merges contacts
+// between the same pair of particles whose normals agree to within CoalesceTolerance. It compacts
+// the contacts in place and returns the shortened slice.
+func (r *ParticleContactResolver) coalesce(contacts []ParticleContact) []ParticleContact {
+	tolerance := r.CoalesceTolerance
+	if tolerance <= 0 {
+		tolerance = defaultCoalesceTolerance
+	}
+
+	sort.SliceStable(contacts, func(i, j int) bool {
+		return contacts[i].separatingVelocity() < contacts[j].separatingVelocity()
+	})
+
+	merged := contacts[:0]
+next:
+	for _, c := range contacts {
+		for i := range merged {
+			m := &merged[i]
+			if m.Particles == c.Particles && m.ContactNormal.Dot(c.ContactNormal) >= 1-tolerance {
+				// Keep the deepest penetration, bounciest restitution and roughest friction of the duplicates.
+				// A RestitutionFunc is kept from the most severe contact, which comes first.
+				m.Penetration = math.Max(m.Penetration, c.Penetration)
+				m.Restitution = math.Max(m.Restitution, c.Restitution)
+				m.Friction = math.Max(m.Friction, c.Friction)
+				continue next
+			}
+		}
+		merged = append(merged, c)
+	}
+
+	return merged
+}
+
+// ParticleContactGenerator is the basic interface for contact generators applying to particles.
+type ParticleContactGenerator interface {
+	// AddContact fills the given slice with generated contacts, using at most len(contacts)
+	// entries, and returns the number of contacts written.
+	AddContact(contacts []ParticleContact) int
+}
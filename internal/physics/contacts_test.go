@@ -0,0 +1,380 @@
+package physics
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+// redundantGroundContacts returns a particle falling into the ground at 2 m/s, and a ground
+// contact on it for each depth, as when several overlapping ground generators cover one floor.
+func redundantGroundContacts(depths ...float64) (*Particle, []ParticleContact) {
+	p := newTestParticle(math64.NewVector3(0, -0.4, 0), math64.NewVector3(0, -2, 0), 1)
+	contacts := make([]ParticleContact, len(depths))
+	for i, depth := range depths {
+		contacts[i] = ParticleContact{
+			Particles:     [2]*Particle{p, nil},
+			ContactNormal: math64.NewVector3(0, 1, 0),
+			Penetration:   depth,
+			Restitution:   0.5,
+		}
+	}
+	return p, contacts
+}
+
+func TestResolverCoalesceConvergesFaster(t *testing.T) {
+	tests := []struct {
+		name           string
+		coalesce       bool
+		wantIterations int
+	}{
+		// Each redundant contact is resolved in turn, as the shallower ones leave the deeper ones
+		// still penetrating.
+		{"without coalescing", false, 4},
+		{"with coalescing", true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, contacts := redundantGroundContacts(0.1, 0.2, 0.3, 0.4)
+			resolver := NewParticleContactResolver(100)
+			resolver.Coalesce = tt.coalesce
+			resolver.ResolveContacts(contacts, 0.016)
+
+			if got := resolver.IterationsUsed(); got != tt.wantIterations {
+				t.Errorf("IterationsUsed() = %d, want %d", got, tt.wantIterations)
+			}
+			// Either way the particle ends up bouncing out of the deepest overlap.
+			if !p.Velocity.ApproxEqual(math64.NewVector3(0, 1, 0), 1e-9) {
+				t.Errorf("velocity after resolving = %v, want (0, 1, 0)", p.Velocity)
+			}
+			if !math64.ApproxEqual(p.Position.Y, 0, 1e-9) {
+				t.Errorf("height after resolving = %v, want 0", p.Position.Y)
+			}
+		})
+	}
+}
+
+func TestResolverCoalesce(t *testing.T) {
+	a := newTestParticle(math64.Vector3{}, math64.NewVector3(0, -1, 0), 1)
+	b := newTestParticle(math64.Vector3{}, math64.NewVector3(0, -3, 0), 1)
+	up, tilted := math64.NewVector3(0, 1, 0), math64.NewVector3(0, 1, 0.01).Normalize()
+	side := math64.NewVector3(1, 0, 0)
+	contacts := []ParticleContact{
+		{Particles: [2]*Particle{a, nil}, ContactNormal: up, Penetration: 0.1, Friction: 0.5},
+		{Particles: [2]*Particle{b, nil}, ContactNormal: up, Penetration: 0.3},
+		{Particles: [2]*Particle{a, nil}, ContactNormal: tilted, Penetration: 0.2, Restitution: 0.7},
+		{Particles: [2]*Particle{a, nil}, ContactNormal: side, Penetration: 0.4},
+		{Particles: [2]*Particle{a, b}, ContactNormal: up, Penetration: 0.1},
+	}
+
+	merged := NewParticleContactResolver(0).coalesce(contacts)
+
+	// Sorted by separating velocity: b on the ground, then a on the ground and (a, b), then a's wall.
+	want := []struct {
+		particles   [2]*Particle
+		normal      math64.Vector3
+		penetration float64
+		restitution float64
+		friction    float64
+	}{
+		{[2]*Particle{b, nil}, up, 0.3, 0, 0},
+		{[2]*Particle{a, nil}, up, 0.2, 0.7, 0.5},
+		{[2]*Particle{a, nil}, side, 0.4, 0, 0},
+		{[2]*Particle{a, b}, up, 0.1, 0, 0},
+	}
+	if len(merged) != len(want) {
+		t.Fatalf("coalesce kept %d contacts, want %d", len(merged), len(want))
+	}
+	for i, w := range want {
+		c := merged[i]
+		if c.Particles != w.particles || c.ContactNormal != w.normal || c.Penetration != w.penetration ||
+			c.Restitution != w.restitution || c.Friction != w.friction {
+			t.Errorf("contact %d = %+v, want %+v", i, c, w)
+		}
+	}
+}
+
+func TestContactFriction(t *testing.T) {
+	tests := []struct {
+		name     string
+		friction float64
+		want     math64.Vector3
+	}{
+		// The particle hits the ground at 1 m/s while sliding at 3 m/s, so the normal impulse is 1.
+		{"frictionless", 0, math64.NewVector3(3, 0, 0)},
+		{"clamped to the normal impulse", 0.5, math64.NewVector3(2.5, 0, 0)},
+		{"rougher", 2, math64.NewVector3(1, 0, 0)},
+		{"stops sliding without reversing", 10, math64.Vector3{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(math64.Vector3{}, math64.NewVector3(3, -1, 0), 1)
+			c := ParticleContact{
+				Particles:     [2]*Particle{p, nil},
+				ContactNormal: math64.NewVector3(0, 1, 0),
+				Friction:      tt.friction,
+			}
+			c.resolveVelocity(0.016)
+			if !p.Velocity.ApproxEqual(tt.want, 1e-9) {
+				t.Errorf("velocity after resolving = %v, want %v", p.Velocity, tt.want)
+			}
+		})
+	}
+}
+
+func TestContactBasis(t *testing.T) {
+	tests := []struct {
+		name              string
+		normal, reference math64.Vector3
+		wantErr           bool
+	}{
+		{"ground", math64.NewVector3(0, 1, 0), math64.NewVector3(1, 0, 0), false},
+		{"unnormalized", math64.NewVector3(0, 0, 5), math64.NewVector3(2, 2, 0), false},
+		{"skewed reference", math64.NewVector3(1, 1, 0), math64.NewVector3(0, 1, 1), false},
+		{"parallel", math64.NewVector3(0, 1, 0), math64.NewVector3(0, 3, 0), true},
+		{"anti-parallel", math64.NewVector3(1, 2, 3), math64.NewVector3(-1, -2, -3), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, tangent, bitangent, err := ContactBasis(tt.normal, tt.reference)
+			if tt.wantErr {
+				var physicsErr *PhysicsError
+				if !errors.As(err, &physicsErr) || physicsErr.Code != ErrParallelVectors {
+					t.Fatalf("ContactBasis error = %v, want code %v", err, ErrParallelVectors)
+				}
+				if !errors.Is(err, math64.ErrParallelVectors) {
+					t.Errorf("ContactBasis error %v doesn't wrap math64.ErrParallelVectors", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ContactBasis error = %v", err)
+			}
+
+			if want := tt.normal.Normalize(); !n.ApproxEqual(want, 1e-12) {
+				t.Errorf("normal = %v, want %v", n, want)
+			}
+			for _, v := range []math64.Vector3{tangent, bitangent} {
+				if !math64.ApproxEqual(v.Magnitude(), 1, 1e-12) || !math64.ApproxEqual(v.Dot(n), 0, 1e-12) {
+					t.Errorf("tangent %v is not a unit vector in the contact plane", v)
+				}
+			}
+			if !math64.ApproxEqual(tangent.Dot(bitangent), 0, 1e-12) {
+				t.Errorf("tangents %v and %v are not orthogonal", tangent, bitangent)
+			}
+			// The first tangent lies in the plane of the normal and the reference.
+			if d := tt.normal.Cross(tt.reference).Dot(tangent); !math64.ApproxEqual(d, 0, 1e-9) {
+				t.Errorf("tangent %v is out of the plane of %v and %v", tangent, tt.normal, tt.reference)
+			}
+		})
+	}
+}
+
+func TestGroundFrictionSliding(t *testing.T) {
+	tests := []struct {
+		name     string
+		friction float64
+		want     float64 // Sliding speed after half a second.
+	}{
+		{"frictionless", 0, 5},
+		// Friction decelerates the particle at mu*g = 5 m/s^2.
+		{"with friction", 0.5, 2.5},
+		{"stops", 2, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParticleMass(math64.Vector3{}, math64.NewVector3(5, 0, 0), math64.NewVector3(0, -10, 0), 1, 1)
+			w := NewParticleWorld(10, 0)
+			w.AddParticle(&p)
+			w.AddContactGenerator(NewGroundContactGenerator([]*Particle{&p}, 0, 0, tt.friction))
+			for i := 0; i < 50; i++ {
+				if err := w.RunPhysics(0.01); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !math64.ApproxEqual(p.Velocity.X, tt.want, 0.1) {
+				t.Errorf("sliding speed after 0.5s = %v, want %v", p.Velocity.X, tt.want)
+			}
+			if p.Velocity.X < 0 {
+				t.Errorf("friction reversed the particle to %v", p.Velocity.X)
+			}
+		})
+	}
+}
+
+func TestContactRestitutionCurve(t *testing.T) {
+	// The curve bounces fully below 1 m/s, then loses bounce as the impact gets harder.
+	curve := func(impactSpeed float64) float64 {
+		if impactSpeed <= 1 {
+			return 1
+		}
+		return 1 / impactSpeed
+	}
+	tests := []struct {
+		name        string
+		impactSpeed float64
+		restitution float64
+		curve       func(float64) float64
+		wantBounce  float64
+	}{
+		{"constant", 4, 0.5, nil, 2},
+		{"constant at low speed", 0.5, 0.5, nil, 0.25},
+		{"curve at low speed", 0.5, 0.5, curve, 0.5},
+		{"curve at high speed", 4, 0.5, curve, 1},
+		{"curve at higher speed", 10, 0.5, curve, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(math64.Vector3{}, math64.NewVector3(0, -tt.impactSpeed, 0), 1)
+			c := ParticleContact{
+				Particles:       [2]*Particle{p, nil},
+				ContactNormal:   math64.NewVector3(0, 1, 0),
+				Restitution:     tt.restitution,
+				RestitutionFunc: tt.curve,
+			}
+			c.resolveVelocity(0.016)
+			if !math64.ApproxEqual(p.Velocity.Y, tt.wantBounce, 1e-12) {
+				t.Errorf("rebound speed = %v, want %v", p.Velocity.Y, tt.wantBounce)
+			}
+		})
+	}
+}
+
+func TestResolverBeta(t *testing.T) {
+	tests := []struct {
+		name  string
+		beta  float64
+		depth []float64 // Penetration after each step.
+	}{
+		{"unset corrects fully", 0, []float64{0, 0, 0}},
+		{"one corrects fully", 1, []float64{0, 0, 0}},
+		{"above one corrects fully", 1.5, []float64{0, 0, 0}},
+		{"half", 0.5, []float64{0.5, 0.25, 0.125}},
+		{"quarter", 0.25, []float64{0.75, 0.5625, 0.421875}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A resting particle sunk a metre into the ground. Spare iterations must not keep
+			// correcting the remaining penetration within a step.
+			w := NewParticleWorld(1, 4)
+			w.Resolver().Beta = tt.beta
+			p := newTestParticle(math64.NewVector3(0, -1, 0), math64.Vector3{}, 1)
+			w.AddParticle(p)
+			w.AddContactGenerator(NewGroundContactGenerator([]*Particle{p}, 0, 0, 0))
+
+			for step, want := range tt.depth {
+				if err := w.RunPhysics(0.01); err != nil {
+					t.Fatal(err)
+				}
+				if depth := -p.Position.Y; !math64.ApproxEqual(depth, want, 1e-12) {
+					t.Errorf("step %d: penetration = %v, want %v", step, depth, want)
+				}
+				// Position correction alone adds no velocity, so the particle doesn't jump out.
+				if !p.Velocity.IsZero() {
+					t.Errorf("step %d: velocity = %v, want zero", step, p.Velocity)
+				}
+			}
+		})
+	}
+}
+
+// stackJitter drops a column of height unit-diameter particles onto the ground, lets it settle for
+// five seconds, and returns the mean change in velocity per particle per frame over the next five.
+// In a perfectly resting stack the contacts cancel gravity's pull every frame, so the velocities
+// never change; jitter shows up as changes.
+func stackJitter(t *testing.T, height, iterations int, warmStart bool) float64 {
+	t.Helper()
+	w := NewParticleWorld(2*height, iterations)
+	w.Resolver().WarmStart = warmStart
+	var particles []*Particle
+	for i := 0; i < height; i++ {
+		p := newTestParticle(math64.NewVector3(0, 0.5+float64(i), 0), math64.Vector3{}, 1)
+		p.Acceleration = math64.NewVector3(0, -9.81, 0)
+		particles = append(particles, p)
+		w.AddParticle(p)
+	}
+	w.AddContactGenerator(NewGroundContactGenerator(particles, 0.5, 0, 0))
+	w.AddContactGenerator(NewParticleCollisionGenerator(particles, 0.5, 0))
+
+	const settle, measure = 300, 300
+	total := 0.0
+	before := make([]math64.Vector3, height)
+	for step := 0; step < settle+measure; step++ {
+		for i, p := range particles {
+			before[i] = p.Velocity
+		}
+		w.StartFrame()
+		if err := w.RunPhysics(1.0 / 60); err != nil {
+			t.Fatal(err)
+		}
+		if step >= settle {
+			for i, p := range particles {
+				total += p.Velocity.Distance(before[i])
+			}
+		}
+	}
+	return total / measure / float64(height)
+}
+
+func TestResolverWarmStartReducesJitter(t *testing.T) {
+	// Too few iterations leave a stack's contacts partly unresolved each frame, which is where
+	// carrying impulses over from the last frame helps.
+	for _, iterations := range []int{1, 2, 4, 8} {
+		t.Run(strconv.Itoa(iterations), func(t *testing.T) {
+			var cold, warm float64
+			for height := 3; height <= 8; height++ {
+				cold += stackJitter(t, height, iterations, false)
+				warm += stackJitter(t, height, iterations, true)
+			}
+			if warm >= cold {
+				t.Errorf("stacks jitter by %v with warm starting, want less than %v without", warm, cold)
+			}
+		})
+	}
+}
+
+func TestResolverWarmStartCache(t *testing.T) {
+	tests := []struct {
+		name         string
+		secondSpeed  float64 // Closing speed of the pair's contact in the second call; negative separates.
+		wantWarmPush bool
+	}{
+		{"resting contact is warm started", 1, true},
+		{"separating contact is not pushed", -1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewParticleContactResolver(4)
+			r.WarmStart = true
+			p := newTestParticle(math64.Vector3{}, math64.NewVector3(0, -2, 0), 1)
+			contact := func() []ParticleContact {
+				return []ParticleContact{{
+					Particles:     [2]*Particle{p, nil},
+					ContactNormal: math64.NewVector3(0, 1, 0),
+				}}
+			}
+
+			// The first call stops the particle with an impulse of 2 and caches it.
+			r.ResolveContacts(contact(), 0.01)
+			if p.Velocity.Y != 0 {
+				t.Fatalf("velocity after the first call = %v, want the particle stopped", p.Velocity)
+			}
+
+			// Only warm start the second call, so its effect can be seen on its own.
+			p.Velocity = math64.NewVector3(0, -tt.secondSpeed, 0)
+			r.Iterations = 0
+			r.ResolveContacts(contact(), 0.01)
+
+			// A warm start applies 0.8 of the cached impulse, capped so the pair is never pushed apart.
+			want := -tt.secondSpeed
+			if tt.wantWarmPush {
+				want = 0
+			}
+			if !math64.ApproxEqual(p.Velocity.Y, want, 1e-12) {
+				t.Errorf("velocity after warm starting = %v, want %v", p.Velocity.Y, want)
+			}
+		})
+	}
+}
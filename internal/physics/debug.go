@@ -0,0 +1,55 @@
+package physics
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// debugChecks is true while invariant checks are enabled by SetDebugChecks.
+var debugChecks atomic.Bool
+
+// SetDebugChecks turns on or off invariant checking after every particle integration, for
+// catching bugs during development. While enabled, Integrate, IntegrateSubstepped and
+// IntegrateVelocityVerlet panic with a *PhysicsError as soon as a particle is left with a NaN or
+// infinite position, velocity or acceleration, a negative or NaN inverse mass, or a damping, or
+// per-axis damping, outside the range (0, 1].
+//
+// The setting is process-wide, and disabled by default. When disabled, each integration only
+// pays for a single atomic load.
+func SetDebugChecks(enabled bool) {
+	debugChecks.Store(enabled)
+}
+
+// DebugChecks reports whether invariant checks are enabled by SetDebugChecks.
+func DebugChecks() bool {
+	return debugChecks.Load()
+}
+
+// checkInvariants panics if the particle has broken an invariant, when debug checks are enabled.
+func (p *Particle) checkInvariants() {
+	if !debugChecks.Load() {
+		return
+	}
+	if err := p.invariantError(); err != nil {
+		panic(err)
+	}
+}
+
+// invariantError returns an error describing the first invariant the particle breaks, if any.
+func (p *Particle) invariantError() error {
+	switch {
+	case !p.Position.IsFinite():
+		return newPhysicsError(ErrNonFinite, fmt.Sprintf("position %v is not finite", p.Position))
+	case !p.Velocity.IsFinite():
+		return newPhysicsError(ErrNonFinite, fmt.Sprintf("velocity %v is not finite", p.Velocity))
+	case !p.Acceleration.IsFinite():
+		return newPhysicsError(ErrNonFinite, fmt.Sprintf("acceleration %v is not finite", p.Acceleration))
+	case !(p.inverseMass >= 0):
+		return newPhysicsError(ErrNonFinite, fmt.Sprintf("inverse mass %v is negative or NaN", p.inverseMass))
+	case !inDampingRange(p.Damping):
+		return newPhysicsError(ErrInvalidDamping, fmt.Sprintf("damping %v is outside the range (0, 1]", p.Damping))
+	case !p.DampingAxes.IsZero() && !(inDampingRange(p.DampingAxes.X) && inDampingRange(p.DampingAxes.Y) && inDampingRange(p.DampingAxes.Z)):
+		return newPhysicsError(ErrInvalidDamping, fmt.Sprintf("per-axis damping %v is outside the range (0, 1]", p.DampingAxes))
+	}
+	return nil
+}
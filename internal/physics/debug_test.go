@@ -0,0 +1,154 @@
+package physics
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+// integratePanic integrates the particle with integrate and returns the value it panicked with,
+// or nil if it didn't panic.
+func integratePanic(p *Particle, integrate func(p *Particle) error) (recovered any) {
+	defer func() {
+		recovered = recover()
+	}()
+	integrate(p)
+	return nil
+}
+
+func TestDebugChecks(t *testing.T) {
+	t.Cleanup(func() { SetDebugChecks(false) })
+
+	corruptions := []struct {
+		name     string
+		corrupt  func(p *Particle)
+		wantCode ErrorCode
+	}{
+		{"NaN position", func(p *Particle) { p.Position.X = math.NaN() }, ErrNonFinite},
+		{"infinite velocity", func(p *Particle) { p.Velocity.Y = math.Inf(1) }, ErrNonFinite},
+		{"NaN acceleration", func(p *Particle) { p.Acceleration.Z = math.NaN() }, ErrNonFinite},
+		{"zero damping", func(p *Particle) { p.Damping = 0 }, ErrInvalidDamping},
+		{"damping above one", func(p *Particle) { p.Damping = 1.5 }, ErrInvalidDamping},
+		{"per-axis damping above one", func(p *Particle) { p.DampingAxes = math64.NewVector3(1, 1.5, 1) }, ErrInvalidDamping},
+	}
+	integrators := []struct {
+		name      string
+		integrate func(p *Particle) error
+	}{
+		{"Integrate", func(p *Particle) error { return p.Integrate(0.01) }},
+		{"IntegrateSubstepped", func(p *Particle) error { return p.IntegrateSubstepped(0.01, 4) }},
+		{"IntegrateVelocityVerlet", func(p *Particle) error { return p.IntegrateVelocityVerlet(0.01, nil) }},
+	}
+	for _, integrator := range integrators {
+		for _, tt := range corruptions {
+			t.Run(integrator.name+"/"+tt.name, func(t *testing.T) {
+				for _, enabled := range []bool{true, false} {
+					SetDebugChecks(enabled)
+					if DebugChecks() != enabled {
+						t.Fatalf("DebugChecks() = %v after SetDebugChecks(%v)", DebugChecks(), enabled)
+					}
+
+					p := newTestParticle(math64.Vector3{}, math64.NewVector3(1, 0, 0), 1)
+					tt.corrupt(p)
+					recovered := integratePanic(p, integrator.integrate)
+
+					if !enabled {
+						if recovered != nil {
+							t.Errorf("with checks disabled, integrating panicked with %v", recovered)
+						}
+						continue
+					}
+					err, ok := recovered.(*PhysicsError)
+					if !ok {
+						t.Fatalf("with checks enabled, integrating panicked with %v, want a *PhysicsError", recovered)
+					}
+					if !errors.Is(err, tt.wantCode) {
+						t.Errorf("panicked with %v, want %v", err, tt.wantCode)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestDebugChecksHealthyParticle(t *testing.T) {
+	t.Cleanup(func() { SetDebugChecks(false) })
+	SetDebugChecks(true)
+
+	p := newTestParticle(math64.NewVector3(1, 2, 3), math64.NewVector3(4, 5, 6), 2)
+	p.Acceleration = math64.NewVector3(0, -9.81, 0)
+	p.DampingAxes = math64.NewVector3(1, 0.5, 1)
+	for i := 0; i < 100; i++ {
+		if recovered := integratePanic(p, func(p *Particle) error { return p.Integrate(0.01) }); recovered != nil {
+			t.Fatalf("step %d: a healthy particle panicked with %v", i, recovered)
+		}
+	}
+}
+
+func TestInvariantErrorInverseMass(t *testing.T) {
+	tests := []struct {
+		name        string
+		inverseMass float64
+		wantErr     bool
+	}{
+		{"finite", 0.5, false},
+		{"infinite mass", 0, false},
+		{"negative", -1, true},
+		{"NaN", math.NaN(), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(math64.Vector3{}, math64.Vector3{}, 1)
+			p.inverseMass = tt.inverseMass
+			if err := p.invariantError(); (err != nil) != tt.wantErr {
+				t.Errorf("invariantError() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDebugChecksSpawnedParticle(t *testing.T) {
+	t.Cleanup(func() { SetDebugChecks(false) })
+	SetDebugChecks(true)
+
+	tests := []struct {
+		name    string
+		acquire func() *Particle
+	}{
+		{"pool", func() *Particle { return NewParticlePool(1).Acquire() }},
+		{"empty pool", func() *Particle { return NewParticlePool(0).Acquire() }},
+		{"released to a pool", func() *Particle {
+			pool := NewParticlePool(1)
+			p := pool.Acquire()
+			p.Damping = 0.5
+			pool.Release(p)
+			return pool.Acquire()
+		}},
+		{"world with a pool", func() *Particle {
+			w := NewParticleWorld(0, 0)
+			w.Pool = NewParticlePool(1)
+			return w.SpawnParticle()
+		}},
+		{"world without a pool", func() *Particle { return NewParticleWorld(0, 0).SpawnParticle() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.acquire()
+			if p.Damping != DefaultDamping {
+				t.Errorf("Damping = %v, want DefaultDamping", p.Damping)
+			}
+
+			// Set up only as far as a caller must, without touching the damping.
+			p.Velocity = math64.NewVector3(1, 0, 0)
+			p.SetMass(1)
+			if recovered := integratePanic(p, func(p *Particle) error { return p.Integrate(0.01) }); recovered != nil {
+				t.Fatalf("integrating with checks enabled panicked with %v", recovered)
+			}
+			if p.Velocity.X != 1 {
+				t.Errorf("velocity = %v, want it undamped", p.Velocity)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package physics
+
+import (
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+func TestDragGeneratorOpposesMotion(t *testing.T) {
+	tests := []struct {
+		name     string
+		k1, k2   float64
+		velocity math64.Vector3
+		want     math64.Vector3
+	}{
+		{"linear term", 0.5, 0, math64.NewVector3(4, 0, 0), math64.NewVector3(-2, 0, 0)},
+		{"squared term", 0, 0.5, math64.NewVector3(0, -4, 0), math64.NewVector3(0, 8, 0)},
+		{"both terms", 1, 0.25, math64.NewVector3(0, 0, 2), math64.NewVector3(0, 0, -3)},
+		{"diagonal", 0, 1, math64.NewVector3(3, 4, 0), math64.NewVector3(-15, -20, 0)},
+		{"at rest", 1, 1, math64.Vector3{}, math64.Vector3{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParticleMass(math64.Vector3{}, tt.velocity, math64.Vector3{}, 1, 1)
+			NewDragGenerator(tt.k1, tt.k2).UpdateForce(&p, 0.01)
+			if !p.forceAccumulator.ApproxEqual(tt.want, 1e-12) {
+				t.Errorf("drag = %v, want %v", p.forceAccumulator, tt.want)
+			}
+			if tt.velocity.Dot(p.forceAccumulator) > 0 {
+				t.Errorf("drag %v pushes along the velocity %v", p.forceAccumulator, tt.velocity)
+			}
+		})
+	}
+}
+
+func TestDragGeneratorSquaredTermQuadruples(t *testing.T) {
+	drag := NewDragGenerator(0, 0.1)
+	slow := NewParticleMass(math64.Vector3{}, math64.NewVector3(5, 0, 0), math64.Vector3{}, 1, 1)
+	fast := NewParticleMass(math64.Vector3{}, math64.NewVector3(10, 0, 0), math64.Vector3{}, 1, 1)
+	drag.UpdateForce(&slow, 0.01)
+	drag.UpdateForce(&fast, 0.01)
+
+	if got, want := fast.forceAccumulator.Magnitude(), 4*slow.forceAccumulator.Magnitude(); !math64.ApproxEqual(got, want, 1e-12) {
+		t.Errorf("drag at double the speed = %v, want four times %v", got, slow.forceAccumulator.Magnitude())
+	}
+}
@@ -0,0 +1,94 @@
+package physics
+
+import "fmt"
+
+// ErrorCode represents different specific error codes the engine can throw out.
+//
+// An ErrorCode is itself an error, so it can be used as a sentinel with errors.Is:
+//
+//	if errors.Is(err, physics.ErrInfiniteMass) { ... }
+type ErrorCode int
+
+const (
+	ErrUnknown ErrorCode = iota
+	ErrInfiniteMass
+	ErrNegativeDuration
+	ErrParallelVectors // Wraps math64.ErrParallelVectors, such as from ContactBasis.
+	ErrInvalidDamping
+	ErrNonFinite // A NaN or infinite value in the simulation state.
+)
+
+// String returns a human-readable name for the error code.
+func (e ErrorCode) String() string {
+	switch e {
+	case ErrInfiniteMass:
+		return "ErrInfiniteMass"
+	case ErrNegativeDuration:
+		return "ErrNegativeDuration"
+	case ErrParallelVectors:
+		return "ErrParallelVectors"
+	case ErrInvalidDamping:
+		return "ErrInvalidDamping"
+	case ErrNonFinite:
+		return "ErrNonFinite"
+	default:
+		return "ErrUnknown"
+	}
+}
+
+// Error is used to implement the Error interface, allowing an ErrorCode to act as a sentinel.
+func (e ErrorCode) Error() string {
+	return e.String()
+}
+
+// PhysicsError represents specific errors relevant to our physics engine. It carries a
+// machine-readable Code alongside a free-form Message, and optionally the underlying error
+// that caused it.
+type PhysicsError struct {
+	Code    ErrorCode
+	Message string
+	Err     error // Underlying cause, if any.
+}
+
+// Error is used to implement the Error interface.
+func (e *PhysicsError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Unwrap returns the underlying cause of the error, if any.
+func (e *PhysicsError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target matches this error's code. Both a bare ErrorCode and
+// another *PhysicsError with the same Code are considered a match.
+func (e *PhysicsError) Is(target error) bool {
+	switch t := target.(type) {
+	case ErrorCode:
+		return e.Code == t
+	case *PhysicsError:
+		return e.Code == t.Code
+	default:
+		return false
+	}
+}
+
+// newPhysicsError creates a physics error object with a code.
+func newPhysicsError(code ErrorCode, message string) error {
+	return wrapPhysicsError(code, message, nil)
+}
+
+// wrapPhysicsError creates a physics error object with a code around an underlying error.
+//
+// Errors aren't logged where they're created: the package keeps no logger of its own, so that
+// independent worlds share no state. A ParticleWorld logs the errors it returns to its Logger.
+func wrapPhysicsError(code ErrorCode, message string, err error) error {
+	return &PhysicsError{
+		Code:    code,
+		Message: message,
+		Err:     err,
+	}
+}
@@ -0,0 +1,87 @@
+package physics
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+func TestIntegrateErrorCodes(t *testing.T) {
+	tests := []struct {
+		name        string
+		inverseMass float64
+		duration    float64
+		want        ErrorCode
+	}{
+		{"infinite mass", 0, 0.1, ErrInfiniteMass},
+		{"zero duration", 1, 0, ErrNegativeDuration},
+		{"negative duration", 1, -0.1, ErrNegativeDuration},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParticleInverseMass(math64.Vector3{}, math64.Vector3{}, math64.Vector3{}, 1, tt.inverseMass)
+			err := p.Integrate(tt.duration)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Integrate(%v) = %v, want an error matching %v", tt.duration, err, tt.want)
+			}
+
+			var physicsErr *PhysicsError
+			if !errors.As(err, &physicsErr) || physicsErr.Code != tt.want {
+				t.Errorf("errors.As(%v) did not recover code %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPhysicsErrorWrapped(t *testing.T) {
+	cause := errors.New("cause")
+	err := fmt.Errorf("stepping world: %w", wrapPhysicsError(ErrParallelVectors, "bad basis", cause))
+
+	tests := []struct {
+		name   string
+		target error
+		want   bool
+	}{
+		{"code", ErrParallelVectors, true},
+		{"other code", ErrInfiniteMass, false},
+		{"physics error with same code", &PhysicsError{Code: ErrParallelVectors}, true},
+		{"physics error with other code", &PhysicsError{Code: ErrInvalidDamping}, false},
+		{"underlying cause", cause, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(err, tt.target); got != tt.want {
+				t.Errorf("errors.Is(%v, %v) = %v, want %v", err, tt.target, got, tt.want)
+			}
+		})
+	}
+
+	var physicsErr *PhysicsError
+	if !errors.As(err, &physicsErr) {
+		t.Fatalf("errors.As(%v) found no *PhysicsError", err)
+	}
+	if physicsErr.Code != ErrParallelVectors {
+		t.Errorf("recovered code %v, want %v", physicsErr.Code, ErrParallelVectors)
+	}
+}
+
+func TestErrorCodeString(t *testing.T) {
+	tests := []struct {
+		code ErrorCode
+		want string
+	}{
+		{ErrUnknown, "ErrUnknown"},
+		{ErrInfiniteMass, "ErrInfiniteMass"},
+		{ErrNegativeDuration, "ErrNegativeDuration"},
+		{ErrParallelVectors, "ErrParallelVectors"},
+		{ErrInvalidDamping, "ErrInvalidDamping"},
+		{ErrNonFinite, "ErrNonFinite"},
+	}
+	for _, tt := range tests {
+		if got := tt.code.Error(); got != tt.want {
+			t.Errorf("ErrorCode(%d).Error() = %q, want %q", int(tt.code), got, tt.want)
+		}
+	}
+}
@@ -2,12 +2,12 @@ package physics
 
 import (
 	"math"
+	"math/rand"
+	"sync"
 
 	"github.com/user54778/cyclone/internal/math64"
 )
 
-// TODO: Add tests for add and removal of forces to the registry.
-
 const G = 6.67430e-11
 
 // ForceGenerator defines an interface for objects that can apply forces to one or more particles.
@@ -20,6 +20,53 @@ type ForceGenerator interface {
 	UpdateForce(particle *Particle, duration float64)
 }
 
+// Enableable is an optional interface for force generators that can be switched on and off
+// without being removed from a ForceRegistry. The registry skips any generator whose Enabled
+// method returns false.
+type Enableable interface {
+	Enabled() bool
+}
+
+// EnabledFlag can be embedded in a force generator to make it Enableable. Its zero value is
+// enabled, so embedding it doesn't change a generator's behavior until SetEnabled(false) is called.
+type EnabledFlag struct {
+	disabled bool
+}
+
+// Enabled reports whether the generator is enabled.
+func (e *EnabledFlag) Enabled() bool {
+	return !e.disabled
+}
+
+// SetEnabled switches the generator on or off.
+func (e *EnabledFlag) SetEnabled(enabled bool) {
+	e.disabled = !enabled
+}
+
+// isEnabled reports whether the force generator should be applied this frame.
+func isEnabled(fg ForceGenerator) bool {
+	if e, ok := fg.(Enableable); ok {
+		return e.Enabled()
+	}
+	return true
+}
+
+// Stateful is an optional interface for force generators whose UpdateForce changes the generator
+// itself, such as one that only fires once or that breaks under load. Dry runs, like
+// PredictTrajectory, skip any generator whose Stateful method returns true, so that looking ahead
+// can't use up or break the real generator.
+type Stateful interface {
+	Stateful() bool
+}
+
+// isStateful reports whether running the force generator would change its state.
+func isStateful(fg ForceGenerator) bool {
+	if s, ok := fg.(Stateful); ok {
+		return s.Stateful()
+	}
+	return false
+}
+
 // ForceRegistry acts as a central registry of particles and force generators, holding
 // a registry type in a slice.
 //
@@ -55,6 +102,34 @@ func (r *ForceRegistry) RemoveForce(particle *Particle, fg ForceGenerator) {
 	}
 }
 
+// RemoveAllForParticle removes every registration for the given particle, regardless of
+// the force generator it is paired with.
+func (r *ForceRegistry) RemoveAllForParticle(particle *Particle) {
+	kept := r.registrations[:0]
+	for _, reg := range r.registrations {
+		if reg.particle != particle {
+			kept = append(kept, reg)
+		}
+	}
+	// Clear the tail so removed particles and generators can be garbage collected.
+	for i := len(kept); i < len(r.registrations); i++ {
+		r.registrations[i] = registry{}
+	}
+	r.registrations = kept
+}
+
+// GeneratorsFor returns every force generator registered against the given particle, in
+// registration order. An unregistered particle returns an empty slice.
+func (r *ForceRegistry) GeneratorsFor(particle *Particle) []ForceGenerator {
+	generators := []ForceGenerator{}
+	for _, reg := range r.registrations {
+		if reg.particle == particle {
+			generators = append(generators, reg.fg)
+		}
+	}
+	return generators
+}
+
 // Clear removes all force generator registrations from the registry *slice*,
 // however, does *not* remove the particles or force generators themselves.
 func (r *ForceRegistry) Clear() {
@@ -62,13 +137,65 @@ func (r *ForceRegistry) Clear() {
 }
 
 // UpdateForces calls all the force generators to update the forces of their
-// corresponding particles.
+// corresponding particles. Disabled generators are skipped.
 func (r *ForceRegistry) UpdateForces(duration float64) {
 	for _, reg := range r.registrations {
+		if !isEnabled(reg.fg) {
+			continue
+		}
 		reg.fg.UpdateForce(reg.particle, duration) // Notice how it calls the Interface function? Neat.
 	}
 }
 
+// ParallelUpdateForces calls all the force generators to update the forces of their corresponding
+// particles, spreading the work across the given number of goroutines.
+//
+// Registrations are partitioned by particle, so every generator for a given particle runs on the
+// same goroutine, in registration order, and no two goroutines write to the same particle's
+// accumulator. A generator registered to several particles may be called concurrently, so it must
+// not modify shared state without synchronization. With fewer than two workers this is
+// equivalent to UpdateForces.
+func (r *ForceRegistry) ParallelUpdateForces(duration float64, workers int) {
+	if workers < 2 {
+		r.UpdateForces(duration)
+		return
+	}
+
+	// Group the registrations by particle, preserving their order within each group.
+	groupIndex := make(map[*Particle]int)
+	var groups [][]registry
+	for _, reg := range r.registrations {
+		i, ok := groupIndex[reg.particle]
+		if !ok {
+			i = len(groups)
+			groupIndex[reg.particle] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], reg)
+	}
+
+	if workers > len(groups) {
+		workers = len(groups)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(groups); i += workers {
+				for _, reg := range groups[i] {
+					if !isEnabled(reg.fg) {
+						continue
+					}
+					reg.fg.UpdateForce(reg.particle, duration)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
 // removeCopy is a helper function to remove an element from the underlying registry
 // slice.
 func removeCopy(registry []registry, i int) []registry {
@@ -103,7 +230,8 @@ func (g *GravityGenerator) UpdateForce(particle *Particle, duration float64) {
 
 	scale := r * r
 
-	force := g.Gravity.ScaleCopy(particle.Mass() * scale)
+	force := g.Gravity
+	force.Scale(particle.Mass() * scale)
 	particle.AddForce(force)
 }
 
@@ -144,12 +272,46 @@ func (p *PointGravityGenerator) UpdateForce(particle *Particle, duration float64
 	particle.AddForce(force)
 }
 
+// RadialGravityGenerator pulls particles towards a fixed Center, such as the middle of a planet,
+// with the same strength at every distance. Unlike PointGravityGenerator, the pull doesn't fall off
+// with distance, so it behaves like ordinary surface gravity whose "down" is always towards the
+// center, and particles fall onto the planet from any side.
+//
+// A particle at the center, or within math64.Epsilon of it, has no direction to fall in, and
+// receives no force.
+type RadialGravityGenerator struct {
+	Center    math64.Vector3 // Point particles are pulled towards.
+	Magnitude float64        // Acceleration towards the center, such as 9.81 for Earth's surface.
+}
+
+func NewRadialGravityGenerator(center math64.Vector3, magnitude float64) *RadialGravityGenerator {
+	return &RadialGravityGenerator{
+		Center:    center,
+		Magnitude: magnitude,
+	}
+}
+
+// UpdateForce pulls the particle towards the center with the mass-scaled force m*Magnitude.
+func (g *RadialGravityGenerator) UpdateForce(particle *Particle, duration float64) {
+	if !particle.HasFiniteMass() {
+		return
+	}
+
+	// Normalize gives the zero vector, and so no force, for a particle at the center.
+	direction := g.Center.SubCopy(particle.Position).Normalize()
+	particle.AddForce(direction.ScaleCopy(particle.Mass() * g.Magnitude))
+}
+
 // DragGenerator is a model to represent a drag force applied to a point mass,
 // where k1 and k2 are two constants that characterize how *strong* the drag force is,
 // named drag coefficients.
+//
+// Drag acts against the particle's velocity relative to the surrounding medium, which moves with
+// MediumVelocity. The zero value is a still medium.
 type DragGenerator struct {
-	K1 float64
-	K2 float64
+	K1             float64
+	K2             float64
+	MediumVelocity math64.Vector3 // Velocity of the surrounding air or water, such as a wind.
 }
 
 func NewDragGenerator(k1, k2 float64) *DragGenerator {
@@ -159,6 +321,41 @@ func NewDragGenerator(k1, k2 float64) *DragGenerator {
 	}
 }
 
+// Physical properties of the media the drag presets model, at around 20 degrees C and sea level.
+const (
+	airDensity     = 1.204  // kg/m^3
+	airViscosity   = 1.8e-5 // Dynamic viscosity, Pa*s.
+	waterDensity   = 998.0  // kg/m^3
+	waterViscosity = 1.0e-3 // Dynamic viscosity, Pa*s.
+)
+
+// NewAirDragGenerator creates a drag generator for a body moving through air, from its
+// cross-sectional area, in m^2, and drag coefficient, Cd. A sphere has a Cd of about 0.47, and a
+// streamlined body about 0.04.
+func NewAirDragGenerator(crossSection, dragCoefficient float64) *DragGenerator {
+	return newMediumDragGenerator(airDensity, airViscosity, crossSection, dragCoefficient)
+}
+
+// NewWaterDragGenerator creates a drag generator for a body moving through water, from its
+// cross-sectional area, in m^2, and drag coefficient, Cd, as in NewAirDragGenerator.
+func NewWaterDragGenerator(crossSection, dragCoefficient float64) *DragGenerator {
+	return newMediumDragGenerator(waterDensity, waterViscosity, crossSection, dragCoefficient)
+}
+
+// newMediumDragGenerator derives drag coefficients for a body in a medium of the given density
+// and viscosity.
+//
+// k2 comes from the drag equation, F = 1/2 * rho * Cd * A * v^2, which dominates at speed. k1
+// comes from Stokes' law, F = 6 * pi * mu * r * v, for a sphere of the same cross-section, which
+// dominates when the body creeps slowly through a viscous medium.
+func newMediumDragGenerator(density, viscosity, crossSection, dragCoefficient float64) *DragGenerator {
+	radius := math.Sqrt(crossSection / math64.Pi)
+	return NewDragGenerator(
+		6*math64.Pi*viscosity*radius,
+		0.5*density*dragCoefficient*crossSection,
+	)
+}
+
 // UpdateForce updates the drag force on a particle.
 //
 // The force acts in the opposite direction to the *velocity* of the object, with a strength
@@ -167,18 +364,42 @@ func NewDragGenerator(k1, k2 float64) *DragGenerator {
 // as for every doubling of speed, the *drag* nearly *quadruples*.
 func (d *DragGenerator) UpdateForce(particle *Particle, duration float64) {
 	// F_drag = -norm(vel(particle))*(k1*norm(vel(particle)) + k2*norm(vel(particle))^2)
+	// where vel(particle) is measured relative to the medium.
 	force := particle.Velocity
+	force.Sub(d.MediumVelocity)
 
 	// Calculate the total drag coefficient
-	dragCoeff := force.Magnitude()
-	dragCoeff = d.K1*dragCoeff + d.K2*dragCoeff
+	speed := force.Magnitude()
+	dragCoeff := d.K1*speed + d.K2*speed*speed
 
-	// Calculate the final force and apply it
-	force = force.Normalize()
-	force = force.ScaleCopy(dragCoeff)
+	// Calculate the final force and apply it. A particle at rest in the medium has no direction to drag against.
+	force, err := force.NormalizeChecked()
+	if err != nil {
+		return
+	}
+	force.Scale(-dragCoeff)
 	particle.AddForce(force)
 }
 
+// TerminalVelocity returns the theoretical speed at which a particle of the given mass, falling
+// under the given gravitational acceleration, feels a drag force equal to its weight in a still
+// medium. It solves k2*v^2 + k1*v = m*|g| for v.
+//
+// Without any drag the particle never stops accelerating, and the result is +Inf.
+func TerminalVelocity(gravity math64.Vector3, mass float64, drag *DragGenerator) float64 {
+	weight := mass * gravity.Magnitude()
+
+	switch {
+	case drag.K2 > 0:
+		// Take the positive root of the quadratic.
+		return (-drag.K1 + math.Sqrt(drag.K1*drag.K1+4*drag.K2*weight)) / (2 * drag.K2)
+	case drag.K1 > 0:
+		return weight / drag.K1
+	default:
+		return math.Inf(1)
+	}
+}
+
 // UpliftForceGenerator represents an uplift force on a particle. An uplift force is simply
 // "any upward pressure applied to a structure (particle) that has the *potential* to raise it relative to its surroundings."
 //
@@ -246,3 +467,592 @@ func (a *AirBrakeForceGenerator) UpdateForce(particle *Particle, duration float6
 		a.NormalDrag.UpdateForce(particle, duration)
 	}
 }
+
+// SpringForceGenerator is a force generator that applies a spring force between the particle it is
+// registered with and the Other particle at the end of the spring.
+//
+// The force only acts on the registered particle, so a two-way spring needs to be registered
+// once for each of its ends.
+type SpringForceGenerator struct {
+	Other          *Particle // The particle at the other end of the spring.
+	SpringConstant float64   // Holds the spring constant, k.
+	RestLength     float64   // Holds the rest length of the spring, l0.
+}
+
+func NewSpringForceGenerator(other *Particle, springConstant, restLength float64) *SpringForceGenerator {
+	return &SpringForceGenerator{
+		Other:          other,
+		SpringConstant: springConstant,
+		RestLength:     restLength,
+	}
+}
+
+// UpdateForce applies the spring force using Hooke's law.
+//
+// f = -k(|d| - l0) * norm(d), where d is the vector from the other end of the spring to the particle.
+func (s *SpringForceGenerator) UpdateForce(particle *Particle, duration float64) {
+	d := particle.Position.SubCopy(s.Other.Position)
+
+	// Calculate the magnitude of the force.
+	magnitude := s.SpringConstant * (d.Magnitude() - s.RestLength)
+
+	// Calculate the final force and apply it.
+	force := d.Normalize().ScaleCopy(-magnitude)
+	particle.AddForce(force)
+}
+
+// ImpulseForceGenerator applies a single impulse to a particle the first time it is updated, and
+// no force after that. The force is the impulse spread over the duration of that first frame.
+//
+// Because it only fires once, each generator should be registered with a single particle.
+type ImpulseForceGenerator struct {
+	Impulse math64.Vector3 // Change in momentum to apply, in kg*m/s.
+	applied bool
+}
+
+func NewImpulseForceGenerator(impulse math64.Vector3) *ImpulseForceGenerator {
+	return &ImpulseForceGenerator{
+		Impulse: impulse,
+	}
+}
+
+// Applied reports whether the impulse has already been applied.
+func (i *ImpulseForceGenerator) Applied() bool {
+	return i.applied
+}
+
+// Stateful reports that the generator is used up by applying its impulse.
+func (i *ImpulseForceGenerator) Stateful() bool {
+	return true
+}
+
+// UpdateForce applies the impulse as a force over the frame's duration, F = J / t.
+func (i *ImpulseForceGenerator) UpdateForce(particle *Particle, duration float64) {
+	if i.applied || duration <= 0 {
+		return
+	}
+
+	particle.AddForce(i.Impulse.ScaleCopy(1.0 / duration))
+	i.applied = true
+}
+
+// ExplosionForceGenerator models the blast of an explosion at a point. Every particle within its
+// radius receives a single outward impulse, strongest at the center and falling off linearly to
+// zero at the edge of the blast.
+type ExplosionForceGenerator struct {
+	Center  math64.Vector3 // Center of the explosion.
+	Radius  float64        // Distance the blast reaches.
+	Impulse float64        // *Magnitude* of the impulse applied at the center.
+	// affected holds every particle the blast has already pushed, so it is only pushed once.
+	// It is guarded by mu, as one blast is registered to many particles.
+	affected map[*Particle]struct{}
+	mu       sync.Mutex
+}
+
+func NewExplosionForceGenerator(center math64.Vector3, radius, impulse float64) *ExplosionForceGenerator {
+	return &ExplosionForceGenerator{
+		Center:   center,
+		Radius:   radius,
+		Impulse:  impulse,
+		affected: make(map[*Particle]struct{}),
+	}
+}
+
+// Stateful reports that the explosion remembers every particle it has pushed.
+func (e *ExplosionForceGenerator) Stateful() bool {
+	return true
+}
+
+// UpdateForce pushes the particle directly away from the center of the explosion, if it is within
+// the blast radius and has not already been pushed.
+func (e *ExplosionForceGenerator) UpdateForce(particle *Particle, duration float64) {
+	if duration <= 0 {
+		return
+	}
+	direction := particle.Position.SubCopy(e.Center)
+	distance := direction.Magnitude()
+	if distance > e.Radius {
+		return
+	}
+
+	// A particle sitting exactly on the center has no direction to be pushed in.
+	direction, err := direction.NormalizeChecked()
+	if err != nil {
+		return
+	}
+
+	e.mu.Lock()
+	_, pushed := e.affected[particle]
+	e.affected[particle] = struct{}{}
+	e.mu.Unlock()
+	if pushed {
+		return
+	}
+
+	impulse := e.Impulse * (1 - distance/e.Radius)
+	particle.AddForce(direction.ScaleCopy(impulse / duration))
+}
+
+// BreakableSpringForceGenerator behaves like a SpringForceGenerator until the force it needs to
+// apply exceeds BreakForce. At that point the spring snaps, and permanently applies no force.
+type BreakableSpringForceGenerator struct {
+	SpringForceGenerator
+	BreakForce float64 // *Magnitude* of force above which the spring snaps.
+	broken     bool
+}
+
+func NewBreakableSpringForceGenerator(other *Particle, springConstant, restLength, breakForce float64) *BreakableSpringForceGenerator {
+	return &BreakableSpringForceGenerator{
+		SpringForceGenerator: *NewSpringForceGenerator(other, springConstant, restLength),
+		BreakForce:           breakForce,
+	}
+}
+
+// Broken reports whether the spring has snapped.
+func (b *BreakableSpringForceGenerator) Broken() bool {
+	return b.broken
+}
+
+// Stateful reports that the spring can snap when it is updated.
+func (b *BreakableSpringForceGenerator) Stateful() bool {
+	return true
+}
+
+// UpdateForce applies the spring force, unless the spring has snapped or this force would snap it.
+func (b *BreakableSpringForceGenerator) UpdateForce(particle *Particle, duration float64) {
+	if b.broken {
+		return
+	}
+
+	d := particle.Position.SubCopy(b.Other.Position)
+	if math.Abs(b.SpringConstant*(d.Magnitude()-b.RestLength)) > b.BreakForce {
+		b.broken = true
+		return
+	}
+
+	b.SpringForceGenerator.UpdateForce(particle, duration)
+}
+
+// limitStiffnessFactor is how many times stiffer a LimitedSpringForceGenerator becomes once it
+// is stretched past its maximum extension, unless configured otherwise.
+const limitStiffnessFactor = 100.0
+
+// LimitedSpringForceGenerator models a tether with a bit of stretch, like a climbing rope. It
+// applies no force while slack, follows Hooke's law while stretched up to MaxExtension past its
+// rest length, and beyond that resists with the much stiffer LimitSpringConstant, acting almost
+// like a cable.
+type LimitedSpringForceGenerator struct {
+	Other               *Particle // The particle at the other end of the tether.
+	SpringConstant      float64   // Holds the spring constant, k, within the stretch limit.
+	RestLength          float64   // Holds the rest length of the tether, l0.
+	MaxExtension        float64   // Holds how far past l0 the tether stretches before it goes stiff.
+	LimitSpringConstant float64   // Holds the spring constant applied to any extension past MaxExtension.
+}
+
+// NewLimitedSpringForceGenerator creates a limited spring whose LimitSpringConstant is 100 times
+// its spring constant.
+func NewLimitedSpringForceGenerator(other *Particle, springConstant, restLength, maxExtension float64) *LimitedSpringForceGenerator {
+	return &LimitedSpringForceGenerator{
+		Other:               other,
+		SpringConstant:      springConstant,
+		RestLength:          restLength,
+		MaxExtension:        maxExtension,
+		LimitSpringConstant: springConstant * limitStiffnessFactor,
+	}
+}
+
+// UpdateForce pulls the particle towards the other end of the tether if it is stretched.
+//
+// f = -(k*min(x, x_max) + k_limit*max(0, x - x_max)) * norm(d), where x = |d| - l0 is the extension.
+func (l *LimitedSpringForceGenerator) UpdateForce(particle *Particle, duration float64) {
+	d := particle.Position.SubCopy(l.Other.Position)
+
+	extension := d.Magnitude() - l.RestLength
+	if extension <= 0 {
+		return // The tether is slack.
+	}
+
+	magnitude := l.SpringConstant * math.Min(extension, l.MaxExtension)
+	if extension > l.MaxExtension {
+		magnitude += l.LimitSpringConstant * (extension - l.MaxExtension)
+	}
+
+	force := d.Normalize()
+	force.Scale(-magnitude)
+	particle.AddForce(force)
+}
+
+// AltitudeGravityGenerator applies gravity towards the center of a planet, weakening with altitude
+// according to the inverse-square law: g(h) = g0 * (R / (R + h))^2.
+type AltitudeGravityGenerator struct {
+	Center         math64.Vector3 // Center of the planet.
+	SurfaceGravity float64        // *Magnitude* of the gravitational acceleration at the surface, g0.
+	PlanetRadius   float64        // Radius of the planet, R.
+}
+
+func NewAltitudeGravityGenerator(center math64.Vector3, surfaceGravity, planetRadius float64) *AltitudeGravityGenerator {
+	return &AltitudeGravityGenerator{
+		Center:         center,
+		SurfaceGravity: surfaceGravity,
+		PlanetRadius:   planetRadius,
+	}
+}
+
+// Acceleration returns the *magnitude* of gravitational acceleration at the given distance from
+// the planet's center, where distance = R + h.
+func (a *AltitudeGravityGenerator) Acceleration(distance float64) float64 {
+	ratio := a.PlanetRadius / distance
+	return a.SurfaceGravity * ratio * ratio
+}
+
+// UpdateForce pulls the particle towards the planet's center with a mass-scaled force.
+func (a *AltitudeGravityGenerator) UpdateForce(particle *Particle, duration float64) {
+	if !particle.HasFiniteMass() {
+		return
+	}
+
+	direction := a.Center.SubCopy(particle.Position)
+	distance := direction.Magnitude()
+
+	// Avoid dividing by zero at the center of the planet.
+	if distance <= 0.0001 {
+		return
+	}
+
+	force := direction.ScaleCopy(1.0 / distance)
+	force.Scale(particle.Mass() * a.Acceleration(distance))
+	particle.AddForce(force)
+}
+
+// FuncForceGenerator adapts an ordinary function into a ForceGenerator, so one-off forces can be
+// defined inline without a new type. The force the function returns is added to the particle.
+type FuncForceGenerator struct {
+	Fn func(particle *Particle, duration float64) math64.Vector3
+}
+
+func NewFuncForceGenerator(fn func(particle *Particle, duration float64) math64.Vector3) *FuncForceGenerator {
+	return &FuncForceGenerator{
+		Fn: fn,
+	}
+}
+
+// UpdateForce applies the force returned by the wrapped function.
+func (f *FuncForceGenerator) UpdateForce(particle *Particle, duration float64) {
+	particle.AddForce(f.Fn(particle, duration))
+}
+
+// RegionForceGenerator applies a constant force to particles inside a region of space, such as
+// buoyancy underwater or an anti-gravity zone, and no force anywhere else.
+//
+// The region is closed: a particle exactly on its surface is inside, and feels the force.
+type RegionForceGenerator struct {
+	Region math64.AABB
+	Force  math64.Vector3
+}
+
+func NewRegionForceGenerator(region math64.AABB, force math64.Vector3) *RegionForceGenerator {
+	return &RegionForceGenerator{
+		Region: region,
+		Force:  force,
+	}
+}
+
+// UpdateForce applies the force if the particle is inside, or on the surface of, the region.
+func (r *RegionForceGenerator) UpdateForce(particle *Particle, duration float64) {
+	if !r.Region.Contains(particle.Position) {
+		return
+	}
+	particle.AddForce(r.Force)
+}
+
+// CentripetalForceGenerator keeps a particle moving in a circle about Center. Each update it applies
+// the inward force, m * v^2 / r, that bends the particle's current tangential velocity into a circle
+// of its current radius.
+//
+// The generator only maintains a circular path; give the particle a tangential velocity to start
+// it orbiting. Any radial velocity is left alone.
+//
+// Integrate moves each particle along its tangent before bending the velocity, so the orbit slowly
+// drifts outward, by roughly (v*dt/r)^2 / 2 of the radius per step. Shorter steps keep it tighter.
+type CentripetalForceGenerator struct {
+	Center math64.Vector3
+}
+
+func NewCentripetalForceGenerator(center math64.Vector3) *CentripetalForceGenerator {
+	return &CentripetalForceGenerator{
+		Center: center,
+	}
+}
+
+// UpdateForce pulls the particle towards the center with the centripetal force for its current
+// radius and tangential speed.
+func (c *CentripetalForceGenerator) UpdateForce(particle *Particle, duration float64) {
+	if !particle.HasFiniteMass() {
+		return
+	}
+
+	inward := c.Center.SubCopy(particle.Position)
+	radius := inward.Magnitude()
+
+	// A particle at the center has no circle to follow.
+	if radius <= 0.0001 {
+		return
+	}
+	inward.Scale(1.0 / radius)
+
+	// Remove the radial component of the velocity, leaving the tangential velocity.
+	tangential := particle.Velocity.ScaleAddCopy(inward, -particle.Velocity.Dot(inward))
+	speedSquared := tangential.Dot(tangential)
+
+	inward.Scale(particle.Mass() * speedSquared / radius)
+	particle.AddForce(inward)
+}
+
+// ChargeForceGenerator applies a Coulomb-like electrostatic force between the particle it is
+// registered with, carrying Charge, and the Other particle, carrying OtherCharge. Like charges
+// repel and opposite charges attract.
+//
+// As with SpringForceGenerator, the force only acts on the registered particle. For a two-way
+// interaction, register a second generator on Other with the charges swapped; the two forces are
+// then equal and opposite:
+//
+//	registry.AddForce(a, NewChargeForceGenerator(b, k, qa, qb, softening))
+//	registry.AddForce(b, NewChargeForceGenerator(a, k, qb, qa, softening))
+type ChargeForceGenerator struct {
+	Other       *Particle // The other charged particle.
+	K           float64   // Holds the Coulomb constant, k, scaling the force's strength.
+	Charge      float64   // Charge of the registered particle, q1.
+	OtherCharge float64   // Charge of the other particle, q2.
+	// Softening, eps, keeps the force finite as the particles meet, by using r^2 + eps^2 in
+	// place of r^2.
+	Softening float64
+}
+
+func NewChargeForceGenerator(other *Particle, k, q1, q2, softening float64) *ChargeForceGenerator {
+	return &ChargeForceGenerator{
+		Other:       other,
+		K:           k,
+		Charge:      q1,
+		OtherCharge: q2,
+		Softening:   softening,
+	}
+}
+
+// UpdateForce applies the electrostatic force using Coulomb's law.
+//
+// f = k*q1*q2 / (|d|^2 + eps^2) * norm(d), where d is the vector from the other particle to this one.
+func (c *ChargeForceGenerator) UpdateForce(particle *Particle, duration float64) {
+	d := particle.Position.SubCopy(c.Other.Position)
+	distanceSquared := d.Dot(d) + c.Softening*c.Softening
+
+	// Coincident particles have no direction to push along.
+	direction, err := d.NormalizeChecked()
+	if err != nil || distanceSquared <= 0 {
+		return
+	}
+
+	// A positive magnitude (like charges) pushes the particle away from the other.
+	magnitude := c.K * c.Charge * c.OtherCharge / distanceSquared
+	particle.AddForce(direction.ScaleCopy(magnitude))
+}
+
+// ScaledForceGenerator wraps another force generator, applying its force multiplied by Scale. It
+// can weaken or strengthen an existing force, for difficulty tuning or slow-motion effects, without
+// changing the wrapped generator.
+type ScaledForceGenerator struct {
+	Generator ForceGenerator
+	Scale     float64
+}
+
+func NewScaledForceGenerator(generator ForceGenerator, scale float64) *ScaledForceGenerator {
+	return &ScaledForceGenerator{
+		Generator: generator,
+		Scale:     scale,
+	}
+}
+
+// Stateful reports whether the wrapped generator is stateful.
+func (s *ScaledForceGenerator) Stateful() bool {
+	return isStateful(s.Generator)
+}
+
+// UpdateForce lets the wrapped generator apply its force to the particle, then rescales the
+// part of the accumulated force it added.
+//
+// The wrapped generator is run on the particle itself, not a copy, so generators that track the
+// particles they've affected, like ExplosionForceGenerator, still work when wrapped.
+func (s *ScaledForceGenerator) UpdateForce(particle *Particle, duration float64) {
+	// A disabled wrapped generator stays disabled.
+	if !isEnabled(s.Generator) {
+		return
+	}
+
+	before := particle.forceAccumulator
+	s.Generator.UpdateForce(particle, duration)
+
+	added := particle.forceAccumulator.SubCopy(before)
+	particle.forceAccumulator = before.ScaleAddCopy(added, s.Scale)
+}
+
+// OrbitForceGenerator applies inverse-square gravity towards a fixed, massive body at Center,
+// such as a sun, for orbital mechanics. The body's pull is set by its standard gravitational
+// parameter, Mu = G*M, so the particle's acceleration is Mu/r^2 whatever its own mass.
+//
+// A particle at distance r orbits in a circle with speed sqrt(Mu/r), perpendicular to the
+// direction of the center. Integrate spirals slowly out of such an orbit, by a few percent of its
+// radius each revolution at 1000 steps per revolution; IntegrateVelocityVerlet keeps it closed.
+type OrbitForceGenerator struct {
+	Center math64.Vector3 // Position of the attracting body.
+	Mu     float64        // Standard gravitational parameter of the attracting body, mu = G*M.
+}
+
+func NewOrbitForceGenerator(center math64.Vector3, mu float64) *OrbitForceGenerator {
+	return &OrbitForceGenerator{
+		Center: center,
+		Mu:     mu,
+	}
+}
+
+// UpdateForce pulls the particle towards the center with the mass-scaled force m*mu/r^2.
+func (o *OrbitForceGenerator) UpdateForce(particle *Particle, duration float64) {
+	if !particle.HasFiniteMass() {
+		return
+	}
+
+	direction := o.Center.SubCopy(particle.Position)
+	distanceSquared := direction.Dot(direction)
+
+	// Avoid dividing by zero at the center of the body.
+	if distanceSquared <= 0.0001*0.0001 {
+		return
+	}
+
+	direction.Scale(1.0 / math.Sqrt(distanceSquared))
+	direction.Scale(particle.Mass() * o.Mu / distanceSquared)
+	particle.AddForce(direction)
+}
+
+// BeamForceGenerator is a tractor or repulsor beam: a cylinder of radius Radius running from
+// Origin along Direction, which pushes every particle inside it along the beam with a force of
+// Magnitude. A negative Magnitude pulls particles back towards the origin instead.
+//
+// The beam extends without end in front of the origin. Particles behind the origin, or further
+// than Radius from the beam's axis, feel no force; those exactly on the surface are inside. A zero
+// Direction turns the beam off.
+type BeamForceGenerator struct {
+	Origin    math64.Vector3 // Point the beam starts from.
+	Direction math64.Vector3 // Direction the beam points in. It needn't be a unit vector.
+	Radius    float64        // Radius of the beam's cylinder.
+	Magnitude float64        // Size of the force, along Direction, on particles in the beam.
+}
+
+func NewBeamForceGenerator(origin, direction math64.Vector3, radius, magnitude float64) *BeamForceGenerator {
+	return &BeamForceGenerator{
+		Origin:    origin,
+		Direction: direction,
+		Radius:    radius,
+		Magnitude: magnitude,
+	}
+}
+
+// UpdateForce pushes the particle along the beam if it is inside the beam's cylinder.
+func (b *BeamForceGenerator) UpdateForce(particle *Particle, duration float64) {
+	axis := b.Direction.Normalize()
+	if axis.IsZero() {
+		return
+	}
+
+	// Split the particle's offset from the origin into its distance along the axis and the
+	// part perpendicular to it, which is its distance from the axis.
+	offset := particle.Position.SubCopy(b.Origin)
+	along := offset.Dot(axis)
+	if along < 0 {
+		return
+	}
+
+	offset.ScaleAdd(axis, -along)
+	if offset.Dot(offset) > b.Radius*b.Radius {
+		return
+	}
+
+	particle.AddForce(axis.ScaleCopy(b.Magnitude))
+}
+
+// ScatterForceGenerator knocks particles, such as debris, off course by a random amount. Each call
+// to Trigger makes the generator give every particle it is registered with a single impulse of size
+// Magnitude, the next time it updates that particle. The impulse points in a random direction at
+// most ConeAngle radians from the particle's velocity, spread evenly over that cone, so a small
+// angle gives a slight wobble and Pi scatters in any direction. A stationary particle has no heading
+// to scatter around, and is pushed in a uniformly random direction.
+//
+// Directions are drawn from the generator's random source, so the same seed and the same order of
+// updates reproduce the same scattering. ParallelUpdateForces doesn't keep that order.
+type ScatterForceGenerator struct {
+	ConeAngle float64 // Largest angle, in radians, between the impulse and the particle's velocity.
+	Magnitude float64 // Size of each impulse, in kg*m/s.
+	// rng is the source of random directions. trigger counts calls to Trigger, and scattered holds
+	// the particles scattered since the last one, so each is scattered once per trigger. Trigger
+	// empties scattered, so it doesn't keep particles that have since been removed. They are
+	// guarded by mu, as one generator is registered to many particles.
+	rng       *rand.Rand
+	trigger   uint64
+	scattered map[*Particle]uint64
+	mu        sync.Mutex
+}
+
+func NewScatterForceGenerator(rng *rand.Rand, coneAngle, magnitude float64) *ScatterForceGenerator {
+	return &ScatterForceGenerator{
+		ConeAngle: coneAngle,
+		Magnitude: magnitude,
+		rng:       rng,
+		scattered: make(map[*Particle]uint64),
+	}
+}
+
+// Trigger makes the generator scatter each of its particles once more, when it next updates them.
+// Triggering again before a particle has been updated still scatters it only once. A particle first
+// updated after a trigger, such as one registered since, is scattered for it too.
+func (s *ScatterForceGenerator) Trigger() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trigger++
+	clear(s.scattered)
+}
+
+// Stateful reports that the generator remembers which particles it has scattered, and draws from
+// its random source.
+func (s *ScatterForceGenerator) Stateful() bool {
+	return true
+}
+
+// UpdateForce applies a random impulse within the cone about the particle's velocity, as a force
+// over the frame's duration, F = J / t, if the generator has been triggered since it last scattered
+// this particle.
+func (s *ScatterForceGenerator) UpdateForce(particle *Particle, duration float64) {
+	if duration <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.scattered[particle] == s.trigger {
+		return
+	}
+	s.scattered[particle] = s.trigger
+
+	heading, err := particle.Velocity.NormalizeChecked()
+	if err != nil {
+		particle.AddForce(math64.RandomDirection(s.rng).ScaleCopy(s.Magnitude / duration))
+		return
+	}
+
+	// Drawing the cosine of the tilt uniformly, rather than the tilt itself, spreads directions
+	// evenly over the cone's cap instead of bunching them around the heading.
+	cosTilt := 1 - s.rng.Float64()*(1-math.Cos(math.Min(s.ConeAngle, math.Pi)))
+	tilt := math.Acos(cosTilt)
+	spin := 2 * math.Pi * s.rng.Float64()
+
+	direction := heading.RotateAround(heading.AnyPerpendicular(), tilt).RotateAround(heading, spin)
+	particle.AddForce(direction.ScaleCopy(s.Magnitude / duration))
+}
@@ -0,0 +1,1075 @@
+package physics
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+func TestDragGenerator(t *testing.T) {
+	tests := []struct {
+		name     string
+		velocity math64.Vector3
+		want     math64.Vector3
+	}{
+		{"at rest", math64.Vector3{}, math64.Vector3{}},
+		{"unit speed", math64.NewVector3(1, 0, 0), math64.NewVector3(-3, 0, 0)},
+		{"speed 2", math64.NewVector3(0, -2, 0), math64.NewVector3(0, 10, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(math64.Vector3{}, tt.velocity, 1)
+			NewDragGenerator(1, 2).UpdateForce(p, 0.1)
+			if got := p.forceAccumulator; !got.ApproxEqual(tt.want, 1e-9) {
+				t.Errorf("drag on velocity %v = %v, want %v", tt.velocity, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForceRegistryAddRemove(t *testing.T) {
+	a := newTestParticle(math64.Vector3{}, math64.Vector3{}, 1)
+	b := newTestParticle(math64.Vector3{}, math64.Vector3{}, 1)
+	gravity := NewGravityGenerator(math64.NewVector3(0, -10, 0))
+	drag := NewDragGenerator(1, 1)
+
+	tests := []struct {
+		name   string
+		modify func(r *ForceRegistry)
+		wantA  []ForceGenerator
+		wantB  []ForceGenerator
+	}{
+		{"registered", func(r *ForceRegistry) {}, []ForceGenerator{gravity, drag}, []ForceGenerator{gravity}},
+		{"remove pair", func(r *ForceRegistry) { r.RemoveForce(a, gravity) }, []ForceGenerator{drag}, []ForceGenerator{gravity}},
+		{"remove unregistered pair", func(r *ForceRegistry) { r.RemoveForce(b, drag) }, []ForceGenerator{gravity, drag}, []ForceGenerator{gravity}},
+		{"remove all for particle", func(r *ForceRegistry) { r.RemoveAllForParticle(a) }, []ForceGenerator{}, []ForceGenerator{gravity}},
+		{"clear", func(r *ForceRegistry) { r.Clear() }, []ForceGenerator{}, []ForceGenerator{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r ForceRegistry
+			r.AddForce(a, gravity)
+			r.AddForce(a, drag)
+			r.AddForce(b, gravity)
+			tt.modify(&r)
+
+			if got := r.GeneratorsFor(a); !sameGenerators(got, tt.wantA) {
+				t.Errorf("GeneratorsFor(a) = %v, want %v", got, tt.wantA)
+			}
+			if got := r.GeneratorsFor(b); !sameGenerators(got, tt.wantB) {
+				t.Errorf("GeneratorsFor(b) = %v, want %v", got, tt.wantB)
+			}
+		})
+	}
+}
+
+func TestForceRegistryRemoveForceRemovesOneDuplicate(t *testing.T) {
+	p := newTestParticle(math64.Vector3{}, math64.Vector3{}, 1)
+	gravity := NewGravityGenerator(math64.NewVector3(0, -10, 0))
+
+	var r ForceRegistry
+	r.AddForce(p, gravity)
+	r.AddForce(p, gravity)
+	r.RemoveForce(p, gravity)
+	if got := r.GeneratorsFor(p); len(got) != 1 {
+		t.Errorf("after removing one of two duplicate registrations, %d remain, want 1", len(got))
+	}
+}
+
+func TestForceRegistryGeneratorsFor(t *testing.T) {
+	p := newTestParticle(math64.Vector3{}, math64.Vector3{}, 1)
+	other := newTestParticle(math64.Vector3{}, math64.Vector3{}, 1)
+	unregistered := newTestParticle(math64.Vector3{}, math64.Vector3{}, 1)
+	gravity := NewGravityGenerator(math64.NewVector3(0, -10, 0))
+	drag := NewDragGenerator(1, 1)
+
+	var r ForceRegistry
+	r.AddForce(p, gravity)
+	r.AddForce(other, NewDragGenerator(2, 2))
+	r.AddForce(p, drag)
+
+	tests := []struct {
+		name     string
+		particle *Particle
+		want     []ForceGenerator
+	}{
+		{"in registration order", p, []ForceGenerator{gravity, drag}},
+		{"unregistered", unregistered, []ForceGenerator{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.GeneratorsFor(tt.particle)
+			if got == nil || !sameGenerators(got, tt.want) {
+				t.Errorf("GeneratorsFor = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// sameGenerators reports whether a and b hold the same generators in the same order.
+func sameGenerators(a, b []ForceGenerator) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParallelUpdateForcesMatchesSerial(t *testing.T) {
+	// newScene returns particles whose generators cover shared generators, several generators per
+	// particle and a spring that reads another particle.
+	newScene := func() ([]Particle, *ForceRegistry) {
+		particles := make([]Particle, 50)
+		r := &ForceRegistry{}
+		gravity := NewGravityGenerator(math64.NewVector3(0, -9.81, 0))
+		drag := NewDragGenerator(0.1, 0.01)
+		for i := range particles {
+			particles[i] = NewParticleMass(math64.NewVector3(float64(i), 1, -float64(i)), math64.NewVector3(1, float64(i%7), 2), math64.Vector3{}, 1, 1+float64(i%3))
+		}
+		for i := range particles {
+			r.AddForce(&particles[i], gravity)
+			r.AddForce(&particles[i], drag)
+			if i > 0 {
+				r.AddForce(&particles[i], NewSpringForceGenerator(&particles[i-1], 5, 0.5))
+			}
+		}
+		return particles, r
+	}
+
+	want, serial := newScene()
+	serial.UpdateForces(0.016)
+
+	for _, workers := range []int{0, 1, 2, 4, 7, 100} {
+		t.Run(strconv.Itoa(workers), func(t *testing.T) {
+			got, parallel := newScene()
+			parallel.ParallelUpdateForces(0.016, workers)
+			for i := range got {
+				if got[i].forceAccumulator != want[i].forceAccumulator {
+					t.Errorf("particle %d: parallel force %v, serial force %v", i, got[i].forceAccumulator, want[i].forceAccumulator)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakableSpringForceGenerator(t *testing.T) {
+	tests := []struct {
+		name       string
+		lengths    []float64 // Spring length at each update.
+		wantForces []float64 // X force on the particle at each update.
+		wantBroken bool
+	}{
+		{"light load", []float64{3, 1, 2.5}, []float64{-2, 2, -1}, false},
+		{"at the break force", []float64{6}, []float64{-8}, false},
+		{"overloaded", []float64{7}, []float64{0}, true},
+		{"stays broken when unloaded", []float64{3, 7, 3, 2}, []float64{-2, 0, 0, 0}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anchor := newTestParticle(math64.Vector3{}, math64.Vector3{}, 0)
+			spring := NewBreakableSpringForceGenerator(anchor, 2, 2, 8)
+			for i, length := range tt.lengths {
+				p := newTestParticle(math64.NewVector3(length, 0, 0), math64.Vector3{}, 1)
+				spring.UpdateForce(p, 0.016)
+				if got := p.forceAccumulator; !got.ApproxEqual(math64.NewVector3(tt.wantForces[i], 0, 0), 1e-9) {
+					t.Errorf("update %d at length %v: force %v, want (%v, 0, 0)", i, length, got, tt.wantForces[i])
+				}
+			}
+			if spring.Broken() != tt.wantBroken {
+				t.Errorf("Broken() = %v, want %v", spring.Broken(), tt.wantBroken)
+			}
+		})
+	}
+}
+
+func TestBreakableSpringForceGeneratorSurvivesPrediction(t *testing.T) {
+	shot := newPistolShot()
+	anchor := newTestParticle(math64.NewVector3(0, 1.5, 3), math64.Vector3{}, 0)
+	spring := NewBreakableSpringForceGenerator(anchor, 10, 1, 1)
+	var reg ForceRegistry
+	reg.AddForce(shot, spring)
+
+	if _, err := PredictTrajectory(shot, &reg, 100, 1.0/60); err != nil {
+		t.Fatal(err)
+	}
+	if spring.Broken() {
+		t.Fatal("prediction broke the spring")
+	}
+
+	// The real step still loads the spring past its break force.
+	reg.UpdateForces(0.1)
+	if !spring.Broken() {
+		t.Error("spring did not break under the real step")
+	}
+}
+
+func TestLimitedSpringForceGenerator(t *testing.T) {
+	anchor := newTestParticle(math64.Vector3{}, math64.Vector3{}, 0)
+	// k = 2 within 1 of extension past the rest length of 2, and 200 beyond it.
+	spring := NewLimitedSpringForceGenerator(anchor, 2, 2, 1)
+	tests := []struct {
+		name     string
+		position math64.Vector3
+		want     math64.Vector3
+	}{
+		{"slack", math64.NewVector3(1.5, 0, 0), math64.Vector3{}},
+		{"at rest length", math64.NewVector3(0, 2, 0), math64.Vector3{}},
+		{"within the deadzone", math64.NewVector3(2.5, 0, 0), math64.NewVector3(-1, 0, 0)},
+		{"at the limit", math64.NewVector3(0, 0, -3), math64.NewVector3(0, 0, 2)},
+		{"past the limit", math64.NewVector3(3.5, 0, 0), math64.NewVector3(-102, 0, 0)},
+		{"far past the limit", math64.NewVector3(0, 4, 0), math64.NewVector3(0, -202, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(tt.position, math64.Vector3{}, 1)
+			spring.UpdateForce(p, 0.016)
+			if got := p.forceAccumulator; !got.ApproxEqual(tt.want, 1e-9) {
+				t.Errorf("force at %v = %v, want %v", tt.position, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAltitudeGravityGenerator(t *testing.T) {
+	center := math64.NewVector3(0, -100, 0)
+	gravity := NewAltitudeGravityGenerator(center, 9.8, 100)
+	tests := []struct {
+		name     string
+		position math64.Vector3
+		mass     float64
+		want     math64.Vector3
+	}{
+		{"surface", math64.Vector3{}, 1, math64.NewVector3(0, -9.8, 0)},
+		{"surface, heavier", math64.NewVector3(100, -100, 0), 3, math64.NewVector3(-29.4, 0, 0)},
+		{"double the distance", math64.NewVector3(0, 100, 0), 1, math64.NewVector3(0, -2.45, 0)},
+		{"triple the distance", math64.NewVector3(0, -100, -300), 1, math64.NewVector3(0, 0, 9.8/9)},
+		{"at the center", center, 1, math64.Vector3{}},
+		{"infinite mass", math64.Vector3{}, 0, math64.Vector3{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(tt.position, math64.Vector3{}, tt.mass)
+			gravity.UpdateForce(p, 0.016)
+			if got := p.forceAccumulator; !got.ApproxEqual(tt.want, 1e-9) {
+				t.Errorf("gravity at %v = %v, want %v", tt.position, got, tt.want)
+			}
+		})
+	}
+
+	if got := gravity.Acceleration(200) / gravity.Acceleration(100); !math64.ApproxEqual(got, 0.25, 1e-12) {
+		t.Errorf("doubling the distance scales acceleration by %v, want 0.25", got)
+	}
+}
+
+// toggleableGravity is a gravity generator that can be switched on and off.
+type toggleableGravity struct {
+	EnabledFlag
+	GravityGenerator
+}
+
+func TestForceRegistrySkipsDisabledGenerators(t *testing.T) {
+	gravity := &toggleableGravity{GravityGenerator: GravityGenerator{Gravity: math64.NewVector3(0, -1, 0)}}
+	p := newTestParticle(math64.NewVector3(1, 0, 0), math64.Vector3{}, 2)
+	var r ForceRegistry
+	r.AddForce(p, gravity)
+
+	tests := []struct {
+		name    string
+		enabled bool
+		want    math64.Vector3
+	}{
+		{"enabled by default", true, math64.NewVector3(0, -2, 0)},
+		{"disabled", false, math64.Vector3{}},
+		{"re-enabled", true, math64.NewVector3(0, -2, 0)},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if i > 0 {
+				gravity.SetEnabled(tt.enabled)
+			}
+			if gravity.Enabled() != tt.enabled {
+				t.Fatalf("Enabled() = %v, want %v", gravity.Enabled(), tt.enabled)
+			}
+
+			p.ClearForces()
+			r.UpdateForces(0.016)
+			if got := p.forceAccumulator; got != tt.want {
+				t.Errorf("UpdateForces applied %v, want %v", got, tt.want)
+			}
+
+			p.ClearForces()
+			r.ParallelUpdateForces(0.016, 2)
+			if got := p.forceAccumulator; got != tt.want {
+				t.Errorf("ParallelUpdateForces applied %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if n := len(r.GeneratorsFor(p)); n != 1 {
+		t.Errorf("toggling the generator left %d registrations, want 1", n)
+	}
+}
+
+// copyDragForce computes the drag force the way DragGenerator did before it was rewritten to use
+// in-place vector methods, as a reference for its output and allocations.
+func copyDragForce(d *DragGenerator, particle *Particle) math64.Vector3 {
+	velocity := particle.Velocity.SubCopy(d.MediumVelocity)
+	speed := velocity.Magnitude()
+	direction, err := velocity.NormalizeChecked()
+	if err != nil {
+		return math64.Vector3{}
+	}
+	return direction.ScaleCopy(-(d.K1*speed + d.K2*speed*speed))
+}
+
+// copyGravityForce computes the gravity force the way GravityGenerator did before it was rewritten
+// to use in-place vector methods.
+func copyGravityForce(g *GravityGenerator, particle *Particle) math64.Vector3 {
+	r := particle.Position.Magnitude()
+	if r <= 0.0001 {
+		return math64.Vector3{}
+	}
+	return g.Gravity.ScaleCopy(particle.Mass() * r * r)
+}
+
+func TestInPlaceGeneratorsMatchCopyStyle(t *testing.T) {
+	drag := NewDragGenerator(0.3, 0.02)
+	drag.MediumVelocity = math64.NewVector3(1, 0, -1)
+	gravity := NewGravityGenerator(math64.NewVector3(0, -9.81, 0))
+	tests := []struct {
+		name               string
+		position, velocity math64.Vector3
+	}{
+		{"moving", math64.NewVector3(1, 2, 3), math64.NewVector3(4, -5, 6)},
+		{"at rest in the medium", math64.NewVector3(-2, 0, 0.5), drag.MediumVelocity},
+		{"at the origin", math64.Vector3{}, math64.NewVector3(0, 0, 35)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(tt.position, tt.velocity, 2)
+			drag.UpdateForce(p, 0.016)
+			if got, want := p.forceAccumulator, copyDragForce(drag, p); got != want {
+				t.Errorf("drag force = %v, want %v", got, want)
+			}
+
+			p.ClearForces()
+			gravity.UpdateForce(p, 0.016)
+			if got, want := p.forceAccumulator, copyGravityForce(gravity, p); got != want {
+				t.Errorf("gravity force = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestUpdateForcesDoesNotAllocate(t *testing.T) {
+	var registry ForceRegistry
+	p := newBenchParticle()
+	registry.AddForce(&p, NewDragGenerator(0.1, 0.01))
+	registry.AddForce(&p, NewGravityGenerator(math64.NewVector3(0, -9.81, 0)))
+
+	if allocs := testing.AllocsPerRun(100, func() { registry.UpdateForces(0.016) }); allocs != 0 {
+		t.Errorf("UpdateForces made %v allocations per call, want 0", allocs)
+	}
+}
+
+func TestRadialGravityGenerator(t *testing.T) {
+	center := math64.NewVector3(1, -2, 3)
+	tests := []struct {
+		name      string
+		offset    math64.Vector3 // Position relative to the center.
+		mass      float64
+		wantForce math64.Vector3
+	}{
+		{"above", math64.NewVector3(0, 10, 0), 2, math64.NewVector3(0, -20, 0)},
+		{"below", math64.NewVector3(0, -10, 0), 2, math64.NewVector3(0, 20, 0)},
+		{"beside", math64.NewVector3(-3, 0, 0), 1, math64.NewVector3(10, 0, 0)},
+		{"same pull far away", math64.NewVector3(0, 0, 1000), 1, math64.NewVector3(0, 0, -10)},
+		{"diagonal", math64.NewVector3(3, 4, 0), 1, math64.NewVector3(-6, -8, 0)},
+		{"at the center", math64.Vector3{}, 1, math64.Vector3{}},
+		{"within epsilon of the center", math64.NewVector3(math64.Epsilon/2, 0, 0), 1, math64.Vector3{}},
+		{"infinite mass", math64.NewVector3(0, 10, 0), 0, math64.Vector3{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(center.AddCopy(tt.offset), math64.Vector3{}, tt.mass)
+			NewRadialGravityGenerator(center, 10).UpdateForce(p, 0.01)
+
+			if !p.forceAccumulator.ApproxEqual(tt.wantForce, 1e-9) {
+				t.Errorf("force = %v, want %v", p.forceAccumulator, tt.wantForce)
+			}
+		})
+	}
+}
+
+func TestRadialGravityGeneratorOppositeSidesFallInward(t *testing.T) {
+	center := math64.NewVector3(0, 0, 0)
+	g := NewRadialGravityGenerator(center, 9.81)
+	for _, start := range []math64.Vector3{
+		math64.NewVector3(0, 10, 0), math64.NewVector3(0, -10, 0),
+		math64.NewVector3(10, 0, 0), math64.NewVector3(-10, 0, 0),
+		math64.NewVector3(0, 0, 10), math64.NewVector3(0, 0, -10),
+	} {
+		p := newTestParticle(start, math64.Vector3{}, 1)
+		for i := 0; i < 30; i++ {
+			g.UpdateForce(p, 1.0/60)
+			if err := p.Integrate(1.0 / 60); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Half a second of falling at 9.81 m/s^2 covers just over a metre, straight down.
+		fallen := start.Magnitude() - p.Position.Distance(center)
+		if fallen < 1 || fallen > 1.3 {
+			t.Errorf("particle starting at %v fell %v towards the center, want about 1.2", start, fallen)
+		}
+		if dir := p.Position.Normalize(); !dir.ApproxEqual(start.Normalize(), 1e-12) {
+			t.Errorf("particle starting at %v drifted sideways to %v", start, p.Position)
+		}
+	}
+}
+
+func TestDragGeneratorMovingMedium(t *testing.T) {
+	tests := []struct {
+		name     string
+		velocity math64.Vector3
+		medium   math64.Vector3
+		want     math64.Vector3
+	}{
+		{"still medium", math64.NewVector3(1, 0, 0), math64.Vector3{}, math64.NewVector3(-3, 0, 0)},
+		{"moving with the medium", math64.NewVector3(3, -1, 2), math64.NewVector3(3, -1, 2), math64.Vector3{}},
+		{"headwind", math64.NewVector3(1, 0, 0), math64.NewVector3(-1, 0, 0), math64.NewVector3(-10, 0, 0)},
+		{"tailwind", math64.NewVector3(1, 0, 0), math64.NewVector3(0.5, 0, 0), math64.NewVector3(-1, 0, 0)},
+		{"pushed by wind at rest", math64.Vector3{}, math64.NewVector3(0, 0, 1), math64.NewVector3(0, 0, 3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drag := NewDragGenerator(1, 2)
+			drag.MediumVelocity = tt.medium
+			p := newTestParticle(math64.Vector3{}, tt.velocity, 1)
+			drag.UpdateForce(p, 0.016)
+			if got := p.forceAccumulator; !got.ApproxEqual(tt.want, 1e-9) {
+				t.Errorf("drag on %v in a medium moving at %v = %v, want %v", tt.velocity, tt.medium, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerminalVelocity(t *testing.T) {
+	gravity := math64.NewVector3(0, -9.81, 0)
+	tests := []struct {
+		name   string
+		mass   float64
+		k1, k2 float64
+	}{
+		{"linear drag", 2, 0.5, 0},
+		{"quadratic drag", 2, 0, 0.3},
+		{"both", 5, 0.2, 0.1},
+		{"air preset", 80, NewAirDragGenerator(0.7, 1).K1, NewAirDragGenerator(0.7, 1).K2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drag := NewDragGenerator(tt.k1, tt.k2)
+			want := TerminalVelocity(gravity, tt.mass, drag)
+
+			// Fall for long enough to settle, with gravity as a constant acceleration, since
+			// GravityGenerator scales with distance from the origin.
+			p := NewParticleMass(math64.Vector3{}, math64.Vector3{}, gravity, 1, tt.mass)
+			var r ForceRegistry
+			r.AddForce(&p, drag)
+			for i := 0; i < 20000; i++ {
+				r.UpdateForces(0.01)
+				if err := p.Integrate(0.01); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := p.Velocity.Magnitude(); !math64.ApproxEqual(got, want, want*1e-3) {
+				t.Errorf("settled at %v m/s, TerminalVelocity = %v", got, want)
+			}
+		})
+	}
+
+	if got := TerminalVelocity(gravity, 1, NewDragGenerator(0, 0)); !math.IsInf(got, 1) {
+		t.Errorf("TerminalVelocity without drag = %v, want +Inf", got)
+	}
+}
+
+func TestDragPresets(t *testing.T) {
+	tests := []struct {
+		name         string
+		drag         *DragGenerator
+		speed        float64
+		minF, maxF   float64 // Physically reasonable drag force at speed, in newtons.
+		mass         float64
+		minVt, maxVt float64 // Physically reasonable terminal velocity under gravity, in m/s.
+	}{
+		// A football kicked at 30 m/s feels about 10 N of drag, and falls at about 20 m/s at most.
+		{"football in air", NewAirDragGenerator(0.038, 0.47), 30, 8, 12, 0.43, 15, 30},
+		// A skydiver in a belly-down position falls at roughly 50 m/s.
+		{"skydiver in air", NewAirDragGenerator(0.7, 1.0), 50, 900, 1200, 80, 35, 65},
+		// The same football dragged through water at 1 m/s feels about 9 N, and sinks slowly if
+		// weighted to be denser than water.
+		{"football in water", NewWaterDragGenerator(0.038, 0.47), 1, 7, 11, 5, 0.5, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.drag.K1 <= 0 || tt.drag.K2 <= 0 {
+				t.Fatalf("coefficients K1 = %v, K2 = %v, want both positive", tt.drag.K1, tt.drag.K2)
+			}
+
+			p := newTestParticle(math64.Vector3{}, math64.NewVector3(tt.speed, 0, 0), tt.mass)
+			tt.drag.UpdateForce(p, 0.01)
+			force := p.forceAccumulator
+			if force.X >= 0 || force.Y != 0 || force.Z != 0 {
+				t.Errorf("drag force %v doesn't oppose the velocity %v", force, p.Velocity)
+			}
+			if f := force.Magnitude(); f < tt.minF || f > tt.maxF {
+				t.Errorf("drag at %v m/s = %v N, want between %v and %v", tt.speed, f, tt.minF, tt.maxF)
+			}
+
+			if vt := TerminalVelocity(math64.NewVector3(0, -9.81, 0), tt.mass, tt.drag); vt < tt.minVt || vt > tt.maxVt {
+				t.Errorf("terminal velocity = %v m/s, want between %v and %v", vt, tt.minVt, tt.maxVt)
+			}
+		})
+	}
+}
+
+func TestDragPresetsWaterDragsMore(t *testing.T) {
+	air, water := NewAirDragGenerator(0.01, 0.47), NewWaterDragGenerator(0.01, 0.47)
+	for _, speed := range []float64{0.01, 1, 10} {
+		a := newTestParticle(math64.Vector3{}, math64.NewVector3(0, 0, speed), 1)
+		w := newTestParticle(math64.Vector3{}, math64.NewVector3(0, 0, speed), 1)
+		air.UpdateForce(a, 0.01)
+		water.UpdateForce(w, 0.01)
+
+		if fa, fw := a.forceAccumulator.Magnitude(), w.forceAccumulator.Magnitude(); fw < 50*fa {
+			t.Errorf("at %v m/s water drag %v N is not far above air drag %v N", speed, fw, fa)
+		}
+	}
+}
+
+func TestFuncForceGenerator(t *testing.T) {
+	tests := []struct {
+		name         string
+		fn           func(particle *Particle, duration float64) math64.Vector3
+		wantVelocity math64.Vector3
+	}{
+		{"constant upward force", func(*Particle, float64) math64.Vector3 { return math64.NewVector3(0, 4, 0) }, math64.NewVector3(0, 2, 0)},
+		{"zero force", func(*Particle, float64) math64.Vector3 { return math64.Vector3{} }, math64.Vector3{}},
+		{"reads the particle", func(p *Particle, _ float64) math64.Vector3 { return p.Position.ScaleCopy(-1) }, math64.NewVector3(-0.5, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A 2 kg particle integrated for 1 second gains force/2 of velocity.
+			p := newTestParticle(math64.NewVector3(1, 0, 0), math64.Vector3{}, 2)
+			var r ForceRegistry
+			r.AddForce(p, NewFuncForceGenerator(tt.fn))
+			r.UpdateForces(1)
+			if err := p.Integrate(1); err != nil {
+				t.Fatal(err)
+			}
+			if !p.Velocity.ApproxEqual(tt.wantVelocity, 1e-9) {
+				t.Errorf("velocity = %v, want %v", p.Velocity, tt.wantVelocity)
+			}
+		})
+	}
+}
+
+func TestRegionForceGenerator(t *testing.T) {
+	region := math64.NewAABB(math64.NewVector3(-1, -2, -3), math64.NewVector3(1, 2, 3))
+	buoyancy := math64.NewVector3(0, 15, 0)
+	tests := []struct {
+		name     string
+		position math64.Vector3
+		want     math64.Vector3
+	}{
+		{"inside", math64.NewVector3(0.5, -1, 2), buoyancy},
+		{"outside", math64.NewVector3(0, 5, 0), math64.Vector3{}},
+		{"outside on one axis", math64.NewVector3(1.01, 0, 0), math64.Vector3{}},
+		{"on a face", math64.NewVector3(0, 0, -3), buoyancy},
+		{"on a corner", math64.NewVector3(1, 2, 3), buoyancy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(tt.position, math64.Vector3{}, 1)
+			NewRegionForceGenerator(region, buoyancy).UpdateForce(p, 0.016)
+			if got := p.forceAccumulator; got != tt.want {
+				t.Errorf("force at %v = %v, want %v", tt.position, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCentripetalForceGeneratorOrbit(t *testing.T) {
+	tests := []struct {
+		name     string
+		center   math64.Vector3
+		offset   math64.Vector3 // Starting position relative to the center.
+		velocity math64.Vector3
+		mass     float64
+	}{
+		{"horizontal orbit", math64.Vector3{}, math64.NewVector3(10, 0, 0), math64.NewVector3(0, 0, 5), 1},
+		{"vertical orbit off the origin", math64.NewVector3(3, -2, 7), math64.NewVector3(0, 4, 0), math64.NewVector3(-2, 0, 0), 5},
+		{"tilted orbit", math64.NewVector3(-1, 1, -1), math64.NewVector3(2, 2, 0), math64.NewVector3(0, 0, 3), 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			radius, speed := tt.offset.Magnitude(), tt.velocity.Magnitude()
+			p := newTestParticle(tt.center.AddCopy(tt.offset), tt.velocity, tt.mass)
+			g := NewCentripetalForceGenerator(tt.center)
+
+			// Step through a full orbit, 1000 steps per revolution. The orbit drifts outward by
+			// about (2*Pi/1000)^2 / 2 of the radius per step, so 2% over the orbit.
+			period := 2 * math64.Pi * radius / speed
+			dt := period / 1000
+			for i := 0; i < 1000; i++ {
+				g.UpdateForce(p, dt)
+				if err := p.Integrate(dt); err != nil {
+					t.Fatal(err)
+				}
+				if r := p.Position.Distance(tt.center); math.Abs(r-radius) > 0.03*radius {
+					t.Fatalf("step %d: radius %v, want within 3%% of %v", i, r, radius)
+				}
+			}
+
+			if got := p.Velocity.Magnitude(); math.Abs(got-speed) > 0.03*speed {
+				t.Errorf("speed after an orbit = %v, want within 3%% of %v", got, speed)
+			}
+			// After a full revolution the particle is back near where it started.
+			if d := p.Position.Distance(tt.center.AddCopy(tt.offset)); d > 0.1*radius {
+				t.Errorf("after a full orbit the particle is %v from its start, want within %v", d, 0.1*radius)
+			}
+		})
+	}
+}
+
+func TestOrbitForceGenerator(t *testing.T) {
+	center := math64.NewVector3(1, 2, 3)
+	tests := []struct {
+		name     string
+		offset   math64.Vector3 // Position relative to the center.
+		mass     float64
+		wantAccl math64.Vector3
+	}{
+		{"unit mass", math64.NewVector3(2, 0, 0), 1, math64.NewVector3(-2, 0, 0)},
+		{"acceleration independent of mass", math64.NewVector3(2, 0, 0), 5, math64.NewVector3(-2, 0, 0)},
+		{"inverse square", math64.NewVector3(0, -4, 0), 1, math64.NewVector3(0, 0.5, 0)},
+		{"at the center", math64.Vector3{}, 1, math64.Vector3{}},
+		{"infinite mass", math64.NewVector3(2, 0, 0), 0, math64.Vector3{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(center.AddCopy(tt.offset), math64.Vector3{}, tt.mass)
+			NewOrbitForceGenerator(center, 8).UpdateForce(p, 0.01)
+
+			want := tt.wantAccl.ScaleCopy(max(tt.mass, 0))
+			if !p.forceAccumulator.ApproxEqual(want, 1e-12) {
+				t.Errorf("force = %v, want %v", p.forceAccumulator, want)
+			}
+		})
+	}
+}
+
+func TestOrbitForceGeneratorCircularOrbit(t *testing.T) {
+	tests := []struct {
+		name      string
+		center    math64.Vector3
+		offset    math64.Vector3 // Starting position relative to the center.
+		direction math64.Vector3 // Direction of the starting velocity, perpendicular to offset.
+		mu        float64
+		mass      float64
+	}{
+		{"sun-like", math64.Vector3{}, math64.NewVector3(10, 0, 0), math64.NewVector3(0, 0, 1), 1000, 1},
+		{"off the origin", math64.NewVector3(3, -2, 7), math64.NewVector3(0, 4, 0), math64.NewVector3(-1, 0, 0), 50, 5},
+		{"tilted", math64.NewVector3(-1, 1, -1), math64.NewVector3(2, 2, 0), math64.NewVector3(0, 0, 1), 10, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			radius := tt.offset.Magnitude()
+			speed := math.Sqrt(tt.mu / radius)
+			p := newTestParticle(tt.center.AddCopy(tt.offset), tt.direction.ScaleCopy(speed), tt.mass)
+			var reg ForceRegistry
+			reg.AddForce(p, NewOrbitForceGenerator(tt.center, tt.mu))
+
+			// Step through a full orbit, 1000 steps per revolution. Plain Euler integration spirals
+			// out of an inverse-square orbit, so integrate with velocity-Verlet.
+			period := 2 * math64.Pi * radius / speed
+			dt := period / 1000
+			for i := 0; i < 1000; i++ {
+				if err := p.IntegrateVelocityVerlet(dt, &reg); err != nil {
+					t.Fatal(err)
+				}
+				if r := p.Position.Distance(tt.center); math.Abs(r-radius) > 0.001*radius {
+					t.Fatalf("step %d: radius %v, want within 0.1%% of %v", i, r, radius)
+				}
+			}
+
+			if got := p.Velocity.Magnitude(); math.Abs(got-speed) > 0.001*speed {
+				t.Errorf("speed after an orbit = %v, want within 0.1%% of %v", got, speed)
+			}
+			if d := p.Position.Distance(tt.center.AddCopy(tt.offset)); d > 0.01*radius {
+				t.Errorf("after a full orbit the particle is %v from its start, want within %v", d, 0.01*radius)
+			}
+		})
+	}
+}
+
+func TestChargeForceGenerator(t *testing.T) {
+	tests := []struct {
+		name      string
+		qa, qb    float64
+		softening float64
+		wantOnA   math64.Vector3 // Force on a, which sits 2 along X from b.
+	}{
+		{"like charges repel", 2, 3, 0, math64.NewVector3(1.5, 0, 0)},
+		{"negative like charges repel", -2, -3, 0, math64.NewVector3(1.5, 0, 0)},
+		{"opposite charges attract", 2, -3, 0, math64.NewVector3(-1.5, 0, 0)},
+		{"neutral", 0, 3, 0, math64.Vector3{}},
+		{"softened", 2, 3, math.Sqrt(2), math64.NewVector3(1, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestParticle(math64.NewVector3(3, 1, 1), math64.Vector3{}, 1)
+			b := newTestParticle(math64.NewVector3(1, 1, 1), math64.Vector3{}, 5)
+
+			// Registered both ways round, as the doc comment describes.
+			var r ForceRegistry
+			r.AddForce(a, NewChargeForceGenerator(b, 1, tt.qa, tt.qb, tt.softening))
+			r.AddForce(b, NewChargeForceGenerator(a, 1, tt.qb, tt.qa, tt.softening))
+			r.UpdateForces(0.016)
+
+			if !a.forceAccumulator.ApproxEqual(tt.wantOnA, 1e-12) {
+				t.Errorf("force on a = %v, want %v", a.forceAccumulator, tt.wantOnA)
+			}
+			if sum := a.forceAccumulator.AddCopy(b.forceAccumulator); !sum.ApproxEqual(math64.Vector3{}, 1e-12) {
+				t.Errorf("forces %v and %v are not equal and opposite", a.forceAccumulator, b.forceAccumulator)
+			}
+		})
+	}
+}
+
+func TestChargeForceGeneratorCoincident(t *testing.T) {
+	a := newTestParticle(math64.NewVector3(1, 2, 3), math64.Vector3{}, 1)
+	b := newTestParticle(math64.NewVector3(1, 2, 3), math64.Vector3{}, 1)
+	NewChargeForceGenerator(b, 1, 1, 1, 0).UpdateForce(a, 0.016)
+	if !a.forceAccumulator.IsZero() {
+		t.Errorf("coincident charges gave force %v, want none", a.forceAccumulator)
+	}
+}
+
+func TestScaledForceGenerator(t *testing.T) {
+	gravity := NewGravityGenerator(math64.NewVector3(0, -10, 0))
+	existing := math64.NewVector3(1, 2, 3)
+
+	tests := []struct {
+		name  string
+		scale float64
+		want  math64.Vector3
+	}{
+		{"unscaled", 1, math64.NewVector3(1, -18, 3)},
+		{"halves gravity", 0.5, math64.NewVector3(1, -8, 3)},
+		{"zero applies nothing", 0, existing},
+		{"doubles gravity", 2, math64.NewVector3(1, -38, 3)},
+		{"reverses gravity", -1, math64.NewVector3(1, 22, 3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// At distance 1 from the origin, gravity applies exactly mass*Gravity.
+			p := newTestParticle(math64.NewVector3(1, 0, 0), math64.Vector3{}, 2)
+			p.AddForce(existing)
+
+			NewScaledForceGenerator(gravity, tt.scale).UpdateForce(p, 0.1)
+
+			// Only the wrapped generator's share is scaled; forces already accumulated are kept.
+			if !p.forceAccumulator.ApproxEqual(tt.want, 1e-12) {
+				t.Errorf("accumulated force = %v, want %v", p.forceAccumulator, tt.want)
+			}
+		})
+	}
+}
+
+func TestScaledForceGeneratorForwards(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		inner := &toggleableGravity{GravityGenerator: GravityGenerator{Gravity: math64.NewVector3(0, -1, 0)}}
+		inner.SetEnabled(false)
+		p := newTestParticle(math64.NewVector3(1, 0, 0), math64.Vector3{}, 1)
+		NewScaledForceGenerator(inner, 2).UpdateForce(p, 0.1)
+		if !p.forceAccumulator.IsZero() {
+			t.Errorf("disabled wrapped generator applied %v", p.forceAccumulator)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		inner ForceGenerator
+		want  bool
+	}{
+		{"stateless", NewGravityGenerator(math64.NewVector3(0, -1, 0)), false},
+		{"stateful", NewImpulseForceGenerator(math64.NewVector3(1, 0, 0)), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewScaledForceGenerator(tt.inner, 0.5).Stateful(); got != tt.want {
+				t.Errorf("Stateful() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBeamForceGenerator(t *testing.T) {
+	origin := math64.NewVector3(1, 2, 3)
+	tests := []struct {
+		name      string
+		direction math64.Vector3
+		offset    math64.Vector3 // Particle position relative to the origin.
+		magnitude float64
+		want      math64.Vector3
+	}{
+		{"on the axis", math64.NewVector3(0, 0, 1), math64.NewVector3(0, 0, 5), 4, math64.NewVector3(0, 0, 4)},
+		{"inside off the axis", math64.NewVector3(0, 0, 2), math64.NewVector3(1, -1, 20), 4, math64.NewVector3(0, 0, 4)},
+		{"on the surface", math64.NewVector3(0, 0, 1), math64.NewVector3(2, 0, 3), 4, math64.NewVector3(0, 0, 4)},
+		{"at the origin", math64.NewVector3(0, 0, 1), math64.Vector3{}, 4, math64.NewVector3(0, 0, 4)},
+		{"diagonal", math64.NewVector3(1, 1, 0), math64.NewVector3(3, 3, 0.5), 2, math64.NewVector3(math.Sqrt2, math.Sqrt2, 0)},
+		{"tractor", math64.NewVector3(0, 0, 1), math64.NewVector3(0, 0, 5), -4, math64.NewVector3(0, 0, -4)},
+		{"outside the radius", math64.NewVector3(0, 0, 1), math64.NewVector3(2.5, 0, 5), 4, math64.Vector3{}},
+		{"behind the origin", math64.NewVector3(0, 0, 1), math64.NewVector3(0, 0, -0.5), 4, math64.Vector3{}},
+		{"zero direction", math64.Vector3{}, math64.NewVector3(0, 0, 5), 4, math64.Vector3{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(origin.AddCopy(tt.offset), math64.Vector3{}, 1)
+			NewBeamForceGenerator(origin, tt.direction, 2, tt.magnitude).UpdateForce(p, 0.01)
+			if !p.forceAccumulator.ApproxEqual(tt.want, 1e-12) {
+				t.Errorf("force = %v, want %v", p.forceAccumulator, tt.want)
+			}
+		})
+	}
+}
+
+func TestBeamForceGeneratorPushesAlongBeam(t *testing.T) {
+	beam := NewBeamForceGenerator(math64.Vector3{}, math64.NewVector3(1, 0, 0), 1, 10)
+	inside := newTestParticle(math64.NewVector3(2, 0.5, 0), math64.Vector3{}, 2)
+	outside := newTestParticle(math64.NewVector3(2, 1.5, 0), math64.Vector3{}, 2)
+	var reg ForceRegistry
+	reg.AddForce(inside, beam)
+	reg.AddForce(outside, beam)
+
+	for i := 0; i < 10; i++ {
+		reg.UpdateForces(0.1)
+		for _, p := range []*Particle{inside, outside} {
+			if err := p.Integrate(0.1); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if inside.Position.X <= 2 || inside.Position.Y != 0.5 || inside.Position.Z != 0 {
+		t.Errorf("particle in the beam moved to %v, want it pushed along +X only", inside.Position)
+	}
+	if outside.Position != math64.NewVector3(2, 1.5, 0) {
+		t.Errorf("particle outside the beam moved to %v", outside.Position)
+	}
+}
+
+func TestScatterForceGenerator(t *testing.T) {
+	tests := []struct {
+		name      string
+		velocity  math64.Vector3
+		coneAngle float64
+		magnitude float64
+	}{
+		{"narrow cone", math64.NewVector3(0, 0, 10), 0.1, 2},
+		{"wide cone", math64.NewVector3(3, -4, 0), math64.Pi / 3, 5},
+		{"any direction", math64.NewVector3(1, 1, 1), math64.Pi, 1},
+		{"no spread", math64.NewVector3(-2, 0, 0), 0, 3},
+		{"stationary", math64.Vector3{}, 0.1, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScatterForceGenerator(rand.New(rand.NewSource(1)), tt.coneAngle, tt.magnitude)
+			for i := 0; i < 200; i++ {
+				p := newTestParticle(math64.Vector3{}, tt.velocity, 1)
+				s.Trigger()
+				s.UpdateForce(p, 0.02)
+
+				impulse := p.forceAccumulator.ScaleCopy(0.02)
+				if got := impulse.Magnitude(); !math64.ApproxEqual(got, tt.magnitude, 1e-9) {
+					t.Fatalf("impulse %v has magnitude %v, want %v", impulse, got, tt.magnitude)
+				}
+				if tt.velocity.IsZero() {
+					continue
+				}
+				cos := impulse.Dot(tt.velocity) / (impulse.Magnitude() * tt.velocity.Magnitude())
+				if angle := math.Acos(math.Min(cos, 1)); angle > tt.coneAngle+1e-6 {
+					t.Fatalf("impulse %v is %v rad from the velocity, want at most %v", impulse, angle, tt.coneAngle)
+				}
+			}
+		})
+	}
+}
+
+func TestScatterForceGeneratorReproducible(t *testing.T) {
+	scatter := func(seed int64) []math64.Vector3 {
+		s := NewScatterForceGenerator(rand.New(rand.NewSource(seed)), 0.5, 1)
+		var forces []math64.Vector3
+		for i := 0; i < 10; i++ {
+			p := newTestParticle(math64.Vector3{}, math64.NewVector3(0, 1, 0), 1)
+			s.Trigger()
+			s.UpdateForce(p, 0.1)
+			forces = append(forces, p.forceAccumulator)
+		}
+		return forces
+	}
+
+	first, again, other := scatter(42), scatter(42), scatter(7)
+	for i := range first {
+		if first[i] != again[i] {
+			t.Errorf("impulse %d: %v with seed 42, then %v with the same seed", i, first[i], again[i])
+		}
+	}
+	same := true
+	for i := range first {
+		same = same && first[i] == other[i]
+	}
+	if same {
+		t.Errorf("seeds 42 and 7 gave the same impulses %v", first)
+	}
+}
+
+func TestScatterForceGeneratorTrigger(t *testing.T) {
+	tests := []struct {
+		name        string
+		triggers    int // Calls to Trigger before the updates.
+		updates     int
+		wantScatter int // Number of updates that scatter the particle.
+	}{
+		{"never triggered", 0, 3, 0},
+		{"triggered once", 1, 3, 1},
+		{"triggered twice before updating", 2, 3, 1},
+		{"not updated", 1, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScatterForceGenerator(rand.New(rand.NewSource(1)), 0.2, 1)
+			p := newTestParticle(math64.Vector3{}, math64.NewVector3(1, 0, 0), 1)
+			for i := 0; i < tt.triggers; i++ {
+				s.Trigger()
+			}
+
+			scattered := 0
+			for i := 0; i < tt.updates; i++ {
+				s.UpdateForce(p, 0.1)
+				if !p.forceAccumulator.IsZero() {
+					scattered++
+				}
+				p.ClearForces()
+			}
+			if scattered != tt.wantScatter {
+				t.Errorf("scattered %d times, want %d", scattered, tt.wantScatter)
+			}
+		})
+	}
+}
+
+func TestScatterForceGeneratorForgetsParticles(t *testing.T) {
+	s := NewScatterForceGenerator(rand.New(rand.NewSource(1)), 0.2, 1)
+	for i := 0; i < 100; i++ {
+		// A new particle every trigger, as debris is spawned and despawned.
+		s.Trigger()
+		s.UpdateForce(newTestParticle(math64.Vector3{}, math64.NewVector3(1, 0, 0), 1), 0.1)
+	}
+	if got := len(s.scattered); got > 1 {
+		t.Errorf("generator remembers %d particles, want at most those scattered since the last trigger", got)
+	}
+}
+
+func TestScatterForceGeneratorSurvivesPrediction(t *testing.T) {
+	shot := newPistolShot()
+	s := NewScatterForceGenerator(rand.New(rand.NewSource(1)), 0.2, 1)
+	var reg ForceRegistry
+	reg.AddForce(shot, s)
+
+	s.Trigger()
+	if _, err := PredictTrajectory(shot, &reg, 10, 1.0/60); err != nil {
+		t.Fatal(err)
+	}
+
+	// The prediction neither scatters the particle nor draws from the random source, so the real
+	// update still scatters it, the same way a fresh generator with the same seed does.
+	reg.UpdateForces(0.1)
+	fresh := NewScatterForceGenerator(rand.New(rand.NewSource(1)), 0.2, 1)
+	fresh.Trigger()
+	want := *newPistolShot()
+	fresh.UpdateForce(&want, 0.1)
+	if shot.forceAccumulator.IsZero() || shot.forceAccumulator != want.forceAccumulator {
+		t.Errorf("force after prediction = %v, want %v", shot.forceAccumulator, want.forceAccumulator)
+	}
+}
+
+func BenchmarkUpdateForces(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			var registry ForceRegistry
+			particles := make([]Particle, n)
+			for i := range particles {
+				particles[i] = newBenchParticle()
+				registry.AddForce(&particles[i], NewDragGenerator(0.1, 0.01))
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				registry.UpdateForces(0.016)
+			}
+		})
+	}
+}
+
+func BenchmarkGenerators(b *testing.B) {
+	generators := []struct {
+		name string
+		fg   ForceGenerator
+	}{
+		{"Drag", NewDragGenerator(0.1, 0.01)},
+		{"Gravity", NewGravityGenerator(math64.NewVector3(0, -9.81, 0))},
+		{"PointGravity", NewPointGravityGenerator(math64.Vector3{})},
+	}
+	for _, g := range generators {
+		b.Run(g.name, func(b *testing.B) {
+			p := newBenchParticle()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				g.fg.UpdateForce(&p, 0.016)
+			}
+		})
+	}
+}
+
+// forceSink keeps the results of the copy-style benchmarks alive so they aren't optimized away.
+var forceSink math64.Vector3
+
+// BenchmarkCopyStyleGenerators measures the drag and gravity forces computed with copy-style
+// vector methods, to compare with the in-place generators in BenchmarkGenerators. Both styles
+// report zero allocations, as Vector3 is a small value type that stays on the stack.
+func BenchmarkCopyStyleGenerators(b *testing.B) {
+	p := newBenchParticle()
+	b.Run("Drag", func(b *testing.B) {
+		drag := NewDragGenerator(0.1, 0.01)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			forceSink = copyDragForce(drag, &p)
+		}
+	})
+	b.Run("Gravity", func(b *testing.B) {
+		gravity := NewGravityGenerator(math64.NewVector3(0, -9.81, 0))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			forceSink = copyGravityForce(gravity, &p)
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package physics
+
+import (
+	"bytes"
+	"encoding/gob"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+// particleGob mirrors Particle with every field exported, so gob can see the unexported state.
+type particleGob struct {
+	Position          math64.Vector3
+	Velocity          math64.Vector3
+	Acceleration      math64.Vector3
+	Damping           float64
+	DampingAxes       math64.Vector3
+	InverseMass       float64
+	Lifetime          float64
+	CollisionLayer    uint32
+	ForceAccumulator  math64.Vector3
+	Age               float64
+	Pinned            bool
+	PinnedInverseMass float64
+}
+
+// GobEncode implements gob.GobEncoder. Along with the exported fields, it encodes the particle's
+// inverse mass, accumulated force and age, so a decoded particle integrates exactly like the
+// original. The position trail is render-only state, and is not encoded.
+func (p *Particle) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(particleGob{
+		Position:          p.Position,
+		Velocity:          p.Velocity,
+		Acceleration:      p.Acceleration,
+		Damping:           p.Damping,
+		DampingAxes:       p.DampingAxes,
+		InverseMass:       p.inverseMass,
+		Lifetime:          p.Lifetime,
+		CollisionLayer:    p.CollisionLayer,
+		ForceAccumulator:  p.forceAccumulator,
+		Age:               p.age,
+		Pinned:            p.pinned,
+		PinnedInverseMass: p.pinnedInverseMass,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder, decoding a particle written by GobEncode. The decoded
+// particle's trail is disabled.
+func (p *Particle) GobDecode(data []byte) error {
+	var g particleGob
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&g); err != nil {
+		return err
+	}
+
+	*p = Particle{
+		Position:          g.Position,
+		Velocity:          g.Velocity,
+		Acceleration:      g.Acceleration,
+		Damping:           g.Damping,
+		DampingAxes:       g.DampingAxes,
+		inverseMass:       g.InverseMass,
+		Lifetime:          g.Lifetime,
+		CollisionLayer:    g.CollisionLayer,
+		forceAccumulator:  g.ForceAccumulator,
+		age:               g.Age,
+		pinned:            g.Pinned,
+		pinnedInverseMass: g.PinnedInverseMass,
+	}
+	return nil
+}
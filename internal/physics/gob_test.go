@@ -0,0 +1,63 @@
+package physics
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+func TestParticleGobRoundTrip(t *testing.T) {
+	pinned := newTestParticle(math64.NewVector3(1, 1, 1), math64.Vector3{}, 3)
+	pinned.Pin()
+
+	aged := NewParticleMass(math64.NewVector3(1, 2, 3), math64.NewVector3(-4, 5, 0.5), math64.NewVector3(0, -9.81, 0), 0.9, 2)
+	aged.Lifetime = 10
+	aged.CollisionLayer = 0b101
+	aged.DampingAxes = math64.NewVector3(1, 0.5, 1)
+	aged.Integrate(0.25)
+	aged.AddForce(math64.NewVector3(3, 0, -1))
+
+	tests := []struct {
+		name     string
+		particle *Particle
+	}{
+		{"aged with a pending force", &aged},
+		{"pinned", pinned},
+		{"infinite mass", newTestParticle(math64.Vector3{}, math64.NewVector3(1, 0, 0), 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := gob.NewEncoder(&buf).Encode(tt.particle); err != nil {
+				t.Fatal(err)
+			}
+			var decoded Particle
+			if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+				t.Fatal(err)
+			}
+
+			original := *tt.particle
+			original.trail = trail{}
+			if decoded.Pinned() != original.Pinned() {
+				t.Errorf("decoded Pinned() = %v, want %v", decoded.Pinned(), original.Pinned())
+			}
+			decoded.Unpin()
+			original.Unpin()
+
+			// Both copies must now integrate identically, through the unexported state too.
+			for i := 0; i < 10; i++ {
+				errOriginal, errDecoded := original.Integrate(0.1), decoded.Integrate(0.1)
+				if (errOriginal == nil) != (errDecoded == nil) {
+					t.Fatalf("step %d: original error %v, decoded error %v", i, errOriginal, errDecoded)
+				}
+			}
+			if decoded.Position != original.Position || decoded.Velocity != original.Velocity ||
+				decoded.Age() != original.Age() || decoded.Expired() != original.Expired() ||
+				decoded.Mass() != original.Mass() || decoded.CollisionLayers() != original.CollisionLayers() {
+				t.Errorf("decoded particle integrated to %+v, want %+v", decoded, original)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+// gridCell identifies a cell of a SpatialGrid by its integer coordinates.
+type gridCell struct {
+	X, Y, Z int64
+}
+
+// key returns the cell's key in SpatialGrid.cells. Hashing the integer coordinates with a cell size
+// of one gives the same hash as any point in the cell does with the grid's cell size.
+func (c gridCell) key() uint64 {
+	return math64.NewVector3(float64(c.X), float64(c.Y), float64(c.Z)).Hash(1)
+}
+
+// SpatialGrid buckets particles into a uniform grid of cubic cells, so queries about a region of
+// space only need to look at the particles in nearby cells, rather than every particle.
+//
+// The grid stores each particle under the cell of its position when it was inserted. It doesn't
+// track movement, so it is usually cleared and refilled once per frame.
+type SpatialGrid struct {
+	cellSize float64
+	cells    map[uint64][]*Particle // Particles by the math64.Vector3.Hash of their cell.
+	occupied []uint64               // Keys of the occupied cells, in the order they were filled.
+}
+
+// NewSpatialGrid creates an empty grid with cells of the given size, which must be positive. A
+// good cell size is around the radius of a typical query. It panics if cellSize isn't positive.
+func NewSpatialGrid(cellSize float64) *SpatialGrid {
+	if !(cellSize > 0) {
+		panic(fmt.Sprintf("physics: NewSpatialGrid cell size %v is not positive", cellSize))
+	}
+	return &SpatialGrid{
+		cellSize: cellSize,
+		cells:    make(map[uint64][]*Particle),
+	}
+}
+
+// cellOf returns the cell containing the point.
+func (g *SpatialGrid) cellOf(point math64.Vector3) gridCell {
+	return gridCell{
+		X: int64(math.Floor(point.X / g.cellSize)),
+		Y: int64(math.Floor(point.Y / g.cellSize)),
+		Z: int64(math.Floor(point.Z / g.cellSize)),
+	}
+}
+
+// Insert adds the particle to the grid at its current position.
+func (g *SpatialGrid) Insert(particle *Particle) {
+	key := particle.Position.Hash(g.cellSize)
+	if len(g.cells[key]) == 0 {
+		g.occupied = append(g.occupied, key)
+	}
+	g.cells[key] = append(g.cells[key], particle)
+}
+
+// Clear removes every particle from the grid.
+func (g *SpatialGrid) Clear() {
+	clear(g.cells)
+	g.occupied = g.occupied[:0]
+}
+
+// QueryRadius returns every particle in the grid within radius of center, inclusive. Only the
+// cells overlapping the query's bounding box are searched. Cells are searched in a fixed order, so
+// the same query on a grid filled the same way always returns the particles in the same order.
+func (g *SpatialGrid) QueryRadius(center math64.Vector3, radius float64) []*Particle {
+	if radius < 0 {
+		return nil
+	}
+
+	var found []*Particle
+	radiusSquared := radius * radius
+	collect := func(particles []*Particle) {
+		for _, p := range particles {
+			if p.Position.DistanceSquared(center) <= radiusSquared {
+				found = append(found, p)
+			}
+		}
+	}
+
+	extent := math64.NewVector3(radius, radius, radius)
+	lo := g.cellOf(center.SubCopy(extent))
+	hi := g.cellOf(center.AddCopy(extent))
+
+	// A query spanning more cells than are occupied is cheaper to answer from the occupied cells.
+	span := float64(hi.X-lo.X+1) * float64(hi.Y-lo.Y+1) * float64(hi.Z-lo.Z+1)
+	if span > float64(len(g.occupied)) {
+		for _, key := range g.occupied {
+			collect(g.cells[key])
+		}
+		return found
+	}
+
+	for x := lo.X; x <= hi.X; x++ {
+		for y := lo.Y; y <= hi.Y; y++ {
+			for z := lo.Z; z <= hi.Z; z++ {
+				collect(g.cells[gridCell{x, y, z}.key()])
+			}
+		}
+	}
+	return found
+}
@@ -0,0 +1,159 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+func TestSpatialGridQueryRadius(t *testing.T) {
+	positions := []math64.Vector3{
+		math64.NewVector3(0, 0, 0),
+		math64.NewVector3(1.5, 0, 0),
+		math64.NewVector3(2, 0, 0), // Exactly on the radius.
+		math64.NewVector3(2.001, 0, 0),
+		math64.NewVector3(0, -1.9, 0),
+		math64.NewVector3(1.5, 1.5, 0), // Inside the bounding box, outside the radius.
+		math64.NewVector3(-10, 10, 3),
+	}
+
+	tests := []struct {
+		name   string
+		center math64.Vector3
+		radius float64
+		want   []int
+	}{
+		{"inside and on the radius", math64.Vector3{}, 2, []int{0, 1, 2, 4}},
+		{"zero radius", math64.NewVector3(1.5, 0, 0), 0, []int{1}},
+		{"negative radius", math64.Vector3{}, -1, nil},
+		{"empty region", math64.NewVector3(50, 50, 50), 3, nil},
+		{"everything", math64.Vector3{}, 100, []int{0, 1, 2, 3, 4, 5, 6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewSpatialGrid(1)
+			var particles []*Particle
+			for _, pos := range positions {
+				p := newTestParticle(pos, math64.Vector3{}, 1)
+				particles = append(particles, p)
+				g.Insert(p)
+			}
+
+			var want []*Particle
+			for _, i := range tt.want {
+				want = append(want, particles[i])
+			}
+			got := g.QueryRadius(tt.center, tt.radius)
+			if !sameParticleSet(got, want) {
+				t.Errorf("QueryRadius(%v, %v) = %v, want %v", tt.center, tt.radius, positionsOf(got), positionsOf(want))
+			}
+		})
+	}
+}
+
+func TestSpatialGridQueryRadiusMatchesBruteForce(t *testing.T) {
+	tests := []struct {
+		name     string
+		cellSize float64
+		count    int
+		radius   float64
+	}{
+		{"small cells", 0.5, 200, 2},
+		{"cells match radius", 2, 200, 2},
+		{"large cells", 10, 200, 2},
+		{"query wider than occupied cells", 1, 20, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			point := func() math64.Vector3 {
+				return math64.NewVector3(rng.Float64()*20-10, rng.Float64()*20-10, rng.Float64()*20-10)
+			}
+
+			g := NewSpatialGrid(tt.cellSize)
+			var particles []*Particle
+			for i := 0; i < tt.count; i++ {
+				p := newTestParticle(point(), math64.Vector3{}, 1)
+				particles = append(particles, p)
+				g.Insert(p)
+			}
+
+			for i := 0; i < 50; i++ {
+				center := point()
+				var want []*Particle
+				for _, p := range particles {
+					if p.Position.DistanceSquared(center) <= tt.radius*tt.radius {
+						want = append(want, p)
+					}
+				}
+
+				got := g.QueryRadius(center, tt.radius)
+				if !sameParticleSet(got, want) {
+					t.Fatalf("QueryRadius(%v, %v) found %d particles, brute force found %d", center, tt.radius, len(got), len(want))
+				}
+				if again := g.QueryRadius(center, tt.radius); !slices.Equal(again, got) {
+					t.Fatalf("QueryRadius(%v, %v) returned a different order on the second call", center, tt.radius)
+				}
+			}
+		})
+	}
+}
+
+func TestSpatialGridClear(t *testing.T) {
+	g := NewSpatialGrid(1)
+	old := newTestParticle(math64.NewVector3(0.5, 0.5, 0.5), math64.Vector3{}, 1)
+	g.Insert(old)
+	g.Clear()
+	refilled := newTestParticle(math64.NewVector3(0.5, 0.5, 0.5), math64.Vector3{}, 1)
+	g.Insert(refilled)
+
+	for _, radius := range []float64{1, 100} {
+		if got := g.QueryRadius(math64.Vector3{}, radius); !slices.Equal(got, []*Particle{refilled}) {
+			t.Errorf("QueryRadius after Clear with radius %v = %v, want only the refilled particle", radius, positionsOf(got))
+		}
+	}
+}
+
+// sameParticleSet reports whether a and b hold the same particles, in any order.
+func sameParticleSet(a, b []*Particle) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[*Particle]int)
+	for _, p := range a {
+		seen[p]++
+	}
+	for _, p := range b {
+		seen[p]--
+		if seen[p] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// positionsOf returns the positions of the particles, for readable failure messages.
+func positionsOf(particles []*Particle) []math64.Vector3 {
+	positions := make([]math64.Vector3, len(particles))
+	for i, p := range particles {
+		positions[i] = p.Position
+	}
+	return positions
+}
+
+func TestNewSpatialGridInvalidCellSize(t *testing.T) {
+	for _, cellSize := range []float64{0, -2, math.NaN()} {
+		t.Run(fmt.Sprint(cellSize), func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewSpatialGrid(%v) did not panic", cellSize)
+				}
+			}()
+			NewSpatialGrid(cellSize)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package physics
+
+import "github.com/user54778/cyclone/internal/math64"
+
+// GroundContactGenerator generates contacts between a set of particles and a flat horizontal
+// ground plane at Height.
+type GroundContactGenerator struct {
+	Particles   []*Particle
+	Height      float64 // Y coordinate of the ground plane.
+	Restitution float64 // Restitution of every ground contact.
+	Friction    float64 // Friction coefficient of every ground contact.
+	// RestitutionFunc, if set, gives every ground contact a restitution that depends on the impact
+	// speed, in place of Restitution. See ParticleContact.RestitutionFunc.
+	RestitutionFunc func(impactSpeed float64) float64
+	// Layers, if non-zero, limits the ground to particles on at least one of these collision
+	// layers. Particles on no matching layer fall through it.
+	Layers uint32
+}
+
+// NewGroundContactGenerator creates a ground plane at the given height for the given particles.
+func NewGroundContactGenerator(particles []*Particle, height, restitution, friction float64) *GroundContactGenerator {
+	return &GroundContactGenerator{
+		Particles:   particles,
+		Height:      height,
+		Restitution: restitution,
+		Friction:    friction,
+	}
+}
+
+// AddContact writes a contact for every particle at or below the ground, up to len(contacts).
+func (g *GroundContactGenerator) AddContact(contacts []ParticleContact) int {
+	used := 0
+	for _, p := range g.Particles {
+		if used >= len(contacts) {
+			// We've run out of contacts to fill.
+			break
+		}
+
+		if p.Position.Y > g.Height {
+			continue
+		}
+		if g.Layers != 0 && !p.InLayer(g.Layers) {
+			continue
+		}
+
+		contacts[used] = ParticleContact{
+			Particles:       [2]*Particle{p, nil},
+			ContactNormal:   math64.NewVector3(0, 1, 0),
+			Penetration:     g.Height - p.Position.Y,
+			Restitution:     g.Restitution,
+			RestitutionFunc: g.RestitutionFunc,
+			Friction:        g.Friction,
+		}
+		used++
+	}
+	return used
+}
+
+// PlaneContactGenerator generates contacts between a set of particles and an arbitrary plane,
+// such as a ramp or a wall. The plane is the set of points x where Normal . x = Offset, and
+// particles are kept on the side Normal points towards.
+type PlaneContactGenerator struct {
+	Particles   []*Particle
+	Normal      math64.Vector3 // Normal of the plane, pointing to its open side. Need not be unit length.
+	Offset      float64        // Distance of the plane from the origin along the unit Normal.
+	Restitution float64        // Restitution of every plane contact.
+	Friction    float64        // Friction coefficient of every plane contact.
+}
+
+// NewPlaneContactGenerator creates a plane with the given normal and offset for the given particles.
+func NewPlaneContactGenerator(particles []*Particle, normal math64.Vector3, offset, restitution, friction float64) *PlaneContactGenerator {
+	return &PlaneContactGenerator{
+		Particles:   particles,
+		Normal:      normal,
+		Offset:      offset,
+		Restitution: restitution,
+		Friction:    friction,
+	}
+}
+
+// AddContact writes a contact for every particle on or behind the plane, up to len(contacts). The
+// penetration is the particle's distance behind the plane, along its normal.
+func (g *PlaneContactGenerator) AddContact(contacts []ParticleContact) int {
+	normal, err := g.Normal.NormalizeChecked()
+	if err != nil {
+		// A plane without a normal can't be on any side of a particle.
+		return 0
+	}
+
+	used := 0
+	for _, p := range g.Particles {
+		if used >= len(contacts) {
+			// We've run out of contacts to fill.
+			break
+		}
+
+		distance := normal.Dot(p.Position) - g.Offset
+		if distance > 0 {
+			continue
+		}
+
+		contacts[used] = ParticleContact{
+			Particles:     [2]*Particle{p, nil},
+			ContactNormal: normal,
+			Penetration:   -distance,
+			Restitution:   g.Restitution,
+			Friction:      g.Friction,
+		}
+		used++
+	}
+	return used
+}
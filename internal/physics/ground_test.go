@@ -0,0 +1,75 @@
+package physics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+func TestPlaneContactGenerator(t *testing.T) {
+	// A 45-degree ramp through the origin, rising along X, with its open side above it.
+	ramp := math64.NewVector3(-1, 1, 0)
+	unitRamp := math64.NewVector3(-1, 1, 0).Normalize()
+
+	tests := []struct {
+		name            string
+		normal          math64.Vector3
+		offset          float64
+		position        math64.Vector3
+		wantContact     bool
+		wantNormal      math64.Vector3
+		wantPenetration float64
+	}{
+		{"behind a 45 degree ramp", ramp, 0, math64.NewVector3(1, 0, 0), true, unitRamp, math.Sqrt2 / 2},
+		{"on a 45 degree ramp", ramp, 0, math64.NewVector3(2, 2, 5), true, unitRamp, 0},
+		{"above a 45 degree ramp", ramp, 0, math64.NewVector3(0, 1, 0), false, math64.Vector3{}, 0},
+		{"offset ramp", ramp, math.Sqrt2, math64.NewVector3(0, 1, 0), true, unitRamp, math.Sqrt2 / 2},
+		{"wall", math64.NewVector3(0, 0, -2), -3, math64.NewVector3(0, 0, 3.5), true, math64.NewVector3(0, 0, -1), 0.5},
+		{"zero normal", math64.Vector3{}, 0, math64.NewVector3(1, 0, 0), false, math64.Vector3{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(tt.position, math64.Vector3{}, 1)
+			g := NewPlaneContactGenerator([]*Particle{p}, tt.normal, tt.offset, 0.5, 0.25)
+
+			contacts := make([]ParticleContact, 1)
+			used := g.AddContact(contacts)
+			if (used == 1) != tt.wantContact {
+				t.Fatalf("AddContact() = %d contacts, want contact %v", used, tt.wantContact)
+			}
+			if used == 0 {
+				return
+			}
+
+			c := contacts[0]
+			if c.Particles != [2]*Particle{p, nil} {
+				t.Errorf("contact particles = %v, want the particle and nil", c.Particles)
+			}
+			if !c.ContactNormal.ApproxEqual(tt.wantNormal, 1e-12) {
+				t.Errorf("contact normal = %v, want %v", c.ContactNormal, tt.wantNormal)
+			}
+			if !math64.ApproxEqual(c.Penetration, tt.wantPenetration, 1e-12) {
+				t.Errorf("penetration = %v, want %v", c.Penetration, tt.wantPenetration)
+			}
+			if c.Restitution != 0.5 || c.Friction != 0.25 {
+				t.Errorf("restitution and friction = %v, %v, want 0.5, 0.25", c.Restitution, c.Friction)
+			}
+		})
+	}
+}
+
+func TestPlaneContactGeneratorLimit(t *testing.T) {
+	var particles []*Particle
+	for i := 0; i < 3; i++ {
+		particles = append(particles, newTestParticle(math64.NewVector3(0, -1, 0), math64.Vector3{}, 1))
+	}
+	g := NewPlaneContactGenerator(particles, math64.NewVector3(0, 1, 0), 0, 0, 0)
+
+	for _, limit := range []int{0, 2, 5} {
+		want := min(limit, len(particles))
+		if used := g.AddContact(make([]ParticleContact, limit)); used != want {
+			t.Errorf("AddContact with room for %d = %d contacts, want %d", limit, used, want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package physics
+
+import "github.com/user54778/cyclone/internal/math64"
+
+// LerpParticleState blends two snapshots of a particle, such as consecutive network updates,
+// returning a particle whose position, velocity and acceleration lie the fraction t of the way
+// from a to b. A t of 0 gives a's state and 1 gives b's; values outside [0, 1] extrapolate.
+//
+// Everything else, such as the mass, damping, lifetime and accumulated forces, is taken from a.
+// The result has no motion trail.
+func LerpParticleState(a, b Particle, t float64) Particle {
+	p := a
+	p.trail = trail{} // The blend mustn't share, and later write into, a's trail buffer.
+
+	p.Position = lerpVector(a.Position, b.Position, t)
+	p.Velocity = lerpVector(a.Velocity, b.Velocity, t)
+	p.Acceleration = lerpVector(a.Acceleration, b.Acceleration, t)
+
+	return p
+}
+
+// lerpVector returns the point the fraction t of the way from a to b.
+func lerpVector(a, b math64.Vector3, t float64) math64.Vector3 {
+	v := a
+	v.ScaleAdd(b.SubCopy(a), t)
+	return v
+}
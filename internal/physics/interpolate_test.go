@@ -0,0 +1,77 @@
+package physics
+
+import (
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+func TestLerpParticleState(t *testing.T) {
+	a := NewParticleMass(
+		math64.NewVector3(0, 2, -4),
+		math64.NewVector3(1, 0, 0),
+		math64.NewVector3(0, -10, 0),
+		0.9,
+		2,
+	)
+	b := NewParticleMass(
+		math64.NewVector3(4, 6, 0),
+		math64.NewVector3(3, -2, 8),
+		math64.NewVector3(0, -20, 0),
+		0.5,
+		8,
+	)
+
+	tests := []struct {
+		name             string
+		t                float64
+		wantPosition     math64.Vector3
+		wantVelocity     math64.Vector3
+		wantAcceleration math64.Vector3
+	}{
+		{"start", 0, a.Position, a.Velocity, a.Acceleration},
+		{"end", 1, b.Position, b.Velocity, b.Acceleration},
+		{"midpoint", 0.5, math64.NewVector3(2, 4, -2), math64.NewVector3(2, -1, 4), math64.NewVector3(0, -15, 0)},
+		{"quarter", 0.25, math64.NewVector3(1, 3, -3), math64.NewVector3(1.5, -0.5, 2), math64.NewVector3(0, -12.5, 0)},
+		{"extrapolated", 2, math64.NewVector3(8, 10, 4), math64.NewVector3(5, -4, 16), math64.NewVector3(0, -30, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LerpParticleState(a, b, tt.t)
+			if !got.Position.ApproxEqual(tt.wantPosition, 1e-12) {
+				t.Errorf("Position = %v, want %v", got.Position, tt.wantPosition)
+			}
+			if !got.Velocity.ApproxEqual(tt.wantVelocity, 1e-12) {
+				t.Errorf("Velocity = %v, want %v", got.Velocity, tt.wantVelocity)
+			}
+			if !got.Acceleration.ApproxEqual(tt.wantAcceleration, 1e-12) {
+				t.Errorf("Acceleration = %v, want %v", got.Acceleration, tt.wantAcceleration)
+			}
+			if got.Mass() != a.Mass() || got.Damping != a.Damping {
+				t.Errorf("mass, damping = %v, %v; want a's %v, %v", got.Mass(), got.Damping, a.Mass(), a.Damping)
+			}
+		})
+	}
+}
+
+func TestLerpParticleStateDoesNotShareTrail(t *testing.T) {
+	a := *newTestParticle(math64.Vector3{}, math64.NewVector3(1, 0, 0), 1)
+	a.SetTrailLength(4)
+	if err := a.Integrate(1); err != nil {
+		t.Fatal(err)
+	}
+	b := *newTestParticle(math64.NewVector3(10, 0, 0), math64.Vector3{}, 1)
+
+	blend := LerpParticleState(a, b, 0.5)
+	if got := blend.Trail(); got != nil {
+		t.Fatalf("blend Trail() = %v, want nil", got)
+	}
+	before := a.Trail()
+	blend.SetTrailLength(4)
+	if err := blend.Integrate(1); err != nil {
+		t.Fatal(err)
+	}
+	if got := a.Trail(); len(got) != len(before) || got[0] != before[0] {
+		t.Fatalf("integrating the blend changed a's trail from %v to %v", before, got)
+	}
+}
@@ -0,0 +1,258 @@
+package physics
+
+import "github.com/user54778/cyclone/internal/math64"
+
+// ParticleLink connects two particles together, generating a contact if they violate the
+// constraints of their link. It is embedded by the concrete link types, such as cables and rods.
+type ParticleLink struct {
+	Particles [2]*Particle
+}
+
+// currentLength returns the current length of the link.
+func (l *ParticleLink) currentLength() float64 {
+	return l.Particles[0].Position.SubCopy(l.Particles[1].Position).Magnitude()
+}
+
+// ParticleCable links a pair of particles, generating a contact if they stray too far apart.
+type ParticleCable struct {
+	ParticleLink
+	// MaxLength holds the maximum length of the cable.
+	MaxLength float64
+	// Restitution holds the restitution (bounciness) of the cable.
+	Restitution float64
+}
+
+// NewParticleCable creates a cable between a and b with the given maximum length and restitution.
+func NewParticleCable(a, b *Particle, maxLength, restitution float64) *ParticleCable {
+	return &ParticleCable{
+		ParticleLink: ParticleLink{Particles: [2]*Particle{a, b}},
+		MaxLength:    maxLength,
+		Restitution:  restitution,
+	}
+}
+
+// AddContact fills the given contact structure with the contact needed to keep the cable
+// from over-extending.
+func (c *ParticleCable) AddContact(contacts []ParticleContact) int {
+	if len(contacts) == 0 {
+		return 0
+	}
+
+	length := c.currentLength()
+
+	// Check if we're over-extended.
+	if length < c.MaxLength {
+		return 0
+	}
+
+	// Otherwise, return the contact pulling the particles back together.
+	contacts[0] = ParticleContact{
+		Particles:     c.Particles,
+		ContactNormal: c.Particles[1].Position.SubCopy(c.Particles[0].Position).Normalize(),
+		Penetration:   length - c.MaxLength,
+		Restitution:   c.Restitution,
+	}
+
+	return 1
+}
+
+// ParticleRod links a pair of particles, generating a contact if they stray too far apart
+// *or* too close together.
+type ParticleRod struct {
+	ParticleLink
+	// Length holds the length of the rod.
+	Length float64
+}
+
+// NewParticleRod creates a rod of the given length between a and b.
+func NewParticleRod(a, b *Particle, length float64) *ParticleRod {
+	return &ParticleRod{
+		ParticleLink: ParticleLink{Particles: [2]*Particle{a, b}},
+		Length:       length,
+	}
+}
+
+// AddContact fills the given contact structure with the contact needed to keep the rod
+// from extending or compressing.
+func (r *ParticleRod) AddContact(contacts []ParticleContact) int {
+	if len(contacts) == 0 {
+		return 0
+	}
+
+	currentLength := r.currentLength()
+
+	// Check if we're already at the correct length.
+	if currentLength == r.Length {
+		return 0
+	}
+
+	normal := r.Particles[1].Position.SubCopy(r.Particles[0].Position).Normalize()
+
+	// The contact normal depends on whether we're extending or compressing.
+	contact := ParticleContact{
+		Particles: r.Particles,
+		// Rods have no bounciness.
+		Restitution: 0,
+	}
+	if currentLength > r.Length {
+		contact.ContactNormal = normal
+		contact.Penetration = currentLength - r.Length
+	} else {
+		contact.ContactNormal = normal.Invert()
+		contact.Penetration = r.Length - currentLength
+	}
+	contacts[0] = contact
+
+	return 1
+}
+
+// ParticleConstraint connects a particle to an immovable anchor point, generating a contact if they
+// violate the constraints of the link. It is embedded by the concrete constraint types.
+type ParticleConstraint struct {
+	Particle *Particle
+	Anchor   math64.Vector3 // The point to which the particle is anchored.
+}
+
+// currentLength returns the current length of the constraint.
+func (c *ParticleConstraint) currentLength() float64 {
+	return c.Particle.Position.SubCopy(c.Anchor).Magnitude()
+}
+
+// ParticleCableConstraint ties a particle to an anchor point, generating a contact if the particle
+// strays too far from it.
+type ParticleCableConstraint struct {
+	ParticleConstraint
+	// MaxLength holds the maximum length of the cable.
+	MaxLength float64
+	// Restitution holds the restitution (bounciness) of the cable.
+	Restitution float64
+}
+
+// NewParticleCableConstraint creates a cable from the particle to the anchor with the given maximum
+// length and restitution.
+func NewParticleCableConstraint(particle *Particle, anchor math64.Vector3, maxLength, restitution float64) *ParticleCableConstraint {
+	return &ParticleCableConstraint{
+		ParticleConstraint: ParticleConstraint{Particle: particle, Anchor: anchor},
+		MaxLength:          maxLength,
+		Restitution:        restitution,
+	}
+}
+
+// AddContact fills the given contact structure with the contact needed to keep the cable
+// from over-extending. The contact is made with the scenery, so its second particle is nil.
+func (c *ParticleCableConstraint) AddContact(contacts []ParticleContact) int {
+	if len(contacts) == 0 {
+		return 0
+	}
+
+	length := c.currentLength()
+
+	// Check if we're over-extended.
+	if length < c.MaxLength {
+		return 0
+	}
+
+	contacts[0] = ParticleContact{
+		Particles:     [2]*Particle{c.Particle, nil},
+		ContactNormal: c.Anchor.SubCopy(c.Particle.Position).Normalize(),
+		Penetration:   length - c.MaxLength,
+		Restitution:   c.Restitution,
+	}
+
+	return 1
+}
+
+// ParticleRodConstraint ties a particle to an anchor point with a rigid rod, such as a pendulum's,
+// generating a contact if the particle moves either too far from the anchor *or* too close to it.
+type ParticleRodConstraint struct {
+	ParticleConstraint
+	// Length holds the length of the rod.
+	Length float64
+}
+
+// NewParticleRodConstraint creates a rod of the given length from the particle to the anchor.
+func NewParticleRodConstraint(particle *Particle, anchor math64.Vector3, length float64) *ParticleRodConstraint {
+	return &ParticleRodConstraint{
+		ParticleConstraint: ParticleConstraint{Particle: particle, Anchor: anchor},
+		Length:             length,
+	}
+}
+
+// AddContact fills the given contact structure with the contact needed to keep the rod from
+// extending or compressing. The contact is made with the scenery, so its second particle is nil.
+func (r *ParticleRodConstraint) AddContact(contacts []ParticleContact) int {
+	if len(contacts) == 0 {
+		return 0
+	}
+
+	currentLength := r.currentLength()
+
+	// Check if we're already at the correct length.
+	if currentLength == r.Length {
+		return 0
+	}
+
+	normal := r.Anchor.SubCopy(r.Particle.Position).Normalize()
+
+	// The contact normal depends on whether we're extending or compressing.
+	contact := ParticleContact{
+		Particles: [2]*Particle{r.Particle, nil},
+		// Rods have no bounciness.
+		Restitution: 0,
+	}
+	if currentLength > r.Length {
+		contact.ContactNormal = normal
+		contact.Penetration = currentLength - r.Length
+	} else {
+		contact.ContactNormal = normal.Invert()
+		contact.Penetration = r.Length - currentLength
+	}
+	contacts[0] = contact
+
+	return 1
+}
+
+// BuildChain creates a chain, or rope, of segments+1 particles evenly spaced from start to end,
+// each joined to the next by a rod of the spacing's length. Every particle has the given mass, no
+// damping and no acceleration; set gravity and pin the ends as needed.
+//
+// The particles and rods still have to be added to a world, the rods by pointer:
+//
+//	nodes, rods := physics.BuildChain(start, end, 10, 0.5)
+//	for _, p := range nodes {
+//		world.AddParticle(p)
+//	}
+//	for i := range rods {
+//		world.AddContactGenerator(&rods[i])
+//	}
+//
+// A segments of less than one builds nothing.
+func BuildChain(start, end math64.Vector3, segments int, massPerNode float64) ([]*Particle, []ParticleRod) {
+	if segments < 1 {
+		return nil, nil
+	}
+
+	step := end.SubCopy(start)
+	step.Scale(1 / float64(segments))
+	length := step.Magnitude()
+
+	nodes := make([]*Particle, segments+1)
+	for i := range nodes {
+		position := start.ScaleAddCopy(step, float64(i))
+		if i == segments {
+			position = end // Land exactly on the end, free of rounding.
+		}
+		p := NewParticleMass(position, math64.Vector3{}, math64.Vector3{}, 1.0, massPerNode)
+		nodes[i] = &p
+	}
+
+	rods := make([]ParticleRod, segments)
+	for i := range rods {
+		rods[i] = ParticleRod{
+			ParticleLink: ParticleLink{Particles: [2]*Particle{nodes[i], nodes[i+1]}},
+			Length:       length,
+		}
+	}
+
+	return nodes, rods
+}
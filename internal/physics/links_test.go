@@ -0,0 +1,137 @@
+package physics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+func TestParticleRodConstraintContact(t *testing.T) {
+	anchor := math64.NewVector3(0, 5, 0)
+	tests := []struct {
+		name            string
+		position        math64.Vector3
+		wantContact     bool
+		wantNormal      math64.Vector3
+		wantPenetration float64
+	}{
+		{"at length", math64.NewVector3(0, 3, 0), false, math64.Vector3{}, 0},
+		{"extended", math64.NewVector3(0, 2.5, 0), true, math64.NewVector3(0, 1, 0), 0.5},
+		{"compressed", math64.NewVector3(1.5, 5, 0), true, math64.NewVector3(1, 0, 0), 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(tt.position, math64.Vector3{}, 1)
+			rod := NewParticleRodConstraint(p, anchor, 2)
+
+			contacts := make([]ParticleContact, 1)
+			used := rod.AddContact(contacts)
+			if (used == 1) != tt.wantContact {
+				t.Fatalf("AddContact() = %d contacts, want contact %v", used, tt.wantContact)
+			}
+			if used == 0 {
+				return
+			}
+
+			c := contacts[0]
+			if c.Particles != [2]*Particle{p, nil} || c.Restitution != 0 {
+				t.Errorf("contact = %+v, want an inelastic contact between the particle and the scenery", c)
+			}
+			if !c.ContactNormal.ApproxEqual(tt.wantNormal, 1e-12) || !math64.ApproxEqual(c.Penetration, tt.wantPenetration, 1e-12) {
+				t.Errorf("contact normal %v penetration %v, want %v and %v", c.ContactNormal, c.Penetration, tt.wantNormal, tt.wantPenetration)
+			}
+		})
+	}
+}
+
+func TestParticleRodConstraintPendulum(t *testing.T) {
+	tests := []struct {
+		name   string
+		length float64
+		angle  float64 // Release angle from the vertical, in degrees.
+	}{
+		{"hanging at rest", 4, 0},
+		{"small swing", 4, 10},
+		{"wide swing", 4, 60},
+		{"horizontal release", 2, 90},
+		{"released above the anchor", 3, 150},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anchor := math64.NewVector3(0, 10, 0)
+			theta := math64.DegToRad(tt.angle)
+			offset := math64.NewVector3(tt.length*math.Sin(theta), -tt.length*math.Cos(theta), 0)
+			bob := newTestParticle(anchor.AddCopy(offset), math64.Vector3{}, 1)
+			bob.Acceleration = math64.NewVector3(0, -9.81, 0)
+
+			w := NewParticleWorld(1, 1)
+			w.AddParticle(bob)
+			w.AddContactGenerator(NewParticleRodConstraint(bob, anchor, tt.length))
+
+			for i := 0; i < 1200; i++ {
+				if err := w.RunPhysics(1.0 / 120); err != nil {
+					t.Fatal(err)
+				}
+				if d := bob.Position.Distance(anchor); !math64.ApproxEqual(d, tt.length, 1e-9) {
+					t.Fatalf("step %d: bob is %v from the anchor, want %v", i, d, tt.length)
+				}
+				// The rod does no work, so the bob never swings higher than it was released.
+				if height := bob.Position.Y - anchor.Y; height > offset.Y+1e-6 {
+					t.Fatalf("step %d: bob rose to %v, above its release height %v", i, height, offset.Y)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildChain(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end math64.Vector3
+		segments   int
+		mass       float64
+		wantLength float64
+	}{
+		{"single segment", math64.Vector3{}, math64.NewVector3(2, 0, 0), 1, 1, 2},
+		{"horizontal rope", math64.NewVector3(-5, 3, 0), math64.NewVector3(5, 3, 0), 10, 0.5, 1},
+		{"diagonal", math64.NewVector3(1, 1, 1), math64.NewVector3(4, 5, 1), 5, 2, 1},
+		{"uneven spacing", math64.Vector3{}, math64.NewVector3(0, -1, 0), 3, 1, 1.0 / 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, rods := BuildChain(tt.start, tt.end, tt.segments, tt.mass)
+			if len(nodes) != tt.segments+1 || len(rods) != tt.segments {
+				t.Fatalf("BuildChain made %d nodes and %d rods, want %d and %d", len(nodes), len(rods), tt.segments+1, tt.segments)
+			}
+			if nodes[0].Position != tt.start || nodes[len(nodes)-1].Position != tt.end {
+				t.Errorf("chain runs from %v to %v, want %v to %v", nodes[0].Position, nodes[len(nodes)-1].Position, tt.start, tt.end)
+			}
+
+			for i, p := range nodes {
+				if p.Mass() != tt.mass {
+					t.Errorf("node %d has mass %v, want %v", i, p.Mass(), tt.mass)
+				}
+			}
+			for i, rod := range rods {
+				if rod.Particles != [2]*Particle{nodes[i], nodes[i+1]} {
+					t.Errorf("rod %d doesn't join nodes %d and %d", i, i, i+1)
+				}
+				if !math64.ApproxEqual(rod.Length, tt.wantLength, 1e-12) {
+					t.Errorf("rod %d has length %v, want %v", i, rod.Length, tt.wantLength)
+				}
+				if d := nodes[i].Position.Distance(nodes[i+1].Position); !math64.ApproxEqual(d, rod.Length, 1e-12) {
+					t.Errorf("nodes %d and %d are %v apart, want the rod length %v", i, i+1, d, rod.Length)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildChainNoSegments(t *testing.T) {
+	for _, segments := range []int{0, -1} {
+		if nodes, rods := BuildChain(math64.Vector3{}, math64.NewVector3(1, 0, 0), segments, 1); nodes != nil || rods != nil {
+			t.Errorf("BuildChain with %d segments = %v, %v, want nothing", segments, nodes, rods)
+		}
+	}
+}
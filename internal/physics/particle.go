@@ -6,7 +6,6 @@ import (
 	"math"
 
 	"github.com/user54778/cyclone/internal/math64"
-	"github.com/user54778/cyclone/internal/physicslog"
 )
 
 // Particle is the simplest object that can be simulated
@@ -24,24 +23,59 @@ type Particle struct {
 	Acceleration math64.Vector3
 	// Damping is our solution to give a rough approximation for drag
 	// to apply to our particle in accordance with Newton's First Law.
+	// It is the proportion of velocity retained each second, in the range (0, 1],
+	// where 1.0 means no damping. Prefer SetDamping, which enforces the range.
 	Damping float64
+	// DampingAxes, if non-zero, replaces Damping with a separate damping for each axis, so
+	// velocity can be damped more along one axis than another, e.g. more vertically at a water
+	// surface. Each component should be in the range (0, 1], like Damping.
+	DampingAxes math64.Vector3
 	// Inverse Mass is more useful to hold since it makes integration simpler
 	// and is more useful to have objects with infinite mass (i.e., walls, floors, etc)
 	// than storing mass itself, which could (although shouldn't) have zero mass.
 	// You can set this field with the Mass and InverseMass setters or set it directly.
 	inverseMass float64
+	// Lifetime is how long, in seconds, the particle lives before it expires.
+	// Zero or negative lifetime means the particle is immortal.
+	Lifetime float64
+	// CollisionLayer is the set of collision layers the particle is on, one bit per layer. Particles
+	// only collide when they share a layer. Zero puts the particle on DefaultCollisionLayer.
+	CollisionLayer uint32
 	// forceAccumulator accumulates every force to be applied at the next
 	// iteration *only*. It is zeroed at each integration step.
 	forceAccumulator math64.Vector3
+	// age is the total duration, in seconds, the particle has been integrated for.
+	age float64
+	// impulse is the impulse delivered by the accumulated force since ClearForces was last called.
+	impulse math64.Vector3
+	// stepWork is the work done by the accumulated force over the last integration step.
+	stepWork float64
+	// stepDistance is the distance moved over the last integration step.
+	stepDistance float64
+	// pinned is true while the particle is pinned in place by Pin.
+	pinned bool
+	// pinnedInverseMass holds the inverse mass to restore when the particle is unpinned.
+	pinnedInverseMass float64
+	// trail records the most recent integrated positions, when enabled with SetTrailLength.
+	trail trail
 }
 
+// DefaultDamping is the damping the particle constructors fall back to when given one SetDamping
+// rejects, such as zero. It retains all of the velocity, i.e. no damping.
+const DefaultDamping = 1.0
+
 // NewParticleMass creates a Particle object where the *mass* itself is passed in as a parameter.
+// The damping is validated by SetDamping. A damping above 1.0 is clamped to 1.0, and one it rejects
+// is replaced with DefaultDamping, so construction never fails; call SetDamping directly to detect
+// an invalid damping.
 func NewParticleMass(position, velocity, acceleration math64.Vector3, damping, mass float64) Particle {
 	p := Particle{
 		Position:     position,
 		Velocity:     velocity,
 		Acceleration: acceleration,
-		Damping:      damping,
+	}
+	if err := p.SetDamping(damping); err != nil {
+		p.Damping = DefaultDamping
 	}
 	p.SetMass(mass)
 
@@ -49,21 +83,53 @@ func NewParticleMass(position, velocity, acceleration math64.Vector3, damping, m
 }
 
 // NewParticleInverseMass creates a Particle object where the *inverse mass* is passed in as a parameter.
+// The damping is validated as in NewParticleMass.
 func NewParticleInverseMass(position, velocity, acceleration math64.Vector3, damping, inverseMass float64) Particle {
 	p := Particle{
 		Position:     position,
 		Velocity:     velocity,
 		Acceleration: acceleration,
-		Damping:      damping,
+	}
+	if err := p.SetDamping(damping); err != nil {
+		p.Damping = DefaultDamping
 	}
 	p.SetInverseMass(inverseMass)
 
 	return p
 }
 
+// SetDamping sets the particle's damping, the proportion of velocity retained each second.
+// A damping above 1.0 would add energy, and is clamped to 1.0 (no damping). A damping of zero
+// or less is rejected, since it stops the particle dead or produces NaN velocities, and the
+// current damping is left unchanged.
+func (p *Particle) SetDamping(damping float64) error {
+	switch {
+	case damping <= 0.0 || math.IsNaN(damping):
+		return newPhysicsError(ErrInvalidDamping, "damping must be in the range (0, 1]")
+	case damping > 1.0:
+		p.Damping = 1.0
+	default:
+		p.Damping = damping
+	}
+	return nil
+}
+
+// ConvertPerFrameDamping converts a damping given per frame, the proportion of velocity kept each
+// frame at referenceFPS frames per second, into the per-second damping Integrate expects. Keeping
+// perFrame of the velocity referenceFPS times over is the same as keeping perFrame^referenceFPS of
+// it once a second, so 0.99 per frame at 60 FPS converts to about 0.547.
+//
+// The result gives the same decay at any frame rate, where using the per-frame value directly
+// would damp far less than intended.
+func ConvertPerFrameDamping(perFrame float64, referenceFPS float64) float64 {
+	return math.Pow(perFrame, referenceFPS)
+}
+
 // SetMass is a helper to set the particle's mass, and calculates its inverse mass.
-// Zero or negative mass is treated as infinite.
+// Zero or negative mass is treated as infinite. Setting the mass unpins a particle pinned by Pin,
+// even if the new mass is infinite.
 func (p *Particle) SetMass(mass float64) {
+	p.pinned = false
 	if mass <= 0 {
 		p.inverseMass = 0.0
 	} else {
@@ -72,8 +138,9 @@ func (p *Particle) SetMass(mass float64) {
 }
 
 // SetInverseMass sets the inverseMass directly.
-// Zero or negative inverse will be treated as infinite.
+// Zero or negative inverse will be treated as infinite. Like SetMass, it unpins a pinned particle.
 func (p *Particle) SetInverseMass(inverseMass float64) {
+	p.pinned = false
 	if inverseMass <= 0 {
 		p.inverseMass = 0.0
 	} else {
@@ -90,6 +157,34 @@ func (p *Particle) Mass() float64 {
 	}
 }
 
+// Pin fixes the particle in place, as an anchor, by giving it infinite mass. Its current mass is
+// saved, and restored by Unpin. Pinning an already pinned particle does nothing.
+//
+// Setting the mass of a pinned particle unpins it without restoring the saved mass.
+func (p *Particle) Pin() {
+	if p.pinned {
+		return
+	}
+	p.pinned = true
+	p.pinnedInverseMass = p.inverseMass
+	p.inverseMass = 0
+}
+
+// Unpin releases a particle pinned by Pin, restoring the mass it had when it was pinned. Unpinning
+// a particle that isn't pinned does nothing.
+func (p *Particle) Unpin() {
+	if !p.pinned {
+		return
+	}
+	p.pinned = false
+	p.inverseMass = p.pinnedInverseMass
+}
+
+// Pinned reports whether the particle is pinned by Pin.
+func (p *Particle) Pinned() bool {
+	return p.pinned
+}
+
 func (p *Particle) HasFiniteMass() bool {
 	return p.inverseMass > 0.0
 }
@@ -100,15 +195,157 @@ func (p *Particle) KineticEnergy() float64 {
 	return 0.5 * p.Mass() * p.Velocity.Magnitude() * p.Velocity.Magnitude()
 }
 
+// RollAngle returns the angle, in radians, a sphere of the given radius would turn through if it
+// rolled along the ground at the particle's horizontal (X-Z) speed for duration. The angle is the
+// arc length travelled divided by the radius.
+//
+// Particles can't rotate; this is only meant to drive a render transform, by accumulating the
+// angle each frame.
+func (p *Particle) RollAngle(radius float64, duration float64) float64 {
+	if radius <= 0 {
+		return 0
+	}
+
+	horizontalSpeed := math.Hypot(p.Velocity.X, p.Velocity.Z)
+	return horizontalSpeed * duration / radius
+}
+
+// ImpulseTo returns the impulse, m * (v_target - v), that would instantly change the particle's
+// velocity to the target velocity. An infinite-mass particle can't be moved by any impulse, and
+// returns the zero vector.
+func (p *Particle) ImpulseTo(targetVelocity math64.Vector3) math64.Vector3 {
+	if !p.HasFiniteMass() {
+		return math64.Vector3{}
+	}
+
+	deltaV := targetVelocity.SubCopy(p.Velocity)
+	deltaV.Scale(p.Mass())
+	return deltaV
+}
+
+// ApplyImpulse instantly changes the particle's velocity by impulse * inverseMass. It has no
+// effect on an infinite-mass particle.
+func (p *Particle) ApplyImpulse(impulse math64.Vector3) {
+	p.Velocity.ScaleAdd(impulse, p.inverseMass)
+}
+
+// SteerToward turns the particle's velocity towards the target position, as a homing projectile
+// would, by at most turnRate*duration radians, keeping its speed unchanged. A particle already
+// heading within that angle of the target turns to face it exactly.
+//
+// A stationary particle, or one sitting on the target, has no heading to turn and is left alone.
+func (p *Particle) SteerToward(target math64.Vector3, turnRate float64, duration float64) {
+	p.Velocity = p.Velocity.RotateToward(target.SubCopy(p.Position), turnRate*duration)
+}
+
 // AddForce adds force to the particle to be applied at the next iteration.
 func (p *Particle) AddForce(force math64.Vector3) {
 	p.forceAccumulator.Add(force) // NOTE: This directly adds to the particle's ForceAccumulator,
 	// see if doing this in a functional style does not eat much memory.
 }
 
-// ClearForces sets the forceAccumulator to the zero value for a math64.Vector3.
+// AccumulatedImpulse returns the total impulse, J = F*dt, delivered to the particle by the forces
+// added with AddForce, summed over every generator and every integration step since ClearForces was
+// last called. It is read after integrating, as the world's StartFrame calls ClearForces, making it
+// the impulse the particle absorbed over the last frame.
+func (p *Particle) AccumulatedImpulse() math64.Vector3 {
+	return p.impulse
+}
+
+// StepWork returns the work, in joules, done on the particle by its accumulated force during the
+// last integration step: the force applied over the step dotted with the step's displacement.
+// The constant Acceleration, such as gravity set directly on the particle, isn't a force and
+// isn't counted.
+func (p *Particle) StepWork() float64 {
+	return p.stepWork
+}
+
+// StepDistance returns the straight-line distance the particle moved during the last integration
+// step, the magnitude of its change in position. A particle moving further than the obstacles it
+// might hit are thick can pass through them without a contact ever being generated.
+func (p *Particle) StepDistance() float64 {
+	return p.stepDistance
+}
+
+// WorkDone returns the work done by a constant force over a displacement, W = F . d. It is
+// positive when the force pushes along the displacement, and negative when it opposes it, as drag
+// should.
+func WorkDone(force, displacement math64.Vector3) float64 {
+	return force.Dot(displacement)
+}
+
+// ClearForces sets the forceAccumulator, and the impulse returned by AccumulatedImpulse, to the
+// zero value for a math64.Vector3.
 func (p *Particle) ClearForces() {
 	p.forceAccumulator = math64.Vector3{}
+	p.impulse = math64.Vector3{}
+}
+
+// Age returns the total duration, in seconds, the particle has been integrated for.
+func (p *Particle) Age() float64 {
+	return p.age
+}
+
+// SetTrailLength enables recording of the last n positions the particle is integrated to, for
+// rendering motion trails. Zero or less disables the trail. Changing the length clears the trail.
+func (p *Particle) SetTrailLength(n int) {
+	p.trail = newTrail(n)
+}
+
+// Trail returns the most recently integrated positions, oldest first, up to the trail length set
+// with SetTrailLength. It returns nil if the trail is disabled.
+func (p *Particle) Trail() []math64.Vector3 {
+	return p.trail.positions()
+}
+
+// Expired reports whether the particle has outlived its Lifetime. An immortal particle,
+// with a Lifetime of zero or less, never expires.
+func (p *Particle) Expired() bool {
+	return p.Lifetime > 0 && p.age > p.Lifetime
+}
+
+// DefaultCollisionLayer is the layer a particle with a zero CollisionLayer is on.
+const DefaultCollisionLayer uint32 = 1
+
+// CollisionLayers returns the set of layers the particle is on, which is DefaultCollisionLayer
+// if its CollisionLayer is unset.
+func (p *Particle) CollisionLayers() uint32 {
+	if p.CollisionLayer == 0 {
+		return DefaultCollisionLayer
+	}
+	return p.CollisionLayer
+}
+
+// InLayer reports whether the particle is on any of the layers in mask.
+func (p *Particle) InLayer(mask uint32) bool {
+	return p.CollisionLayers()&mask != 0
+}
+
+// CollidesWith reports whether the particle shares a collision layer with other.
+func (p *Particle) CollidesWith(other *Particle) bool {
+	return p.InLayer(other.CollisionLayers())
+}
+
+// AtRest reports whether the particle's speed is strictly below linearThreshold. A particle moving
+// at exactly the threshold is not at rest.
+func (p *Particle) AtRest(linearThreshold float64) bool {
+	return p.Velocity.Magnitude() < linearThreshold
+}
+
+// BounceOffPlane bounces the particle off the horizontal plane at height planeY. If the particle
+// has fallen below the plane while moving downward, it is moved back onto the plane and its Y
+// velocity is reflected and scaled by restitution. It returns true if a bounce occurred.
+//
+// This is a lightweight alternative to the contact system for particles that only need a floor.
+func (p *Particle) BounceOffPlane(planeY float64, restitution float64) bool {
+	if p.Position.Y >= planeY || p.Velocity.Y >= 0 {
+		return false
+	}
+
+	p.Position.Y = planeY
+	p.Velocity.Y = -p.Velocity.Y * restitution
+
+	return true
 }
 
 // Integrate updates the position and velocity of a point mass using equations for constant
@@ -118,11 +355,150 @@ func (p *Particle) Integrate(duration float64) error {
 	switch {
 	case p.inverseMass <= 0.0:
 		// return fmt.Errorf("integration is not performed on infinite mass")
-		return newPhysicsError("integration is not performed on infinite mass")
+		return newPhysicsError(ErrInfiniteMass, "integration is not performed on infinite mass")
 	case duration <= 0.0:
 		// return fmt.Errorf("can not perform integration on a negative duration")
-		return newPhysicsError("can not perform integration on a negative duration")
+		return newPhysicsError(ErrNegativeDuration, "can not perform integration on a negative duration")
+	}
+
+	start, force := p.Position, p.forceAccumulator
+	p.integrate(duration)
+	p.recordStep(force, start, duration)
+
+	// Clear the accumulated force after applying it to the particle. The impulse it delivered is
+	// kept until ClearForces.
+	p.forceAccumulator = math64.Vector3{}
+
+	p.trail.record(p.Position)
+
+	p.checkInvariants()
+
+	return nil
+}
+
+// IntegrateSubstepped integrates the particle over duration in substeps equal steps, rather than
+// one. Smaller steps keep the explicit integrator stable in stiff scenes, such as strong springs,
+// without lowering the frame rate. A substeps of one or less behaves exactly like Integrate.
+//
+// The accumulated force is applied in every substep and cleared at the end, so forces are held
+// constant over the frame. The trail records only the final position.
+func (p *Particle) IntegrateSubstepped(duration float64, substeps int) error {
+	if substeps <= 1 {
+		return p.Integrate(duration)
 	}
+
+	switch {
+	case p.inverseMass <= 0.0:
+		return newPhysicsError(ErrInfiniteMass, "integration is not performed on infinite mass")
+	case duration <= 0.0:
+		return newPhysicsError(ErrNegativeDuration, "can not perform integration on a negative duration")
+	}
+
+	start, force := p.Position, p.forceAccumulator
+	step := duration / float64(substeps)
+	for i := 0; i < substeps; i++ {
+		p.integrate(step)
+	}
+	p.recordStep(force, start, duration)
+
+	p.forceAccumulator = math64.Vector3{}
+
+	p.trail.record(p.Position)
+
+	p.checkInvariants()
+
+	return nil
+}
+
+// IntegrateVelocityVerlet integrates the particle over duration with the velocity-Verlet method,
+// which conserves energy far better than Integrate for position-dependent forces such as springs:
+//
+//	x' = x + v*dt + a*dt^2/2
+//	v' = v + (a + a')*dt/2
+//
+// where a is the acceleration at x and a' the acceleration at x'. Every enabled generator registered
+// against the particle in reg is run twice per step, once at each position, so reg must not also be
+// updated for this particle this frame. Stateful generators (see Stateful) are the exception: they
+// must not run twice, so they are run once, at x, and their force is held constant over the step,
+// as is any force already accumulated on the particle. Velocity-dependent forces, like drag, see
+// the velocity at the start of the step. reg may be nil to integrate under the accumulated force
+// alone.
+func (p *Particle) IntegrateVelocityVerlet(duration float64, reg *ForceRegistry) error {
+	switch {
+	case p.inverseMass <= 0.0:
+		return newPhysicsError(ErrInfiniteMass, "integration is not performed on infinite mass")
+	case duration <= 0.0:
+		return newPhysicsError(ErrNegativeDuration, "can not perform integration on a negative duration")
+	}
+
+	var generators []ForceGenerator
+	if reg != nil {
+		generators = reg.GeneratorsFor(p)
+	}
+
+	// Run the stateful generators now, so their force joins the constant part of the force, and
+	// keep the rest to evaluate at either end of the step.
+	stateless := generators[:0]
+	for _, fg := range generators {
+		switch {
+		case !isEnabled(fg):
+		case isStateful(fg):
+			fg.UpdateForce(p, duration)
+		default:
+			stateless = append(stateless, fg)
+		}
+	}
+
+	constant := p.forceAccumulator
+	acceleration := func() math64.Vector3 {
+		p.forceAccumulator = constant
+		for _, fg := range stateless {
+			fg.UpdateForce(p, duration)
+		}
+		return p.Acceleration.ScaleAddCopy(p.forceAccumulator, p.inverseMass)
+	}
+
+	// Move with the current velocity and half the current acceleration.
+	start := p.Position
+	a0 := acceleration()
+	f0 := p.forceAccumulator
+	p.Position.ScaleAdd(p.Velocity, duration)
+	p.Position.ScaleAdd(a0, duration*duration/2)
+
+	// Complete the velocity update with the average of the old and new accelerations.
+	a1 := acceleration()
+	p.Velocity.ScaleAdd(a0.AddCopy(a1), duration/2)
+
+	// The force over the step is taken as the average of the forces at either end.
+	averageForce := f0.AddCopy(p.forceAccumulator)
+	averageForce.Scale(0.5)
+	p.recordStep(averageForce, start, duration)
+
+	p.applyDamping(duration)
+
+	p.forceAccumulator = math64.Vector3{}
+
+	p.age += duration
+
+	p.trail.record(p.Position)
+
+	p.checkInvariants()
+
+	return nil
+}
+
+// recordStep records the work done by force, and the distance moved, over a step of duration that
+// started at start, and adds the impulse force delivered over it to the accumulated impulse.
+func (p *Particle) recordStep(force, start math64.Vector3, duration float64) {
+	p.impulse.ScaleAdd(force, duration)
+	displacement := p.Position.SubCopy(start)
+	p.stepWork = WorkDone(force, displacement)
+	p.stepDistance = displacement.Magnitude()
+}
+
+// integrate advances the particle's position, velocity and age by duration, without checking
+// its arguments or clearing the accumulated force.
+func (p *Particle) integrate(duration float64) {
 	// NOTE: I am using pointer methods for Vector operations; copying will result
 	// in thousands of vectors not used due to how often this function will be called.
 
@@ -138,60 +514,54 @@ func (p *Particle) Integrate(duration float64) error {
 	// Update linear velocity from the acceleration.
 	p.Velocity.ScaleAdd(resultingAcceleration, duration)
 
-	// Impose drag. Match time scales by exponentiating time by drag, counteracting the effects
-	// of the linearity of acceleration integration.
-	dampingFactor := math.Pow(p.Damping, duration)
-	p.Velocity.Scale(dampingFactor)
-
-	// Clear the accumulated force after applying it to the particle.
-	p.ClearForces()
+	p.applyDamping(duration)
 
-	return nil
+	p.age += duration
 }
 
-// PhysicsError represents specific errors relevant to our physics engine.
-type PhysicsError struct {
-	Message string
-}
+// applyDamping imposes the particle's damping on its velocity over duration.
+func (p *Particle) applyDamping(duration float64) {
+	// Impose drag. Match time scales by exponentiating time by drag, counteracting the effects
+	// of the linearity of acceleration integration.
+	if p.DampingAxes.IsZero() {
+		dampingFactor := math.Pow(p.Damping, duration)
+		p.Velocity.Scale(dampingFactor)
+		return
+	}
 
-// Error is used to implement the Error interface.
-func (e *PhysicsError) Error() string {
-	return e.Message
+	p.Velocity.X *= math.Pow(p.DampingAxes.X, duration)
+	p.Velocity.Y *= math.Pow(p.DampingAxes.Y, duration)
+	p.Velocity.Z *= math.Pow(p.DampingAxes.Z, duration)
 }
 
-// newPhysicsError creates a physics error object and logs it using PhysicsLogger.
-func newPhysicsError(message string) error {
-	err := &PhysicsError{
-		Message: message,
+// Step is a pure counterpart to Integrate. It returns a copy of p integrated forward by duration,
+// leaving p itself untouched. On error, it returns p unchanged alongside the error.
+//
+// Integrate remains the faster choice when the particle can be modified in place.
+func Step(p Particle, duration float64) (Particle, error) {
+	next := p
+	next.trail = p.trail.clone() // The trail's buffer would otherwise be shared with p.
+	if err := next.Integrate(duration); err != nil {
+		return p, err
 	}
-
-	logger := physicslog.NewPhysicsLogger(physicslog.LevelError)
-	logger.LogError(message)
-
-	return err
+	return next, nil
 }
 
-// ErrorCode represents different specific error codes the particle can throw out.
-/*
-type ErrorCode int
-
-// FIXME: Look into alternate ways to better supply all the errors in the engine.
-const (
-	ErrInfiniteMass ErrorCode = iota
-	ErrNegativeDuration
-)
+// RelativeVelocity returns the velocity of a as seen from b, a.Velocity - b.Velocity.
+func RelativeVelocity(a, b *Particle) math64.Vector3 {
+	return a.Velocity.SubCopy(b.Velocity)
+}
 
-func (e ErrorCode) String() string {
-	switch e {
-	case ErrInfiniteMass:
-		return "ErrInfiniteMass:"
-	case ErrNegativeDuration:
-		return "ErrNegativeDuration:"
-	default:
-		return ""
+// ClosingSpeed returns how fast a and b are approaching each other along the line between them.
+// It is positive when they are closing, negative when they are separating, and zero when they
+// move parallel to each other or sit at the same position.
+func ClosingSpeed(a, b *Particle) float64 {
+	towardB, err := b.Position.SubCopy(a.Position).NormalizeChecked()
+	if err != nil {
+		return 0
 	}
+	return RelativeVelocity(a, b).Dot(towardB)
 }
-*/
 
 /*
 // Deprecated: Only use Integrate() to perform integration. This should only ever be used
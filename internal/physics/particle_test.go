@@ -0,0 +1,1097 @@
+package physics
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+// newTestParticle returns an undamped particle of the given mass, where zero or less is infinite.
+func newTestParticle(position, velocity math64.Vector3, mass float64) *Particle {
+	p := NewParticleMass(position, velocity, math64.Vector3{}, 1, mass)
+	return &p
+}
+
+func TestParticleIntegrate(t *testing.T) {
+	tests := []struct {
+		name         string
+		velocity     math64.Vector3
+		acceleration math64.Vector3
+		force        math64.Vector3
+		mass         float64
+		damping      float64
+		duration     float64
+		wantPosition math64.Vector3
+		wantVelocity math64.Vector3
+	}{
+		{
+			name:         "constant velocity",
+			velocity:     math64.NewVector3(1, 2, 3),
+			mass:         1,
+			damping:      1,
+			duration:     0.5,
+			wantPosition: math64.NewVector3(0.5, 1, 1.5),
+			wantVelocity: math64.NewVector3(1, 2, 3),
+		},
+		{
+			// Position moves with the old velocity, then the velocity picks up the acceleration.
+			name:         "gravity",
+			acceleration: math64.NewVector3(0, -10, 0),
+			mass:         1,
+			damping:      1,
+			duration:     0.1,
+			wantPosition: math64.Vector3{},
+			wantVelocity: math64.NewVector3(0, -1, 0),
+		},
+		{
+			name:         "force scaled by inverse mass",
+			force:        math64.NewVector3(4, 0, 0),
+			mass:         2,
+			damping:      1,
+			duration:     0.5,
+			wantVelocity: math64.NewVector3(1, 0, 0),
+		},
+		{
+			name:         "damping over one second",
+			velocity:     math64.NewVector3(10, 0, 0),
+			mass:         1,
+			damping:      0.5,
+			duration:     1,
+			wantPosition: math64.NewVector3(10, 0, 0),
+			wantVelocity: math64.NewVector3(5, 0, 0),
+		},
+		{
+			name:         "damping over a quarter second",
+			velocity:     math64.NewVector3(16, 0, 0),
+			mass:         1,
+			damping:      0.0625,
+			duration:     0.25,
+			wantPosition: math64.NewVector3(4, 0, 0),
+			wantVelocity: math64.NewVector3(8, 0, 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParticleMass(math64.Vector3{}, tt.velocity, tt.acceleration, tt.damping, tt.mass)
+			p.AddForce(tt.force)
+
+			if err := p.Integrate(tt.duration); err != nil {
+				t.Fatalf("Integrate(%v) = %v", tt.duration, err)
+			}
+			if !p.Position.ApproxEqual(tt.wantPosition, 1e-12) {
+				t.Errorf("position = %v, want %v", p.Position, tt.wantPosition)
+			}
+			if !p.Velocity.ApproxEqual(tt.wantVelocity, 1e-12) {
+				t.Errorf("velocity = %v, want %v", p.Velocity, tt.wantVelocity)
+			}
+			if !p.forceAccumulator.IsZero() {
+				t.Errorf("force accumulator = %v after integrating, want zero", p.forceAccumulator)
+			}
+		})
+	}
+}
+
+// TestParticleDampingFrameRateIndependent checks that damping removes the same share of velocity
+// over a second whatever the step size.
+func TestParticleDampingFrameRateIndependent(t *testing.T) {
+	for _, steps := range []int{1, 10, 60, 240} {
+		p := NewParticleMass(math64.Vector3{}, math64.NewVector3(1, 0, 0), math64.Vector3{}, 0.8, 1)
+		for i := 0; i < steps; i++ {
+			if err := p.Integrate(1 / float64(steps)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if !math64.ApproxEqual(p.Velocity.X, 0.8, 1e-12) {
+			t.Errorf("%d steps: velocity %v after one second, want 0.8", steps, p.Velocity.X)
+		}
+	}
+}
+
+func TestParticleIntegrateInfiniteMassUnchanged(t *testing.T) {
+	p := newTestParticle(math64.NewVector3(1, 2, 3), math64.NewVector3(4, 5, 6), 0)
+	before := *p
+	if err := p.Integrate(0.1); err == nil {
+		t.Fatal("Integrate on an infinite mass returned no error")
+	}
+	if p.Position != before.Position || p.Velocity != before.Velocity {
+		t.Errorf("infinite-mass particle changed from %v/%v to %v/%v", before.Position, before.Velocity, p.Position, p.Velocity)
+	}
+	if !math.IsInf(p.Mass(), 1) {
+		t.Errorf("Mass() = %v, want +Inf", p.Mass())
+	}
+}
+
+// dampingOver returns the share of velocity a particle with the given damping keeps over duration.
+func dampingOver(damping, duration float64) float64 {
+	return math.Pow(damping, duration)
+}
+
+func TestBounceOffPlane(t *testing.T) {
+	tests := []struct {
+		name         string
+		position     math64.Vector3
+		velocity     math64.Vector3
+		restitution  float64
+		wantBounce   bool
+		wantPosition math64.Vector3
+		wantVelocity math64.Vector3
+	}{
+		{
+			name:         "falling through",
+			position:     math64.NewVector3(1, -0.2, 3),
+			velocity:     math64.NewVector3(2, -10, 0),
+			restitution:  0.5,
+			wantBounce:   true,
+			wantPosition: math64.NewVector3(1, 0, 3),
+			wantVelocity: math64.NewVector3(2, 5, 0),
+		},
+		{
+			name:         "dead stop",
+			position:     math64.NewVector3(0, -1, 0),
+			velocity:     math64.NewVector3(0, -4, 0),
+			restitution:  0,
+			wantBounce:   true,
+			wantPosition: math64.Vector3{},
+			wantVelocity: math64.Vector3{},
+		},
+		{
+			name:         "above the plane",
+			position:     math64.NewVector3(0, 2, 0),
+			velocity:     math64.NewVector3(0, -10, 0),
+			restitution:  0.5,
+			wantPosition: math64.NewVector3(0, 2, 0),
+			wantVelocity: math64.NewVector3(0, -10, 0),
+		},
+		{
+			name:         "below but rising",
+			position:     math64.NewVector3(0, -1, 0),
+			velocity:     math64.NewVector3(0, 3, 0),
+			restitution:  0.5,
+			wantPosition: math64.NewVector3(0, -1, 0),
+			wantVelocity: math64.NewVector3(0, 3, 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(tt.position, tt.velocity, 1)
+			if got := p.BounceOffPlane(0, tt.restitution); got != tt.wantBounce {
+				t.Errorf("BounceOffPlane() = %v, want %v", got, tt.wantBounce)
+			}
+			if p.Position != tt.wantPosition || p.Velocity != tt.wantVelocity {
+				t.Errorf("after bounce: position %v, velocity %v; want %v, %v", p.Position, p.Velocity, tt.wantPosition, tt.wantVelocity)
+			}
+		})
+	}
+}
+
+func TestParticleExpired(t *testing.T) {
+	// Steps of 0.125s are exact in binary, so the age lands exactly on the lifetime.
+	const step = 0.125
+	tests := []struct {
+		name     string
+		lifetime float64
+		steps    int
+		want     bool
+	}{
+		{"before its lifetime", 2, 15, false},
+		{"exactly its lifetime", 2, 16, false},
+		{"past its lifetime", 2, 17, true},
+		{"immortal", 0, 1000, false},
+		{"negative lifetime is immortal", -1, 1000, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(math64.Vector3{}, math64.Vector3{}, 1)
+			p.Lifetime = tt.lifetime
+			for i := 0; i < tt.steps; i++ {
+				if err := p.Integrate(step); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := p.Age(); got != step*float64(tt.steps) {
+				t.Errorf("Age() = %v, want %v", got, step*float64(tt.steps))
+			}
+			if got := p.Expired(); got != tt.want {
+				t.Errorf("Expired() = %v at age %v with lifetime %v, want %v", got, p.Age(), tt.lifetime, tt.want)
+			}
+		})
+	}
+}
+
+// newBenchParticle returns a finite-mass particle in motion, so no generator or integration step
+// takes an early return.
+func newBenchParticle() Particle {
+	return NewParticleMass(math64.NewVector3(1, 2, 3), math64.NewVector3(4, 5, 6), math64.NewVector3(0, -9.81, 0), 0.99, 2)
+}
+
+func BenchmarkIntegrate(b *testing.B) {
+	p := newBenchParticle()
+	for i := 0; i < b.N; i++ {
+		p.Integrate(0.016)
+	}
+}
+
+func TestStep(t *testing.T) {
+	tests := []struct {
+		name     string
+		particle Particle
+		force    math64.Vector3
+		duration float64
+		wantErr  bool
+	}{
+		{"ballistic", NewParticleMass(math64.NewVector3(0, 1.5, 0), math64.NewVector3(0, 0, 35), math64.NewVector3(0, -1, 0), 0.99, 2), math64.Vector3{}, 0.016, false},
+		{"with force", NewParticleMass(math64.NewVector3(1, 2, 3), math64.NewVector3(-1, 0, 1), math64.Vector3{}, 0.5, 4), math64.NewVector3(0, 8, 0), 0.1, false},
+		{"invalid duration", NewParticleMass(math64.NewVector3(1, 2, 3), math64.NewVector3(-1, 0, 1), math64.Vector3{}, 0.5, 4), math64.Vector3{}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := tt.particle
+			input.SetTrailLength(4)
+			input.AddForce(tt.force)
+
+			want := input
+			want.SetTrailLength(4)
+			wantErr := want.Integrate(tt.duration)
+
+			got, err := Step(input, tt.duration)
+			if (err != nil) != tt.wantErr || (wantErr != nil) != tt.wantErr {
+				t.Fatalf("Step error = %v, Integrate error = %v, want error %v", err, wantErr, tt.wantErr)
+			}
+			if got.Position != want.Position || got.Velocity != want.Velocity || got.Age() != want.Age() {
+				t.Errorf("Step = position %v velocity %v age %v, Integrate = %v %v %v",
+					got.Position, got.Velocity, got.Age(), want.Position, want.Velocity, want.Age())
+			}
+
+			if input.Position != tt.particle.Position || input.Velocity != tt.particle.Velocity || input.Age() != 0 ||
+				input.forceAccumulator != tt.force || len(input.Trail()) != 0 {
+				t.Errorf("Step changed its input particle")
+			}
+		})
+	}
+}
+
+func TestParticleTrail(t *testing.T) {
+	// The particle moves 1 along X per step, so the position after step i is (i, 0, 0).
+	positions := func(from, to int) []math64.Vector3 {
+		var out []math64.Vector3
+		for i := from; i <= to; i++ {
+			out = append(out, math64.NewVector3(float64(i), 0, 0))
+		}
+		return out
+	}
+	tests := []struct {
+		name   string
+		length int
+		steps  int
+		want   []math64.Vector3
+	}{
+		{"disabled", 0, 5, nil},
+		{"negative length disables", -3, 5, nil},
+		{"not yet full", 4, 2, positions(1, 2)},
+		{"exactly full", 4, 4, positions(1, 4)},
+		{"capped to the most recent", 4, 10, positions(7, 10)},
+		{"length one", 1, 3, positions(3, 3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(math64.Vector3{}, math64.NewVector3(1, 0, 0), 1)
+			p.SetTrailLength(tt.length)
+			for i := 0; i < tt.steps; i++ {
+				if err := p.Integrate(1); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got := p.Trail()
+			if (got == nil) != (tt.want == nil) || len(got) != len(tt.want) {
+				t.Fatalf("Trail() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Trail() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestParticleTrailReset(t *testing.T) {
+	p := newTestParticle(math64.Vector3{}, math64.NewVector3(1, 0, 0), 1)
+	p.SetTrailLength(3)
+	for i := 0; i < 3; i++ {
+		p.Integrate(1)
+	}
+	p.SetTrailLength(5)
+	if got := p.Trail(); len(got) != 0 {
+		t.Errorf("Trail() after changing the length = %v, want it cleared", got)
+	}
+}
+
+func TestParticleRollAngle(t *testing.T) {
+	tests := []struct {
+		name     string
+		velocity math64.Vector3
+		radius   float64
+		duration float64
+		want     float64
+	}{
+		{"stationary", math64.Vector3{}, 0.5, 1, 0},
+		{"slow", math64.NewVector3(1, 0, 0), 0.5, 1, 2},
+		{"fast", math64.NewVector3(4, 0, 0), 0.5, 1, 8},
+		{"diagonal", math64.NewVector3(3, 0, -4), 1, 0.5, 2.5},
+		{"vertical motion doesn't roll", math64.NewVector3(0, -9, 0), 0.5, 1, 0},
+		{"zero radius", math64.NewVector3(1, 0, 0), 0, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(math64.Vector3{}, tt.velocity, 1)
+			if got := p.RollAngle(tt.radius, tt.duration); !math64.ApproxEqual(got, tt.want, 1e-12) {
+				t.Errorf("RollAngle(%v, %v) = %v, want %v", tt.radius, tt.duration, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParticleSetDamping(t *testing.T) {
+	tests := []struct {
+		name    string
+		damping float64
+		want    float64
+		wantErr bool
+	}{
+		{"valid", 0.5, 0.5, false},
+		{"no damping", 1, 1, false},
+		{"clamped", 1.5, 1, false},
+		{"zero", 0, 0.8, true},
+		{"negative", -0.5, 0.8, true},
+		{"NaN", math.NaN(), 0.8, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParticleMass(math64.Vector3{}, math64.NewVector3(1, 0, 0), math64.Vector3{}, 0.8, 1)
+			err := p.SetDamping(tt.damping)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetDamping(%v) error = %v, want error %v", tt.damping, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidDamping) {
+				t.Errorf("SetDamping(%v) error = %v, want ErrInvalidDamping", tt.damping, err)
+			}
+			if p.Damping != tt.want {
+				t.Errorf("Damping = %v, want %v", p.Damping, tt.want)
+			}
+
+			// Whatever happened, integrating a fractional step must keep the velocity finite.
+			if err := p.Integrate(0.016); err != nil {
+				t.Fatal(err)
+			}
+			if !p.Velocity.IsFinite() {
+				t.Errorf("velocity after integrating = %v, want finite", p.Velocity)
+			}
+		})
+	}
+}
+
+func TestParticleConstructorDamping(t *testing.T) {
+	tests := []struct {
+		name    string
+		damping float64
+		want    float64
+	}{
+		{"valid", 0.9, 0.9},
+		{"clamped", 2, 1},
+		{"zero falls back to the default", 0, DefaultDamping},
+		{"negative falls back to the default", -1, DefaultDamping},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			byMass := NewParticleMass(math64.Vector3{}, math64.Vector3{}, math64.Vector3{}, tt.damping, 2)
+			byInverseMass := NewParticleInverseMass(math64.Vector3{}, math64.Vector3{}, math64.Vector3{}, tt.damping, 0.5)
+			if byMass.Damping != tt.want || byInverseMass.Damping != tt.want {
+				t.Errorf("constructors gave damping %v and %v, want %v", byMass.Damping, byInverseMass.Damping, tt.want)
+			}
+		})
+	}
+}
+
+func TestParticleImpulseTo(t *testing.T) {
+	tests := []struct {
+		name        string
+		velocity    math64.Vector3
+		target      math64.Vector3
+		mass        float64
+		wantImpulse math64.Vector3
+	}{
+		{"from rest", math64.Vector3{}, math64.NewVector3(0, 5, 0), 2, math64.NewVector3(0, 10, 0)},
+		{"reverse", math64.NewVector3(3, 0, -1), math64.NewVector3(-3, 0, 1), 0.5, math64.NewVector3(-3, 0, 1)},
+		{"already there", math64.NewVector3(1, 2, 3), math64.NewVector3(1, 2, 3), 4, math64.Vector3{}},
+		{"infinite mass", math64.NewVector3(1, 0, 0), math64.NewVector3(0, 9, 0), 0, math64.Vector3{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(math64.Vector3{}, tt.velocity, tt.mass)
+			impulse := p.ImpulseTo(tt.target)
+			if !impulse.ApproxEqual(tt.wantImpulse, 1e-12) {
+				t.Errorf("ImpulseTo(%v) = %v, want %v", tt.target, impulse, tt.wantImpulse)
+			}
+
+			p.ApplyImpulse(impulse)
+			want := tt.target
+			if !p.HasFiniteMass() {
+				want = tt.velocity // Nothing moves an infinite mass.
+			}
+			if !p.Velocity.ApproxEqual(want, 1e-12) {
+				t.Errorf("velocity after ApplyImpulse = %v, want %v", p.Velocity, want)
+			}
+		})
+	}
+}
+
+func TestParticleApplyImpulseInfiniteMass(t *testing.T) {
+	p := newTestParticle(math64.Vector3{}, math64.NewVector3(1, 2, 3), 0)
+	p.ApplyImpulse(math64.NewVector3(100, -100, 100))
+	if p.Velocity != math64.NewVector3(1, 2, 3) {
+		t.Errorf("impulse changed an infinite-mass particle's velocity to %v", p.Velocity)
+	}
+}
+
+func TestParticleIntegrateSubsteppedSpring(t *testing.T) {
+	// A 1 kg particle on a spring of stiffness 100 to the origin, released from rest at x = 1,
+	// oscillates as x(t) = cos(10t).
+	const (
+		stiffness = 100.0
+		frame     = 0.05
+		frames    = 20
+	)
+	simulate := func(substeps int) float64 {
+		anchor := newTestParticle(math64.Vector3{}, math64.Vector3{}, 0)
+		p := newTestParticle(math64.NewVector3(1, 0, 0), math64.Vector3{}, 1)
+		spring := NewSpringForceGenerator(anchor, stiffness, 0)
+		worst := 0.0
+		for i := 1; i <= frames; i++ {
+			spring.UpdateForce(p, frame)
+			if err := p.IntegrateSubstepped(frame, substeps); err != nil {
+				t.Fatal(err)
+			}
+			want := math.Cos(math.Sqrt(stiffness) * frame * float64(i))
+			worst = math.Max(worst, math.Abs(p.Position.X-want))
+		}
+		return worst
+	}
+
+	single, substepped := simulate(1), simulate(8)
+	if substepped >= single {
+		t.Errorf("worst error with 8 substeps = %v, want less than the %v of a single step", substepped, single)
+	}
+}
+
+func TestParticleIntegrateSubsteppedSingleStep(t *testing.T) {
+	for _, substeps := range []int{-1, 0, 1} {
+		want := newTestParticle(math64.NewVector3(1, 2, 3), math64.NewVector3(4, 5, 6), 2)
+		got := newTestParticle(math64.NewVector3(1, 2, 3), math64.NewVector3(4, 5, 6), 2)
+		want.AddForce(math64.NewVector3(0, 10, 0))
+		got.AddForce(math64.NewVector3(0, 10, 0))
+
+		want.Integrate(0.1)
+		if err := got.IntegrateSubstepped(0.1, substeps); err != nil {
+			t.Fatal(err)
+		}
+		if got.Position != want.Position || got.Velocity != want.Velocity {
+			t.Errorf("IntegrateSubstepped with %d substeps = %v, %v, want Integrate's %v, %v",
+				substeps, got.Position, got.Velocity, want.Position, want.Velocity)
+		}
+	}
+}
+
+func TestParticleIntegrateSubsteppedErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		mass     float64
+		duration float64
+		want     ErrorCode
+	}{
+		{"infinite mass", 0, 0.1, ErrInfiniteMass},
+		{"zero duration", 1, 0, ErrNegativeDuration},
+		{"negative duration", 1, -0.1, ErrNegativeDuration},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(math64.Vector3{}, math64.Vector3{}, tt.mass)
+			if err := p.IntegrateSubstepped(tt.duration, 4); !errors.Is(err, tt.want) {
+				t.Errorf("IntegrateSubstepped error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParticleAtRest(t *testing.T) {
+	tests := []struct {
+		name     string
+		velocity math64.Vector3
+		want     bool
+	}{
+		{"stationary", math64.Vector3{}, true},
+		{"slow", math64.NewVector3(3, 0, -3.9), true},
+		{"exactly the threshold", math64.NewVector3(0, 3, -4), false},
+		{"fast", math64.NewVector3(50, 0, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(math64.Vector3{}, tt.velocity, 1)
+			if got := p.AtRest(5); got != tt.want {
+				t.Errorf("AtRest(5) at speed %v = %v, want %v", tt.velocity.Magnitude(), got, tt.want)
+			}
+		})
+	}
+}
+
+// angleBetween returns the angle, in radians, between a and b.
+func angleBetween(a, b math64.Vector3) float64 {
+	return math.Acos(math.Max(-1, math.Min(1, a.Normalize().Dot(b.Normalize()))))
+}
+
+func TestParticleSteerTowardCurves(t *testing.T) {
+	const (
+		turnRate = 1.0 // Radians per second.
+		dt       = 0.1
+	)
+	target := math64.NewVector3(0, 0, 50)
+	p := newTestParticle(math64.Vector3{}, math64.NewVector3(10, 0, 0), 1)
+
+	previous := angleBetween(p.Velocity, target.SubCopy(p.Position))
+	for i := 0; i < 30; i++ {
+		before := p.Velocity
+		p.SteerToward(target, turnRate, dt)
+
+		if turned := angleBetween(before, p.Velocity); turned > turnRate*dt+1e-9 {
+			t.Fatalf("step %d turned %v radians, more than the maximum %v", i, turned, turnRate*dt)
+		}
+		if !math64.ApproxEqual(p.Velocity.Magnitude(), 10, 1e-9) {
+			t.Fatalf("step %d changed the speed to %v", i, p.Velocity.Magnitude())
+		}
+
+		if err := p.Integrate(dt); err != nil {
+			t.Fatal(err)
+		}
+		off := angleBetween(p.Velocity, target.SubCopy(p.Position))
+		if off > previous+1e-9 {
+			t.Fatalf("step %d: heading is %v radians off target, up from %v", i, off, previous)
+		}
+		previous = off
+	}
+	if previous > 1e-6 {
+		t.Errorf("after 3 seconds the heading is still %v radians off target", previous)
+	}
+}
+
+func TestParticleSteerToward(t *testing.T) {
+	tests := []struct {
+		name     string
+		position math64.Vector3
+		velocity math64.Vector3
+		target   math64.Vector3
+		turnRate float64
+		want     math64.Vector3
+	}{
+		{"aligns within one step", math64.Vector3{}, math64.NewVector3(3, 0, 0), math64.NewVector3(0, 7, 0), 100, math64.NewVector3(0, 3, 0)},
+		{"limited turn", math64.Vector3{}, math64.NewVector3(2, 0, 0), math64.NewVector3(0, 0, -5), math64.Pi / 4, math64.NewVector3(math.Sqrt2, 0, -math.Sqrt2)},
+		{"stationary", math64.Vector3{}, math64.Vector3{}, math64.NewVector3(1, 0, 0), 10, math64.Vector3{}},
+		{"on the target", math64.NewVector3(1, 1, 1), math64.NewVector3(0, 1, 0), math64.NewVector3(1, 1, 1), 10, math64.NewVector3(0, 1, 0)},
+		{"no turn rate", math64.Vector3{}, math64.NewVector3(1, 0, 0), math64.NewVector3(0, 1, 0), 0, math64.NewVector3(1, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(tt.position, tt.velocity, 1)
+			p.SteerToward(tt.target, tt.turnRate, 1)
+			if !p.Velocity.ApproxEqual(tt.want, 1e-9) {
+				t.Errorf("velocity = %v, want %v", p.Velocity, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntegrateVelocityVerletConservesEnergy(t *testing.T) {
+	const stiffness = 10.0
+
+	// maxEnergyError integrates a unit mass on a frictionless spring to a fixed anchor for the given
+	// time, and returns the largest relative error in its total energy along the way.
+	maxEnergyError := func(t *testing.T, step func(p *Particle, reg *ForceRegistry, dt float64) error, dt, seconds float64) float64 {
+		t.Helper()
+		anchor := newTestParticle(math64.Vector3{}, math64.Vector3{}, 0)
+		p := newTestParticle(math64.NewVector3(1, 0, 0), math64.Vector3{}, 1)
+		var reg ForceRegistry
+		reg.AddForce(p, NewSpringForceGenerator(anchor, stiffness, 0))
+
+		energy := func() float64 {
+			return 0.5*p.Velocity.Dot(p.Velocity) + 0.5*stiffness*p.Position.Dot(p.Position)
+		}
+		initial := energy()
+		worst := 0.0
+		for i := 0; i < int(seconds/dt); i++ {
+			if err := step(p, &reg, dt); err != nil {
+				t.Fatal(err)
+			}
+			worst = max(worst, math.Abs(energy()-initial)/initial)
+		}
+		return worst
+	}
+	euler := func(p *Particle, reg *ForceRegistry, dt float64) error {
+		reg.UpdateForces(dt)
+		return p.Integrate(dt)
+	}
+	verlet := func(p *Particle, reg *ForceRegistry, dt float64) error {
+		return p.IntegrateVelocityVerlet(dt, reg)
+	}
+
+	for _, dt := range []float64{0.001, 0.01, 0.05} {
+		t.Run(strconv.FormatFloat(dt, 'g', -1, 64), func(t *testing.T) {
+			eulerError := maxEnergyError(t, euler, dt, 100)
+			verletError := maxEnergyError(t, verlet, dt, 100)
+
+			if verletError >= eulerError/10 {
+				t.Errorf("velocity-Verlet energy error %v, want well under Euler's %v", verletError, eulerError)
+			}
+			if verletError > 0.01 {
+				t.Errorf("velocity-Verlet energy error %v, want at most 1%%", verletError)
+			}
+		})
+	}
+}
+
+func TestIntegrateVelocityVerletRunsStatefulGeneratorsOnce(t *testing.T) {
+	tests := []struct {
+		name string
+		fg   ForceGenerator
+	}{
+		{"direct", NewImpulseForceGenerator(math64.NewVector3(2, 0, 0))},
+		{"wrapped", NewScaledForceGenerator(NewImpulseForceGenerator(math64.NewVector3(4, 0, 0)), 0.5)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(math64.Vector3{}, math64.Vector3{}, 2)
+			var reg ForceRegistry
+			reg.AddForce(p, tt.fg)
+
+			if err := p.IntegrateVelocityVerlet(0.1, &reg); err != nil {
+				t.Fatal(err)
+			}
+
+			// An impulse of 2 N*s on 2 kg changes the velocity by 1 m/s, but only if its force
+			// acts for the whole step rather than just the first half of it.
+			if want := math64.NewVector3(1, 0, 0); !p.Velocity.ApproxEqual(want, 1e-12) {
+				t.Errorf("velocity = %v, want %v", p.Velocity, want)
+			}
+		})
+	}
+}
+
+func TestParticleAccumulatedImpulse(t *testing.T) {
+	tests := []struct {
+		name     string
+		forces   []math64.Vector3
+		duration float64
+		want     math64.Vector3
+	}{
+		{"no forces", nil, 0.5, math64.Vector3{}},
+		{"one force", []math64.Vector3{math64.NewVector3(2, 0, 0)}, 0.5, math64.NewVector3(1, 0, 0)},
+		{"forces sum", []math64.Vector3{math64.NewVector3(2, 0, 0), math64.NewVector3(0, 4, -2)}, 0.5, math64.NewVector3(1, 2, -1)},
+		{"opposing forces cancel", []math64.Vector3{math64.NewVector3(3, 0, 0), math64.NewVector3(-3, 0, 0)}, 0.5, math64.Vector3{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(math64.Vector3{}, math64.Vector3{}, 2)
+			for _, f := range tt.forces {
+				p.AddForce(f)
+			}
+			if err := p.Integrate(tt.duration); err != nil {
+				t.Fatal(err)
+			}
+
+			// The impulse is read after integrating, and is exactly what changed the velocity.
+			got := p.AccumulatedImpulse()
+			if !got.ApproxEqual(tt.want, 1e-12) {
+				t.Errorf("AccumulatedImpulse() = %v, want %v", got, tt.want)
+			}
+			if want := tt.want.ScaleCopy(0.5); !p.Velocity.ApproxEqual(want, 1e-12) {
+				t.Errorf("velocity after integrating = %v, want %v", p.Velocity, want)
+			}
+
+			// A second step in the same frame adds nothing, as its force was used up by the first.
+			if err := p.Integrate(tt.duration); err != nil {
+				t.Fatal(err)
+			}
+			if got := p.AccumulatedImpulse(); !got.ApproxEqual(tt.want, 1e-12) {
+				t.Errorf("AccumulatedImpulse() after a second step = %v, want %v", got, tt.want)
+			}
+
+			p.ClearForces()
+			if got := p.AccumulatedImpulse(); !got.IsZero() {
+				t.Errorf("AccumulatedImpulse() after ClearForces = %v, want zero", got)
+			}
+		})
+	}
+}
+
+func TestParticleAccumulatedImpulseSumsSteps(t *testing.T) {
+	force := math64.NewVector3(0, 6, 0)
+	tests := []struct {
+		name      string
+		integrate func(p *Particle) error
+	}{
+		{"two steps", func(p *Particle) error {
+			for i := 0; i < 2; i++ {
+				p.AddForce(force)
+				if err := p.Integrate(0.25); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+		{"substepped", func(p *Particle) error {
+			p.AddForce(force)
+			return p.IntegrateSubstepped(0.5, 4)
+		}},
+		{"velocity Verlet", func(p *Particle) error {
+			p.AddForce(force)
+			return p.IntegrateVelocityVerlet(0.5, nil)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(math64.Vector3{}, math64.Vector3{}, 2)
+			if err := tt.integrate(p); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := p.AccumulatedImpulse(), force.ScaleCopy(0.5); !got.ApproxEqual(want, 1e-12) {
+				t.Errorf("AccumulatedImpulse() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestParticleWorldResetsAccumulatedImpulse(t *testing.T) {
+	w := NewParticleWorld(0, 0)
+	p := newTestParticle(math64.Vector3{}, math64.Vector3{}, 1)
+	w.AddParticle(p)
+	w.Registry.AddForce(p, NewFuncForceGenerator(func(*Particle, float64) math64.Vector3 {
+		return math64.NewVector3(0, -10, 0)
+	}))
+
+	for frame := 0; frame < 3; frame++ {
+		w.StartFrame()
+		if got := p.AccumulatedImpulse(); !got.IsZero() {
+			t.Fatalf("frame %d: AccumulatedImpulse() after StartFrame = %v, want zero", frame, got)
+		}
+		if err := w.RunPhysics(0.1); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := p.AccumulatedImpulse(), math64.NewVector3(0, -1, 0); !got.ApproxEqual(want, 1e-12) {
+			t.Errorf("frame %d: AccumulatedImpulse() = %v, want %v", frame, got, want)
+		}
+	}
+}
+
+func TestParticleDampingAxes(t *testing.T) {
+	tests := []struct {
+		name    string
+		damping float64
+		axes    math64.Vector3
+		want    math64.Vector3
+	}{
+		{"unset uses scalar damping", 0.5, math64.Vector3{}, math64.NewVector3(2, 2, 2)},
+		{"more vertical damping", 0.5, math64.NewVector3(1, 0.25, 1), math64.NewVector3(4, 1, 4)},
+		{"replaces scalar damping", 0.25, math64.NewVector3(1, 1, 0.5), math64.NewVector3(4, 4, 2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(math64.Vector3{}, math64.NewVector3(4, 4, 4), 1)
+			p.Damping = tt.damping
+			p.DampingAxes = tt.axes
+
+			// Over one second each component keeps exactly its damping's proportion.
+			if err := p.Integrate(1); err != nil {
+				t.Fatal(err)
+			}
+
+			if !p.Velocity.ApproxEqual(tt.want, 1e-12) {
+				t.Errorf("velocity = %v, want %v", p.Velocity, tt.want)
+			}
+		})
+	}
+}
+
+func TestParticlePin(t *testing.T) {
+	tests := []struct {
+		name     string
+		mass     float64
+		pinTwice bool
+		setMass  float64 // If positive, set while pinned.
+		wantMass float64 // After Unpin.
+	}{
+		{"unpin restores mass", 2, false, 0, 2},
+		{"pinning twice keeps the first mass", 3, true, 0, 3},
+		{"setting the mass unpins", 2, false, 5, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(math64.NewVector3(1, 2, 3), math64.NewVector3(0, 1, 0), tt.mass)
+			p.Acceleration = math64.NewVector3(0, -9.81, 0)
+			start := p.Position
+
+			p.Pin()
+			if tt.pinTwice {
+				p.Pin()
+			}
+			if !p.Pinned() || p.HasFiniteMass() {
+				t.Fatalf("after Pin, Pinned() = %v and HasFiniteMass() = %v, want true and false", p.Pinned(), p.HasFiniteMass())
+			}
+
+			// A pinned particle doesn't move under force and integration.
+			p.AddForce(math64.NewVector3(100, 0, 0))
+			if err := p.Integrate(0.1); !errors.Is(err, ErrInfiniteMass) {
+				t.Errorf("Integrate on a pinned particle = %v, want ErrInfiniteMass", err)
+			}
+			if p.Position != start {
+				t.Errorf("pinned particle moved to %v from %v", p.Position, start)
+			}
+
+			if tt.setMass > 0 {
+				p.SetMass(tt.setMass)
+			}
+			p.Unpin()
+			if p.Pinned() || p.Mass() != tt.wantMass {
+				t.Errorf("after Unpin, Pinned() = %v and Mass() = %v, want false and %v", p.Pinned(), p.Mass(), tt.wantMass)
+			}
+
+			// Unpinned, it moves again.
+			p.ClearForces()
+			if err := p.Integrate(0.1); err != nil {
+				t.Fatal(err)
+			}
+			if p.Position == start {
+				t.Error("unpinned particle did not move")
+			}
+		})
+	}
+}
+
+func TestParticleSetMassWhilePinned(t *testing.T) {
+	tests := []struct {
+		name     string
+		set      func(p *Particle)
+		wantMass float64 // After Unpin, which must not restore the mass saved by Pin.
+	}{
+		{"SetMass then infinite SetMass", func(p *Particle) { p.SetMass(5); p.SetMass(0) }, math.Inf(1)},
+		{"infinite SetMass", func(p *Particle) { p.SetMass(0) }, math.Inf(1)},
+		{"SetInverseMass then infinite SetInverseMass", func(p *Particle) { p.SetInverseMass(0.2); p.SetInverseMass(0) }, math.Inf(1)},
+		{"finite SetInverseMass", func(p *Particle) { p.SetInverseMass(0.25) }, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(math64.Vector3{}, math64.Vector3{}, 2)
+			p.Pin()
+			tt.set(p)
+			if p.Pinned() {
+				t.Error("Pinned() = true after setting the mass, want false")
+			}
+
+			p.Unpin()
+			if p.Pinned() || p.Mass() != tt.wantMass {
+				t.Errorf("after Unpin, Pinned() = %v and Mass() = %v, want false and %v", p.Pinned(), p.Mass(), tt.wantMass)
+			}
+		})
+	}
+}
+
+func TestParticleUnpinWithoutPin(t *testing.T) {
+	tests := []struct {
+		name string
+		mass float64
+	}{
+		{"finite mass", 2},
+		{"infinite mass", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(math64.Vector3{}, math64.Vector3{}, tt.mass)
+			before := p.inverseMass
+			p.Unpin()
+			if p.Pinned() || p.inverseMass != before {
+				t.Errorf("Unpin on an unpinned particle changed inverse mass from %v to %v", before, p.inverseMass)
+			}
+		})
+	}
+}
+
+func TestRelativeVelocityClosingSpeed(t *testing.T) {
+	tests := []struct {
+		name         string
+		posA, velA   math64.Vector3
+		posB, velB   math64.Vector3
+		wantRelative math64.Vector3
+		wantClosing  float64
+	}{
+		{
+			"head-on",
+			math64.NewVector3(0, 0, 0), math64.NewVector3(3, 0, 0),
+			math64.NewVector3(10, 0, 0), math64.NewVector3(-2, 0, 0),
+			math64.NewVector3(5, 0, 0), 5,
+		},
+		{
+			"separating",
+			math64.NewVector3(0, 0, 0), math64.NewVector3(-1, 0, 0),
+			math64.NewVector3(0, 0, 4), math64.NewVector3(0, 0, 2),
+			math64.NewVector3(-1, 0, -2), -2,
+		},
+		{
+			"parallel",
+			math64.NewVector3(0, 0, 0), math64.NewVector3(0, 5, 0),
+			math64.NewVector3(3, 0, 0), math64.NewVector3(0, 5, 0),
+			math64.Vector3{}, 0,
+		},
+		{
+			"passing side by side",
+			math64.NewVector3(0, 0, 0), math64.NewVector3(0, 4, 0),
+			math64.NewVector3(3, 0, 0), math64.NewVector3(0, -4, 0),
+			math64.NewVector3(0, 8, 0), 0,
+		},
+		{
+			"oblique approach",
+			math64.NewVector3(0, 0, 0), math64.NewVector3(1, 1, 0),
+			math64.NewVector3(3, 4, 0), math64.Vector3{},
+			math64.NewVector3(1, 1, 0), 1.4,
+		},
+		{
+			"same position",
+			math64.NewVector3(1, 1, 1), math64.NewVector3(1, 0, 0),
+			math64.NewVector3(1, 1, 1), math64.NewVector3(-1, 0, 0),
+			math64.NewVector3(2, 0, 0), 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestParticle(tt.posA, tt.velA, 1)
+			b := newTestParticle(tt.posB, tt.velB, 1)
+
+			if got := RelativeVelocity(a, b); !got.ApproxEqual(tt.wantRelative, 1e-12) {
+				t.Errorf("RelativeVelocity() = %v, want %v", got, tt.wantRelative)
+			}
+			if got := ClosingSpeed(a, b); !math64.ApproxEqual(got, tt.wantClosing, 1e-12) {
+				t.Errorf("ClosingSpeed() = %v, want %v", got, tt.wantClosing)
+			}
+			// Closing speed is the same from either particle's point of view.
+			if got := ClosingSpeed(b, a); !math64.ApproxEqual(got, tt.wantClosing, 1e-12) {
+				t.Errorf("ClosingSpeed(b, a) = %v, want %v", got, tt.wantClosing)
+			}
+		})
+	}
+}
+
+func TestWorkDone(t *testing.T) {
+	tests := []struct {
+		name                string
+		force, displacement math64.Vector3
+		want                float64
+	}{
+		{"aligned", math64.NewVector3(2, 0, 0), math64.NewVector3(3, 0, 0), 6},
+		{"opposed", math64.NewVector3(-2, 0, 0), math64.NewVector3(3, 0, 0), -6},
+		{"perpendicular", math64.NewVector3(0, 5, 0), math64.NewVector3(3, 0, 0), 0},
+		{"oblique", math64.NewVector3(1, 1, 0), math64.NewVector3(2, -1, 4), 1},
+		{"no displacement", math64.NewVector3(1, 2, 3), math64.Vector3{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WorkDone(tt.force, tt.displacement); got != tt.want {
+				t.Errorf("WorkDone(%v, %v) = %v, want %v", tt.force, tt.displacement, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParticleStepWork(t *testing.T) {
+	tests := []struct {
+		name     string
+		velocity math64.Vector3
+		force    func(p *Particle)
+		wantSign int
+	}{
+		{"pushed along", math64.NewVector3(1, 0, 0), func(p *Particle) { p.AddForce(math64.NewVector3(2, 0, 0)) }, 1},
+		{"pushed against", math64.NewVector3(1, 0, 0), func(p *Particle) { p.AddForce(math64.NewVector3(-2, 0, 0)) }, -1},
+		{"pushed sideways", math64.NewVector3(1, 0, 0), func(p *Particle) { p.AddForce(math64.NewVector3(0, 0, 2)) }, 0},
+		{"drag", math64.NewVector3(5, 0, 0), func(p *Particle) { NewDragGenerator(0.1, 0.1).UpdateForce(p, 0.1) }, -1},
+		{"no force", math64.NewVector3(1, 0, 0), func(p *Particle) {}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParticle(math64.Vector3{}, tt.velocity, 1)
+			// Gravity set as a constant acceleration isn't a force, and does no recorded work.
+			p.Acceleration = math64.NewVector3(0, -9.81, 0)
+			tt.force(p)
+			force := p.forceAccumulator
+			start := p.Position
+
+			if err := p.Integrate(0.1); err != nil {
+				t.Fatal(err)
+			}
+
+			displacement := p.Position.SubCopy(start)
+			if want := WorkDone(force, displacement); !math64.ApproxEqual(p.StepWork(), want, 1e-12) {
+				t.Errorf("StepWork() = %v, want %v", p.StepWork(), want)
+			}
+			sign := 0
+			switch {
+			case p.StepWork() > 0:
+				sign = 1
+			case p.StepWork() < 0:
+				sign = -1
+			}
+			if sign != tt.wantSign {
+				t.Errorf("StepWork() = %v, want sign %d", p.StepWork(), tt.wantSign)
+			}
+			if !math64.ApproxEqual(p.StepDistance(), displacement.Magnitude(), 1e-12) {
+				t.Errorf("StepDistance() = %v, want %v", p.StepDistance(), displacement.Magnitude())
+			}
+		})
+	}
+}
+
+func TestConvertPerFrameDamping(t *testing.T) {
+	tests := []struct {
+		name     string
+		perFrame float64
+		fps      float64
+		want     float64
+	}{
+		{"0.99 at 60 FPS", 0.99, 60, 0.5471566423907612},
+		{"0.9 at 30 FPS", 0.9, 30, math.Pow(0.9, 30)},
+		{"no damping", 1, 60, 1},
+		{"one frame per second", 0.5, 1, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			perSecond := ConvertPerFrameDamping(tt.perFrame, tt.fps)
+			if !math64.ApproxEqual(perSecond, tt.want, 1e-12) {
+				t.Fatalf("ConvertPerFrameDamping(%v, %v) = %v, want %v", tt.perFrame, tt.fps, perSecond, tt.want)
+			}
+
+			// Integrating a second of frames at any rate reproduces the per-frame decay at the
+			// reference rate: a second's worth of frames each keeping perFrame of the velocity.
+			want := math.Pow(tt.perFrame, tt.fps)
+			for _, fps := range []float64{tt.fps, 30, 144} {
+				p := newTestParticle(math64.Vector3{}, math64.NewVector3(1, 0, 0), 1)
+				p.Damping = perSecond
+				for i := 0; i < int(fps); i++ {
+					if err := p.Integrate(1 / fps); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if !math64.ApproxEqual(p.Velocity.X, want, 1e-9) {
+					t.Errorf("after a second at %v FPS, speed = %v, want %v", fps, p.Velocity.X, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package physics
+
+// ParticlePool recycles Particle objects so short-lived particles, such as sparks and debris,
+// don't allocate a new object each time one is spawned.
+//
+// A pool is not safe for concurrent use.
+type ParticlePool struct {
+	free []*Particle
+}
+
+// NewParticlePool creates a pool with capacity particles allocated up front in a single block.
+func NewParticlePool(capacity int) *ParticlePool {
+	block := make([]Particle, capacity)
+	pool := &ParticlePool{
+		free: make([]*Particle, capacity),
+	}
+	for i := range block {
+		pool.free[i] = &block[i]
+	}
+	return pool
+}
+
+// Acquire returns a particle from the pool, allocating a new one if the pool is empty. The particle
+// is zeroed apart from its Damping, which is DefaultDamping, as zero damping is invalid.
+func (p *ParticlePool) Acquire() *Particle {
+	n := len(p.free)
+	if n == 0 {
+		return &Particle{Damping: DefaultDamping}
+	}
+
+	particle := p.free[n-1]
+	p.free[n-1] = nil
+	p.free = p.free[:n-1]
+	particle.Damping = DefaultDamping
+	return particle
+}
+
+// Release zeroes the particle and returns it to the pool. The caller must not use the particle
+// after releasing it.
+func (p *ParticlePool) Release(particle *Particle) {
+	*particle = Particle{}
+	p.free = append(p.free, particle)
+}
+
+// Available returns the number of particles ready to be acquired without allocating.
+func (p *ParticlePool) Available() int {
+	return len(p.free)
+}
@@ -0,0 +1,62 @@
+package physics
+
+import "github.com/user54778/cyclone/internal/math64"
+
+// PredictTrajectory simulates a copy of the particle forward by steps integration steps of the
+// given duration, and returns the position after each step. Only the copy is integrated, so
+// neither the particle nor any other particle in the simulation is modified.
+//
+// The enabled generators registered against p in reg are applied to the copy; registrations for
+// other particles are ignored, so a world's whole registry may be passed. Stateful generators, such
+// as ImpulseForceGenerator, are skipped, so predicting never uses them up, and the prediction
+// doesn't include their forces. reg may be nil to predict motion under the particle's acceleration
+// alone. If a step fails to integrate, the positions predicted before it are returned with the
+// error.
+func PredictTrajectory(p *Particle, reg *ForceRegistry, steps int, duration float64) ([]math64.Vector3, error) {
+	if steps <= 0 || duration <= 0 {
+		return nil, nil
+	}
+
+	var generators []ForceGenerator
+	if reg != nil {
+		for _, fg := range reg.GeneratorsFor(p) {
+			if !isStateful(fg) {
+				generators = append(generators, fg)
+			}
+		}
+	}
+
+	sim := *p
+	sim.trail = trail{} // Predicted positions mustn't be recorded into the real particle's trail.
+	positions := make([]math64.Vector3, 0, steps)
+
+	for i := 0; i < steps; i++ {
+		// An immovable particle stays where it is.
+		if sim.HasFiniteMass() {
+			for _, fg := range generators {
+				if !isEnabled(fg) {
+					continue
+				}
+				fg.UpdateForce(&sim, duration)
+			}
+			if err := sim.Integrate(duration); err != nil {
+				return positions, err
+			}
+		}
+		positions = append(positions, sim.Position)
+	}
+
+	return positions, nil
+}
+
+// ExtrapolateParticle predicts where the particle will be after duration, for dead reckoning a
+// networked particle between updates. It takes a single analytic step under constant acceleration,
+// x + v*t + 1/2*a*t^2, from the particle's current velocity and Acceleration. Accumulated forces,
+// registered generators and damping are not taken into account. A zero duration returns the
+// current position.
+func ExtrapolateParticle(p Particle, duration float64) math64.Vector3 {
+	position := p.Position
+	position.ScaleAdd(p.Velocity, duration)
+	position.ScaleAdd(p.Acceleration, 0.5*duration*duration)
+	return position
+}
@@ -0,0 +1,188 @@
+package physics
+
+import (
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+// newPistolShot returns a particle set up like the ballistic demo's pistol round.
+func newPistolShot() *Particle {
+	p := NewParticleMass(
+		math64.NewVector3(0, 1.5, 0),
+		math64.NewVector3(0, 0, 35),
+		math64.NewVector3(0, -1, 0),
+		0.99,
+		2,
+	)
+	return &p
+}
+
+func TestPredictTrajectoryMatchesIntegration(t *testing.T) {
+	tests := []struct {
+		name     string
+		drag     bool
+		steps    int
+		duration float64
+	}{
+		{"pistol", false, 120, 1.0 / 60},
+		{"pistol with drag", true, 120, 1.0 / 60},
+		{"single step", false, 1, 0.1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shot := newPistolShot()
+			var reg ForceRegistry
+			if tt.drag {
+				reg.AddForce(shot, NewDragGenerator(0.1, 0.01))
+			}
+			before := *shot
+
+			predicted, err := PredictTrajectory(shot, &reg, tt.steps, tt.duration)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if shot.Position != before.Position || shot.Velocity != before.Velocity || shot.age != before.age {
+				t.Fatalf("PredictTrajectory changed the particle from %+v to %+v", before, *shot)
+			}
+			if len(predicted) != tt.steps {
+				t.Fatalf("got %d predicted positions, want %d", len(predicted), tt.steps)
+			}
+
+			for i, want := range predicted {
+				reg.UpdateForces(tt.duration)
+				if err := shot.Integrate(tt.duration); err != nil {
+					t.Fatal(err)
+				}
+				if shot.Position != want {
+					t.Fatalf("step %d: integrated to %v, predicted %v", i, shot.Position, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPredictTrajectoryInvalidArguments(t *testing.T) {
+	tests := []struct {
+		name     string
+		steps    int
+		duration float64
+	}{
+		{"no steps", 0, 0.1},
+		{"negative steps", -1, 0.1},
+		{"zero duration", 10, 0},
+	}
+	for _, tt := range tests {
+		if got, err := PredictTrajectory(newPistolShot(), nil, tt.steps, tt.duration); got != nil || err != nil {
+			t.Errorf("%s: PredictTrajectory() = %v, %v, want nil, nil", tt.name, got, err)
+		}
+	}
+}
+
+func TestPredictTrajectoryLeavesStatefulGenerators(t *testing.T) {
+	shot := newPistolShot()
+	impulse := NewImpulseForceGenerator(math64.NewVector3(0, 10, 0))
+	var reg ForceRegistry
+	reg.AddForce(shot, impulse)
+
+	if _, err := PredictTrajectory(shot, &reg, 100, 1.0/60); err != nil {
+		t.Fatal(err)
+	}
+	if impulse.Applied() {
+		t.Fatal("prediction used up the impulse")
+	}
+
+	// The real step still gets the impulse.
+	reg.UpdateForces(0.1)
+	if err := shot.Integrate(0.1); err != nil {
+		t.Fatal(err)
+	}
+	if !impulse.Applied() {
+		t.Error("impulse was not applied by the real step")
+	}
+	if want := 10.0/2 - 0.1; !math64.ApproxEqual(shot.Velocity.Y, want*dampingOver(0.99, 0.1), 1e-9) {
+		t.Errorf("velocity after the real step = %v, want the impulse applied", shot.Velocity)
+	}
+}
+
+func TestPredictTrajectoryIgnoresOtherParticles(t *testing.T) {
+	tests := []struct {
+		name  string
+		other func(shot, other *Particle) (*Particle, ForceGenerator)
+	}{
+		{"generator on another particle", func(shot, other *Particle) (*Particle, ForceGenerator) {
+			return other, NewGravityGenerator(math64.NewVector3(0, -50, 0))
+		}},
+		{"spring on another particle pulled by the shot", func(shot, other *Particle) (*Particle, ForceGenerator) {
+			return other, NewSpringForceGenerator(shot, 100, 0)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shot := newPistolShot()
+			other := newTestParticle(math64.NewVector3(5, 0, 0), math64.Vector3{}, 1)
+
+			// The world's registry holds generators for every particle; only the shot's may apply.
+			var world, own ForceRegistry
+			drag := NewDragGenerator(0.1, 0.01)
+			world.AddForce(shot, drag)
+			own.AddForce(shot, drag)
+			world.AddForce(tt.other(shot, other))
+
+			got, err := PredictTrajectory(shot, &world, 60, 1.0/60)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := PredictTrajectory(shot, &own, 60, 1.0/60)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Fatalf("step %d: predicted %v, want %v without the other particle's generators", i, got[i], want[i])
+				}
+			}
+			if !other.forceAccumulator.IsZero() || other.Position != math64.NewVector3(5, 0, 0) {
+				t.Errorf("prediction touched the other particle: %+v", *other)
+			}
+		})
+	}
+}
+
+func TestExtrapolateParticle(t *testing.T) {
+	tests := []struct {
+		name         string
+		position     math64.Vector3
+		velocity     math64.Vector3
+		acceleration math64.Vector3
+		duration     float64
+	}{
+		{"at rest", math64.NewVector3(1, 2, 3), math64.Vector3{}, math64.Vector3{}, 2},
+		{"constant velocity", math64.Vector3{}, math64.NewVector3(3, 0, -1), math64.Vector3{}, 0.5},
+		{"falling", math64.NewVector3(0, 10, 0), math64.NewVector3(2, 0, 0), math64.NewVector3(0, -9.81, 0), 1.25},
+		{"pistol", math64.NewVector3(0, 1.5, 0), math64.NewVector3(0, 0, 35), math64.NewVector3(0, -1, 0), 1.0 / 60},
+		{"zero duration", math64.NewVector3(4, 5, 6), math64.NewVector3(1, 1, 1), math64.NewVector3(0, -1, 0), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParticleMass(tt.position, tt.velocity, tt.acceleration, 0.5, 2)
+			// A force and damping would change an integrated step but don't enter the extrapolation.
+			p.AddForce(math64.NewVector3(100, 0, 0))
+
+			d := tt.duration
+			want := math64.NewVector3(
+				tt.position.X+tt.velocity.X*d+0.5*tt.acceleration.X*d*d,
+				tt.position.Y+tt.velocity.Y*d+0.5*tt.acceleration.Y*d*d,
+				tt.position.Z+tt.velocity.Z*d+0.5*tt.acceleration.Z*d*d,
+			)
+			if got := ExtrapolateParticle(p, d); !got.ApproxEqual(want, 1e-12) {
+				t.Errorf("ExtrapolateParticle(%v) = %v, want %v", d, got, want)
+			}
+			if d == 0 {
+				if got := ExtrapolateParticle(p, d); got != tt.position {
+					t.Errorf("ExtrapolateParticle(0) = %v, want the current position %v", got, tt.position)
+				}
+			}
+		})
+	}
+}
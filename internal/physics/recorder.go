@@ -0,0 +1,103 @@
+package physics
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+// Sample is the state of a single particle captured by a Recorder at one step.
+type Sample struct {
+	Step       int // Index of the capture the sample was taken in, starting at 0.
+	ParticleID int // ID the recorder gave the particle the first time it saw it.
+	Position   math64.Vector3
+	Velocity   math64.Vector3
+}
+
+// Recorder captures the position and velocity of every particle in a ParticleWorld, once per
+// call to Capture, so trajectories can be analysed or plotted outside the engine.
+//
+// Particles are given IDs in the order the recorder first sees them. A pooled particle that is
+// despawned and spawned again keeps the ID it was first given.
+type Recorder struct {
+	world   *ParticleWorld
+	ids     map[*Particle]int
+	samples []Sample
+	step    int
+}
+
+// NewRecorder creates a recorder for the given world. Nothing is captured until Capture is called.
+func NewRecorder(world *ParticleWorld) *Recorder {
+	return &Recorder{
+		world: world,
+		ids:   make(map[*Particle]int),
+	}
+}
+
+// Capture records the current state of every particle in the world as the next step. Call it
+// once before stepping the world to record the initial state, then once after each step.
+func (r *Recorder) Capture() {
+	for _, p := range r.world.Particles() {
+		id, ok := r.ids[p]
+		if !ok {
+			id = len(r.ids)
+			r.ids[p] = id
+		}
+
+		r.samples = append(r.samples, Sample{
+			Step:       r.step,
+			ParticleID: id,
+			Position:   p.Position,
+			Velocity:   p.Velocity,
+		})
+	}
+	r.step++
+}
+
+// Samples returns every sample captured so far, ordered by step.
+func (r *Recorder) Samples() []Sample {
+	return r.samples
+}
+
+// Reset discards all captured samples and particle IDs, and restarts the step count at 0.
+func (r *Recorder) Reset() {
+	r.ids = make(map[*Particle]int)
+	r.samples = nil
+	r.step = 0
+}
+
+// WriteCSV writes the captured samples to w as CSV, with a header row followed by one row per sample.
+func (r *Recorder) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"step", "particle_id", "pos_x", "pos_y", "pos_z", "vel_x", "vel_y", "vel_z"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, s := range r.samples {
+		record := []string{
+			strconv.Itoa(s.Step),
+			strconv.Itoa(s.ParticleID),
+			formatFloat(s.Position.X),
+			formatFloat(s.Position.Y),
+			formatFloat(s.Position.Z),
+			formatFloat(s.Velocity.X),
+			formatFloat(s.Velocity.Y),
+			formatFloat(s.Velocity.Z),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatFloat formats f with the fewest digits needed to represent it exactly.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,61 @@
+package physics
+
+import (
+	"sort"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+// scheduledEvent is a callback waiting for the world's simulated time to reach at.
+type scheduledEvent struct {
+	at float64
+	fn func(w *ParticleWorld)
+}
+
+// ScheduleAt schedules fn to be called once, at the end of the first RunPhysics step after which
+// the world's Elapsed time has reached t. A time that has already passed fires at the end of the
+// next step. Callbacks due in the same step fire in order of their scheduled time, and in the order
+// they were scheduled for equal times.
+//
+// Elapsed time is a running sum of step durations, so t is allowed to be missed by a rounding error
+// of up to math64.Epsilon; scheduling at 1.0 fires after the hundredth step of 0.01 seconds.
+//
+// Callbacks run after the world's lock is released, so they may add particles, register forces, or
+// schedule further callbacks.
+func (w *ParticleWorld) ScheduleAt(t float64, fn func(w *ParticleWorld)) {
+	w.lock()
+	defer w.unlock()
+
+	// Insert after every event at or before t, keeping the queue sorted and stable.
+	i := sort.Search(len(w.events), func(i int) bool {
+		return w.events[i].at > t
+	})
+	w.events = append(w.events, scheduledEvent{})
+	copy(w.events[i+1:], w.events[i:])
+	w.events[i] = scheduledEvent{at: t, fn: fn}
+}
+
+// dueEvents removes and returns the callbacks whose scheduled time has been reached.
+func (w *ParticleWorld) dueEvents() []func(w *ParticleWorld) {
+	n := 0
+	for n < len(w.events) && w.events[n].at <= w.elapsed+math64.Epsilon {
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+
+	due := make([]func(w *ParticleWorld), n)
+	for i := range due {
+		due[i] = w.events[i].fn
+	}
+
+	// Clear the fired events so their callbacks aren't kept alive by the backing array.
+	remaining := copy(w.events, w.events[n:])
+	for i := remaining; i < len(w.events); i++ {
+		w.events[i] = scheduledEvent{}
+	}
+	w.events = w.events[:remaining]
+
+	return due
+}
@@ -0,0 +1,72 @@
+package physics
+
+import "testing"
+
+func TestScheduleAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		at       float64
+		dt       float64
+		wantStep int // The step, counting from 1, whose end the callback fires at.
+		preSteps int // Steps run before the callback is scheduled.
+	}{
+		{"crossing step", 1, 0.3, 4, 0},
+		{"landing exactly", 1, 0.01, 100, 0},
+		{"at zero fires on the first step", 0, 0.5, 1, 0},
+		{"past due fires on the next step", 0.2, 0.25, 3, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewParticleWorld(0, 0)
+			for i := 0; i < tt.preSteps; i++ {
+				w.RunPhysics(tt.dt)
+			}
+
+			fired := 0
+			firedAt := 0
+			w.ScheduleAt(tt.at, func(w *ParticleWorld) {
+				fired++
+				firedAt = int(w.StepCount())
+			})
+			for i := tt.preSteps; i < tt.wantStep+10; i++ {
+				if err := w.RunPhysics(tt.dt); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if fired != 1 {
+				t.Fatalf("callback fired %d times, want once", fired)
+			}
+			if firedAt != tt.wantStep {
+				t.Errorf("callback fired after step %d, want step %d", firedAt, tt.wantStep)
+			}
+		})
+	}
+}
+
+func TestScheduleAtOrder(t *testing.T) {
+	w := NewParticleWorld(0, 0)
+	var order []string
+	record := func(name string) func(w *ParticleWorld) {
+		return func(*ParticleWorld) { order = append(order, name) }
+	}
+	w.ScheduleAt(0.3, record("c"))
+	w.ScheduleAt(0.1, record("a"))
+	w.ScheduleAt(0.3, record("d"))
+	w.ScheduleAt(0.2, record("b"))
+	// A callback can schedule another, which fires on a later step.
+	w.ScheduleAt(0.2, func(w *ParticleWorld) { w.ScheduleAt(0, record("e")) })
+
+	w.RunPhysics(0.5)
+	if got, want := len(order), 4; got != want {
+		t.Fatalf("fired %v in the first step, want 4 callbacks", order)
+	}
+	w.RunPhysics(0.5)
+
+	want := []string{"a", "b", "c", "d", "e"}
+	for i := range want {
+		if i >= len(order) || order[i] != want[i] {
+			t.Fatalf("callbacks fired in order %v, want %v", order, want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package physics
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+// ForceEvent is a single force applied to a particle, recorded by a ForceTimeline.
+type ForceEvent struct {
+	Step       uint64 // World StepCount when the force was applied, i.e. the step it acted in.
+	ParticleID int    // Index of the particle in the world's Particles.
+	Force      math64.Vector3
+}
+
+// ForceTimeline records the external forces applied to a world's particles, such as player input,
+// so a run can be reproduced exactly by replaying them against a freshly built copy of the world.
+//
+// Particles are identified by their index in ParticleWorld.Particles, so the replay world must be
+// built with the same particles, added in the same order.
+type ForceTimeline struct {
+	// Events holds the recorded forces, ordered by Step.
+	Events []ForceEvent
+}
+
+// Apply applies force to the particle in the world's next step, and records it against that step
+// so it is reapplied at the same step on replay. The force is applied by a one-shot generator
+// registered with the world, so Apply may be called at any point between steps, before or after
+// StartFrame. A particle that isn't in the world is not recorded, and an error is returned.
+func (t *ForceTimeline) Apply(w *ParticleWorld, particle *Particle, force math64.Vector3) error {
+	id := -1
+	for i, p := range w.Particles() {
+		if p == particle {
+			id = i
+			break
+		}
+	}
+	if id < 0 {
+		return errors.New("physics: particle is not in the world")
+	}
+
+	applyOnce(w, particle, force)
+	t.Events = append(t.Events, ForceEvent{
+		Step:       w.StepCount(),
+		ParticleID: id,
+		Force:      force,
+	})
+	return nil
+}
+
+// Replay applies every recorded force for the world's current step to its particles in the next
+// step. Call it between each RunPhysics of the replay world, just as Apply was called in the
+// recorded one.
+func (t *ForceTimeline) Replay(w *ParticleWorld) {
+	step := w.StepCount()
+	particles := w.Particles()
+
+	i := sort.Search(len(t.Events), func(i int) bool {
+		return t.Events[i].Step >= step
+	})
+	for ; i < len(t.Events) && t.Events[i].Step == step; i++ {
+		e := t.Events[i]
+		if e.ParticleID < len(particles) {
+			applyOnce(w, particles[e.ParticleID], e.Force)
+		}
+	}
+}
+
+// applyOnce registers a oneShotForceGenerator applying force to the particle in the world's next
+// step, and schedules its removal once that step ends.
+func applyOnce(w *ParticleWorld, particle *Particle, force math64.Vector3) {
+	fg := &oneShotForceGenerator{Force: force}
+	w.AddForce(particle, fg)
+	w.ScheduleAt(w.Elapsed(), func(w *ParticleWorld) {
+		w.RemoveForce(particle, fg)
+	})
+}
+
+// oneShotForceGenerator applies Force to a particle the first time it is updated, and no force
+// after that.
+type oneShotForceGenerator struct {
+	Force   math64.Vector3
+	applied bool
+}
+
+// Stateful reports that the generator is used up by applying its force.
+func (o *oneShotForceGenerator) Stateful() bool {
+	return true
+}
+
+// UpdateForce applies the force, unless it has already been applied.
+func (o *oneShotForceGenerator) UpdateForce(particle *Particle, duration float64) {
+	if o.applied {
+		return
+	}
+
+	particle.AddForce(o.Force)
+	o.applied = true
+}
+
+// forceTimelineHeader is the header row of a ForceTimeline's CSV form.
+var forceTimelineHeader = []string{"step", "particle_id", "force_x", "force_y", "force_z"}
+
+// WriteCSV writes the timeline to w as CSV, with a header row followed by one row per event.
+// Forces are written with every digit needed to read them back exactly.
+func (t *ForceTimeline) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(forceTimelineHeader); err != nil {
+		return err
+	}
+
+	for _, e := range t.Events {
+		record := []string{
+			strconv.FormatUint(e.Step, 10),
+			strconv.Itoa(e.ParticleID),
+			formatFloat(e.Force.X),
+			formatFloat(e.Force.Y),
+			formatFloat(e.Force.Z),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadForceTimelineCSV reads a timeline written by WriteCSV.
+func ReadForceTimelineCSV(r io.Reader) (*ForceTimeline, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(forceTimelineHeader)
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("physics: force timeline is missing its header")
+	}
+
+	t := &ForceTimeline{Events: make([]ForceEvent, 0, len(records)-1)}
+	for line, record := range records[1:] {
+		e, err := parseForceEvent(record)
+		if err != nil {
+			return nil, fmt.Errorf("physics: force timeline row %d: %w", line+1, err)
+		}
+		t.Events = append(t.Events, e)
+	}
+	return t, nil
+}
+
+// parseForceEvent parses a single CSV row written by ForceTimeline.WriteCSV.
+func parseForceEvent(record []string) (ForceEvent, error) {
+	var e ForceEvent
+	var err error
+
+	if e.Step, err = strconv.ParseUint(record[0], 10, 64); err != nil {
+		return e, err
+	}
+	if e.ParticleID, err = strconv.Atoi(record[1]); err != nil {
+		return e, err
+	}
+	if e.Force.X, err = strconv.ParseFloat(record[2], 64); err != nil {
+		return e, err
+	}
+	if e.Force.Y, err = strconv.ParseFloat(record[3], 64); err != nil {
+		return e, err
+	}
+	if e.Force.Z, err = strconv.ParseFloat(record[4], 64); err != nil {
+		return e, err
+	}
+	return e, nil
+}
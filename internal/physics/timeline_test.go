@@ -0,0 +1,161 @@
+package physics
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+// newTimelineTestWorld builds the same small world every time, so a recorded run can be replayed
+// against a fresh copy of it.
+func newTimelineTestWorld() *ParticleWorld {
+	w := NewParticleWorld(10, 4)
+	var particles []*Particle
+	for i := 0; i < 3; i++ {
+		p := newTestParticle(math64.NewVector3(float64(i), 2, 0), math64.Vector3{}, float64(i+1))
+		p.Acceleration = math64.NewVector3(0, -9.81, 0)
+		p.Damping = 0.95
+		particles = append(particles, p)
+		w.AddParticle(p)
+	}
+	w.AddContactGenerator(NewGroundContactGenerator(particles, 0, 0.5, 0.2))
+	return w
+}
+
+// timelineForce returns the force applied to particle i at step, or false if there is none, so the
+// recorded run has a varied but repeatable set of forces.
+func timelineForce(step uint64, i int) (math64.Vector3, bool) {
+	if (int(step)+i)%3 != 0 {
+		return math64.Vector3{}, false
+	}
+	return math64.NewVector3(float64(i+1), 20*float64(step%5), -float64(step%7)), true
+}
+
+func TestForceTimelineReplay(t *testing.T) {
+	tests := []struct {
+		name           string
+		applyBeforeRun bool // Apply before StartFrame rather than after it.
+		viaCSV         bool
+	}{
+		{"after StartFrame", false, false},
+		{"before StartFrame", true, false},
+		{"through CSV", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const steps = 60
+
+			recorded := newTimelineTestWorld()
+			var timeline ForceTimeline
+			applyForces := func() {
+				for i, p := range recorded.Particles() {
+					if force, ok := timelineForce(recorded.StepCount(), i); ok {
+						if err := timeline.Apply(recorded, p, force); err != nil {
+							t.Fatal(err)
+						}
+					}
+				}
+			}
+			for step := 0; step < steps; step++ {
+				if tt.applyBeforeRun {
+					applyForces()
+					recorded.StartFrame()
+				} else {
+					recorded.StartFrame()
+					applyForces()
+				}
+				if err := recorded.RunPhysics(1.0 / 60); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			replaying := &timeline
+			if tt.viaCSV {
+				var buf bytes.Buffer
+				if err := timeline.WriteCSV(&buf); err != nil {
+					t.Fatal(err)
+				}
+				var err error
+				if replaying, err = ReadForceTimelineCSV(&buf); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			replay := newTimelineTestWorld()
+			for step := 0; step < steps; step++ {
+				replay.StartFrame()
+				replaying.Replay(replay)
+				if err := replay.RunPhysics(1.0 / 60); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for i, want := range recorded.Particles() {
+				got := replay.Particles()[i]
+				if got.Position != want.Position || got.Velocity != want.Velocity {
+					t.Errorf("particle %d replayed to %v moving %v, recorded %v moving %v",
+						i, got.Position, got.Velocity, want.Position, want.Velocity)
+				}
+			}
+			// Every one-shot generator is removed once its step is over.
+			for i, p := range recorded.Particles() {
+				if n := len(recorded.Registry.GeneratorsFor(p)); n != 0 {
+					t.Errorf("particle %d still has %d generators registered", i, n)
+				}
+			}
+		})
+	}
+}
+
+func TestForceTimelineApply(t *testing.T) {
+	tests := []struct {
+		name       string
+		startFrame bool // Call StartFrame between Apply and RunPhysics.
+	}{
+		{"without StartFrame", false},
+		{"StartFrame after Apply", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewParticleWorld(0, 0)
+			p := newTestParticle(math64.Vector3{}, math64.Vector3{}, 2)
+			w.AddParticle(p)
+
+			var timeline ForceTimeline
+			if err := timeline.Apply(w, p, math64.NewVector3(4, 0, 0)); err != nil {
+				t.Fatal(err)
+			}
+			if tt.startFrame {
+				w.StartFrame()
+			}
+			// The force acts for exactly one step: 4 N on 2 kg for 0.5 s is 1 m/s.
+			for i := 0; i < 2; i++ {
+				if err := w.RunPhysics(0.5); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if want := math64.NewVector3(1, 0, 0); !p.Velocity.ApproxEqual(want, 1e-12) {
+				t.Errorf("velocity = %v, want %v", p.Velocity, want)
+			}
+			want := []ForceEvent{{Step: 0, ParticleID: 0, Force: math64.NewVector3(4, 0, 0)}}
+			if len(timeline.Events) != 1 || timeline.Events[0] != want[0] {
+				t.Errorf("Events = %v, want %v", timeline.Events, want)
+			}
+		})
+	}
+}
+
+func TestForceTimelineApplyUnknownParticle(t *testing.T) {
+	w := NewParticleWorld(0, 0)
+	stray := newTestParticle(math64.Vector3{}, math64.Vector3{}, 1)
+
+	var timeline ForceTimeline
+	if err := timeline.Apply(w, stray, math64.NewVector3(1, 0, 0)); err == nil {
+		t.Error("Apply to a particle not in the world succeeded, want an error")
+	}
+	if len(timeline.Events) != 0 || len(w.Registry.GeneratorsFor(stray)) != 0 {
+		t.Errorf("Apply to a particle not in the world recorded %v", timeline.Events)
+	}
+}
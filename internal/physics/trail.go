@@ -0,0 +1,53 @@
+package physics
+
+import "github.com/user54778/cyclone/internal/math64"
+
+// trail is a fixed-size ring buffer of positions. Its zero value is disabled and records nothing.
+type trail struct {
+	buf   []math64.Vector3
+	next  int // Index the next position is written to.
+	count int // Number of positions recorded, up to len(buf).
+}
+
+// newTrail creates a trail holding up to n positions.
+func newTrail(n int) trail {
+	if n <= 0 {
+		return trail{}
+	}
+	return trail{buf: make([]math64.Vector3, n)}
+}
+
+// record adds a position to the trail, overwriting the oldest once it is full.
+func (t *trail) record(position math64.Vector3) {
+	if len(t.buf) == 0 {
+		return
+	}
+
+	t.buf[t.next] = position
+	t.next = (t.next + 1) % len(t.buf)
+	if t.count < len(t.buf) {
+		t.count++
+	}
+}
+
+// positions returns a copy of the recorded positions, oldest first.
+func (t *trail) positions() []math64.Vector3 {
+	if len(t.buf) == 0 {
+		return nil
+	}
+
+	out := make([]math64.Vector3, 0, t.count)
+	start := (t.next - t.count + len(t.buf)) % len(t.buf)
+	for i := 0; i < t.count; i++ {
+		out = append(out, t.buf[(start+i)%len(t.buf)])
+	}
+	return out
+}
+
+// clone returns a copy of the trail that doesn't share its buffer.
+func (t trail) clone() trail {
+	if t.buf != nil {
+		t.buf = append([]math64.Vector3(nil), t.buf...)
+	}
+	return t
+}
@@ -0,0 +1,50 @@
+package physics
+
+import "fmt"
+
+// Validate checks every particle in the world for misconfiguration, without running any physics,
+// and returns one error for each problem found. A correctly configured world returns nil.
+//
+// It reports, with the particle's index in Particles:
+//   - a NaN or infinite position, velocity or acceleration (ErrNonFinite);
+//   - a damping, or per-axis damping, outside the range (0, 1] (ErrInvalidDamping);
+//   - an infinite-mass particle with registered force generators, which can never move it
+//     (ErrInfiniteMass).
+func (w *ParticleWorld) Validate() []error {
+	w.lock()
+	defer w.unlock()
+
+	var errs []error
+	report := func(i int, code ErrorCode, format string, args ...any) {
+		message := fmt.Sprintf("particle %d: ", i) + fmt.Sprintf(format, args...)
+		errs = append(errs, newPhysicsError(code, message))
+	}
+
+	for i, p := range w.particles {
+		if !p.Position.IsFinite() {
+			report(i, ErrNonFinite, "position %v is not finite", p.Position)
+		}
+		if !p.Velocity.IsFinite() {
+			report(i, ErrNonFinite, "velocity %v is not finite", p.Velocity)
+		}
+		if !p.Acceleration.IsFinite() {
+			report(i, ErrNonFinite, "acceleration %v is not finite", p.Acceleration)
+		}
+		if !inDampingRange(p.Damping) {
+			report(i, ErrInvalidDamping, "damping %v is outside the range (0, 1]", p.Damping)
+		}
+		if d := p.DampingAxes; !d.IsZero() && !(inDampingRange(d.X) && inDampingRange(d.Y) && inDampingRange(d.Z)) {
+			report(i, ErrInvalidDamping, "per-axis damping %v is outside the range (0, 1]", d)
+		}
+		if !p.HasFiniteMass() && len(w.Registry.GeneratorsFor(p)) > 0 {
+			report(i, ErrInfiniteMass, "has infinite mass but registered force generators")
+		}
+	}
+
+	return errs
+}
+
+// inDampingRange reports whether d is a valid damping, in the range (0, 1].
+func inDampingRange(d float64) bool {
+	return d > 0 && d <= 1
+}
@@ -0,0 +1,63 @@
+package physics
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+func TestWorldValidate(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		name   string
+		modify func(w *ParticleWorld, p *Particle)
+		want   []ErrorCode
+	}{
+		{"clean", func(*ParticleWorld, *Particle) {}, nil},
+		{"NaN position and bad damping", func(w *ParticleWorld, p *Particle) {
+			p.Position.Y = nan
+			p.Damping = 1.5
+		}, []ErrorCode{ErrNonFinite, ErrInvalidDamping}},
+		{"infinite velocity", func(w *ParticleWorld, p *Particle) { p.Velocity.X = math.Inf(-1) }, []ErrorCode{ErrNonFinite}},
+		{"NaN acceleration", func(w *ParticleWorld, p *Particle) { p.Acceleration.Z = nan }, []ErrorCode{ErrNonFinite}},
+		{"zero damping", func(w *ParticleWorld, p *Particle) { p.Damping = 0 }, []ErrorCode{ErrInvalidDamping}},
+		{"bad per-axis damping", func(w *ParticleWorld, p *Particle) { p.DampingAxes = math64.NewVector3(1, -0.5, 1) }, []ErrorCode{ErrInvalidDamping}},
+		{"valid per-axis damping", func(w *ParticleWorld, p *Particle) { p.DampingAxes = math64.NewVector3(1, 0.5, 1) }, nil},
+		{"forces on infinite mass", func(w *ParticleWorld, p *Particle) {
+			p.SetInverseMass(0)
+			w.AddForce(p, NewDragGenerator(1, 1))
+		}, []ErrorCode{ErrInfiniteMass}},
+		{"infinite mass without forces", func(w *ParticleWorld, p *Particle) { p.SetInverseMass(0) }, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewParticleWorld(0, 0)
+			w.AddParticle(newTestParticle(math64.Vector3{}, math64.NewVector3(1, 0, 0), 1))
+			p := newTestParticle(math64.NewVector3(1, 2, 3), math64.Vector3{}, 1)
+			w.AddParticle(p)
+			tt.modify(w, p)
+
+			errs := w.Validate()
+			if len(errs) != len(tt.want) {
+				t.Fatalf("Validate() = %v, want %d errors", errs, len(tt.want))
+			}
+			for i, code := range tt.want {
+				if !errors.Is(errs[i], code) {
+					t.Errorf("error %d = %v, want %v", i, errs[i], code)
+				}
+			}
+		})
+	}
+}
+
+func TestWorldValidateDoesNotStep(t *testing.T) {
+	w := NewParticleWorld(0, 0)
+	p := newTestParticle(math64.Vector3{}, math64.NewVector3(1, 0, 0), 1)
+	w.AddParticle(p)
+	w.Validate()
+	if !p.Position.IsZero() || w.StepCount() != 0 {
+		t.Errorf("Validate moved the particle to %v or stepped the world %d times", p.Position, w.StepCount())
+	}
+}
@@ -0,0 +1,479 @@
+package physics
+
+import (
+	"math"
+	"sync"
+
+	"github.com/user54778/cyclone/internal/math64"
+	"github.com/user54778/cyclone/internal/physicslog"
+)
+
+// ParticleWorld keeps track of a set of particles, and provides the means to update them all.
+//
+// Each frame, the world applies registered forces, integrates every particle, generates contacts
+// from its contact generators and resolves them.
+type ParticleWorld struct {
+	// Registry holds the force generators for the particles in this world.
+	Registry ForceRegistry
+	// Pool, if set, is used to acquire spawned particles and release despawned ones.
+	Pool *ParticlePool
+	// AutoDespawn, if true, makes RunPhysics despawn every expired particle after integrating,
+	// removing its force registrations and releasing it to the Pool.
+	AutoDespawn bool
+	// Bounds, if set, confines every particle to the box. After integrating, a particle outside
+	// it is moved back to the nearest point inside.
+	Bounds *math64.AABB
+	// WallBounds, if true, makes Bounds act like walls: a clamped particle also loses the part of its
+	// velocity carrying it out of the box.
+	WallBounds bool
+	// Logger, if set, logs every error RunPhysics returns. Each world has its own logger, if any;
+	// nothing is logged by default.
+	Logger *physicslog.PhysicsLogger
+	// Substeps, if greater than one, splits each RunPhysics step into that many equal substeps,
+	// each of which applies forces, integrates, and generates and resolves contacts. Fast particles
+	// then move less between contact checks, so they are less likely to tunnel through thin
+	// obstacles, at the cost of doing all of that work Substeps times per step.
+	Substeps int
+	// Solver, if set, resolves each frame's contacts in place of the world's built-in resolver,
+	// the one returned by Resolver.
+	Solver ContactSolver
+	// MaxStepDistance, if positive, splits a step (or each of its Substeps) into smaller equal steps
+	// whenever a particle would otherwise move further than this in one of them, so fast particles
+	// can't jump past obstacles thinner than it. A particle moves its velocity times the step
+	// duration, so the split is decided from the fastest finite-mass particle at the start of the
+	// step. A step is split at most maxStepDivisions times, however fast its particles move.
+	MaxStepDistance float64
+
+	particles         []*Particle
+	contactGenerators []ParticleContactGenerator
+	resolver          *ParticleContactResolver
+	// contacts is the fixed-size buffer contact generators write into each frame.
+	contacts []ParticleContact
+	// calculateIterations is true if the world should calculate the number of iterations
+	// to give the contact resolver at each frame.
+	calculateIterations bool
+	// elapsed is the total simulated time, in seconds, over every call to RunPhysics.
+	elapsed float64
+	// steps is the number of completed calls to RunPhysics.
+	steps uint64
+	// divisions is the number of steps the last call to RunPhysics was split into.
+	divisions int
+	// events holds the callbacks waiting to fire, ordered by their scheduled time.
+	events []scheduledEvent
+	// concurrent is true if the world guards its particles and registry with mu.
+	concurrent bool
+	mu         sync.Mutex
+}
+
+// NewParticleWorld creates a new particle simulator that can handle up to the given number of
+// contacts per frame. An iteration count of zero or less means the resolver will use twice the
+// number of contacts generated each frame.
+func NewParticleWorld(maxContacts, iterations int) *ParticleWorld {
+	return &ParticleWorld{
+		resolver:            NewParticleContactResolver(iterations),
+		contacts:            make([]ParticleContact, maxContacts),
+		calculateIterations: iterations <= 0,
+	}
+}
+
+// NewConcurrentParticleWorld creates a particle simulator like NewParticleWorld, but whose particles
+// and force registrations can be safely added and removed from other goroutines while it runs.
+//
+// In this mode AddParticle, RemoveParticle, SpawnParticle, DespawnParticle, AddForce, RemoveForce,
+// AddContactGenerator, Particles, NearestParticle, ApplyGlobalForce, ApplyGlobalImpulse, StartFrame,
+// Elapsed, StepCount, StepDivisions, ScheduleAt, Validate and RunPhysics all take an internal lock.
+// Integrate and GenerateContacts are the unlocked steps of RunPhysics, and should not be called
+// directly. Particles should be fully configured before they are added, and the Registry field
+// should only be changed through AddForce and RemoveForce.
+func NewConcurrentParticleWorld(maxContacts, iterations int) *ParticleWorld {
+	w := NewParticleWorld(maxContacts, iterations)
+	w.concurrent = true
+	return w
+}
+
+// lock acquires the world's lock if it was created with NewConcurrentParticleWorld.
+func (w *ParticleWorld) lock() {
+	if w.concurrent {
+		w.mu.Lock()
+	}
+}
+
+// unlock releases the world's lock if it was created with NewConcurrentParticleWorld.
+func (w *ParticleWorld) unlock() {
+	if w.concurrent {
+		w.mu.Unlock()
+	}
+}
+
+// AddParticle adds a particle to the world so it is integrated each frame.
+func (w *ParticleWorld) AddParticle(particle *Particle) {
+	w.lock()
+	defer w.unlock()
+	w.addParticle(particle)
+}
+
+// addParticle adds a particle to the world without taking the lock.
+func (w *ParticleWorld) addParticle(particle *Particle) {
+	w.particles = append(w.particles, particle)
+}
+
+// RemoveParticle removes a particle from the world. If the particle is *not* in the world,
+// this method will do nothing.
+func (w *ParticleWorld) RemoveParticle(particle *Particle) {
+	w.lock()
+	defer w.unlock()
+	w.removeParticle(particle)
+}
+
+// removeParticle removes a particle from the world without taking the lock.
+func (w *ParticleWorld) removeParticle(particle *Particle) {
+	for i, p := range w.particles {
+		if p == particle {
+			w.particles = append(w.particles[:i], w.particles[i+1:]...)
+			return
+		}
+	}
+}
+
+// SpawnParticle adds a new particle to the world and returns it. The particle comes from the
+// world's Pool when one is set. It is zeroed apart from its Damping, which is DefaultDamping.
+func (w *ParticleWorld) SpawnParticle() *Particle {
+	w.lock()
+	defer w.unlock()
+
+	var particle *Particle
+	if w.Pool != nil {
+		particle = w.Pool.Acquire()
+	} else {
+		particle = &Particle{Damping: DefaultDamping}
+	}
+	w.addParticle(particle)
+	return particle
+}
+
+// DespawnParticle removes a particle from the world along with all of its force registrations,
+// and releases it back to the world's Pool when one is set.
+func (w *ParticleWorld) DespawnParticle(particle *Particle) {
+	w.lock()
+	defer w.unlock()
+
+	w.removeParticle(particle)
+	w.Registry.RemoveAllForParticle(particle)
+	if w.Pool != nil {
+		w.Pool.Release(particle)
+	}
+}
+
+// AddForce registers the given force generator to apply to the given particle in this world's Registry.
+func (w *ParticleWorld) AddForce(particle *Particle, fg ForceGenerator) {
+	w.lock()
+	defer w.unlock()
+	w.Registry.AddForce(particle, fg)
+}
+
+// RemoveForce removes a registered pair from this world's Registry.
+func (w *ParticleWorld) RemoveForce(particle *Particle, fg ForceGenerator) {
+	w.lock()
+	defer w.unlock()
+	w.Registry.RemoveForce(particle, fg)
+}
+
+// Particles returns the particles in the world. A concurrent world returns a copy of the slice,
+// so it stays valid while other goroutines add and remove particles.
+func (w *ParticleWorld) Particles() []*Particle {
+	w.lock()
+	defer w.unlock()
+
+	if w.concurrent {
+		particles := make([]*Particle, len(w.particles))
+		copy(particles, w.particles)
+		return particles
+	}
+	return w.particles
+}
+
+// NearestParticle returns the particle in the world closest to point, and its distance from it.
+// Of particles at the same distance, the one added first is returned. An empty world returns nil
+// and an infinite distance.
+func (w *ParticleWorld) NearestParticle(point math64.Vector3) (*Particle, float64) {
+	w.lock()
+	defer w.unlock()
+
+	var nearest *Particle
+	nearestSquared := math.Inf(1)
+	for _, p := range w.particles {
+		if d := p.Position.DistanceSquared(point); d < nearestSquared {
+			nearest, nearestSquared = p, d
+		}
+	}
+	return nearest, math.Sqrt(nearestSquared)
+}
+
+// ApplyGlobalForce adds force to the force accumulator of every finite-mass particle in the world,
+// for effects such as a shockwave that push everything at once. Like any other force, it is applied
+// by the next integration step and then cleared, so call it between StartFrame and RunPhysics.
+// RunPhysics may split the frame into several integration steps, when Substeps is set or when
+// MaxStepDistance splits a step for a fast particle, and then only the first of them feels the
+// force. Register a force generator instead for a force that should act over the whole frame.
+func (w *ParticleWorld) ApplyGlobalForce(force math64.Vector3) {
+	w.lock()
+	defer w.unlock()
+
+	for _, p := range w.particles {
+		if p.HasFiniteMass() {
+			p.AddForce(force)
+		}
+	}
+}
+
+// ApplyGlobalImpulse applies impulse to every finite-mass particle in the world, changing each
+// velocity immediately by impulse scaled by the particle's inverse mass. Lighter particles are
+// therefore thrown harder.
+func (w *ParticleWorld) ApplyGlobalImpulse(impulse math64.Vector3) {
+	w.lock()
+	defer w.unlock()
+
+	for _, p := range w.particles {
+		if p.HasFiniteMass() {
+			p.ApplyImpulse(impulse)
+		}
+	}
+}
+
+// Resolver returns the contact resolver the world uses each frame, so its options can be configured.
+func (w *ParticleWorld) Resolver() *ParticleContactResolver {
+	return w.resolver
+}
+
+// AddContactGenerator registers a contact generator to be run each frame.
+func (w *ParticleWorld) AddContactGenerator(generator ParticleContactGenerator) {
+	w.lock()
+	defer w.unlock()
+	w.contactGenerators = append(w.contactGenerators, generator)
+}
+
+// StartFrame initializes the world for a simulation frame. This clears the force accumulators
+// for particles in the world. After calling this, the particles can have their forces for this
+// frame added.
+func (w *ParticleWorld) StartFrame() {
+	w.lock()
+	defer w.unlock()
+
+	for _, p := range w.particles {
+		p.ClearForces()
+	}
+}
+
+// GenerateContacts calls each of the registered contact generators to report their contacts,
+// and returns the number of generated contacts.
+func (w *ParticleWorld) GenerateContacts() int {
+	used := 0
+	for _, g := range w.contactGenerators {
+		if used >= len(w.contacts) {
+			// We've run out of contacts to fill. This means we're missing contacts.
+			break
+		}
+		used += g.AddContact(w.contacts[used:])
+	}
+	return used
+}
+
+// Integrate integrates all the particles in this world forward in time by the given duration.
+// Particles with infinite mass are immovable, and are skipped.
+func (w *ParticleWorld) Integrate(duration float64) error {
+	for _, p := range w.particles {
+		if !p.HasFiniteMass() {
+			continue
+		}
+		if err := p.Integrate(duration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunPhysics processes all the physics for the particle world.
+func (w *ParticleWorld) RunPhysics(duration float64) error {
+	w.lock()
+	err := w.runPhysics(duration)
+	var due []func(w *ParticleWorld)
+	if err == nil {
+		due = w.dueEvents()
+	}
+	w.unlock()
+
+	if err != nil && w.Logger != nil {
+		w.Logger.LogError(err.Error())
+	}
+
+	// Scheduled callbacks run outside the lock, so they're free to call back into the world.
+	for _, fn := range due {
+		fn(w)
+	}
+
+	return err
+}
+
+// runPhysics runs a single step of RunPhysics without taking the lock.
+func (w *ParticleWorld) runPhysics(duration float64) error {
+	if duration <= 0.0 {
+		return newPhysicsError(ErrNegativeDuration, "can not run physics on a negative duration")
+	}
+
+	substeps := max(w.Substeps, 1)
+	substep := duration / float64(substeps)
+	w.divisions = 0
+	for i := 0; i < substeps; i++ {
+		// Particles speed up over the frame, so each substep is checked for splitting separately.
+		divisions := w.stepDivisions(substep)
+		for j := 0; j < divisions; j++ {
+			if err := w.runSubstep(substep / float64(divisions)); err != nil {
+				return err
+			}
+		}
+		w.divisions += divisions
+	}
+
+	w.elapsed += duration
+	w.steps++
+
+	return nil
+}
+
+// maxStepDivisions caps the number of pieces MaxStepDistance splits a single step into, so a
+// runaway particle can't stall the simulation.
+const maxStepDivisions = 64
+
+// stepDivisions returns the number of equal pieces a step of the given duration must be split into
+// for no finite-mass particle to move further than MaxStepDistance in any of them.
+func (w *ParticleWorld) stepDivisions(duration float64) int {
+	if w.MaxStepDistance <= 0 {
+		return 1
+	}
+
+	maxSpeedSquared := 0.0
+	for _, p := range w.particles {
+		if p.HasFiniteMass() {
+			maxSpeedSquared = max(maxSpeedSquared, p.Velocity.Dot(p.Velocity))
+		}
+	}
+
+	// Written so that a non-finite speed is caught by the cap, rather than converted to an int.
+	ratio := math.Sqrt(maxSpeedSquared) * duration / w.MaxStepDistance
+	switch {
+	case !(ratio > 1):
+		return 1
+	case ratio >= maxStepDivisions:
+		return maxStepDivisions
+	default:
+		return int(math.Ceil(ratio))
+	}
+}
+
+// runSubstep applies the forces, integrates, and resolves contacts over a single substep.
+func (w *ParticleWorld) runSubstep(duration float64) error {
+	// First apply the force generators.
+	w.Registry.UpdateForces(duration)
+
+	// Then integrate the objects.
+	if err := w.Integrate(duration); err != nil {
+		return err
+	}
+
+	if w.Bounds != nil {
+		w.clampToBounds()
+	}
+
+	if w.AutoDespawn {
+		w.despawnExpired()
+	}
+
+	// Generate contacts.
+	used := w.GenerateContacts()
+
+	// And process them.
+	switch {
+	case used == 0:
+	case w.Solver != nil:
+		w.Solver.Resolve(w.contacts[:used], duration)
+	default:
+		if w.calculateIterations {
+			w.resolver.Iterations = used * 2
+		}
+		w.resolver.ResolveContacts(w.contacts[:used], duration)
+	}
+
+	return nil
+}
+
+// Elapsed returns the total simulated time, in seconds, advanced by RunPhysics. It is independent
+// of wall-clock time, so it can be used to time gameplay events in simulation time.
+func (w *ParticleWorld) Elapsed() float64 {
+	w.lock()
+	defer w.unlock()
+	return w.elapsed
+}
+
+// StepDivisions returns the number of steps the last call to RunPhysics was split into, counting
+// both Substeps and any extra splitting for MaxStepDistance. It is one for an unsplit step.
+func (w *ParticleWorld) StepDivisions() int {
+	w.lock()
+	defer w.unlock()
+	return w.divisions
+}
+
+// StepCount returns the number of times RunPhysics has completed successfully.
+func (w *ParticleWorld) StepCount() uint64 {
+	w.lock()
+	defer w.unlock()
+	return w.steps
+}
+
+// despawnExpired despawns every particle in the world whose lifetime has run out.
+func (w *ParticleWorld) despawnExpired() {
+	kept := w.particles[:0]
+	for _, p := range w.particles {
+		if !p.Expired() {
+			kept = append(kept, p)
+			continue
+		}
+
+		w.Registry.RemoveAllForParticle(p)
+		if w.Pool != nil {
+			w.Pool.Release(p)
+		}
+	}
+
+	// Clear the tail so despawned particles aren't kept alive by the backing array.
+	for i := len(kept); i < len(w.particles); i++ {
+		w.particles[i] = nil
+	}
+	w.particles = kept
+}
+
+// clampToBounds moves every particle outside the world's Bounds back inside them, and with
+// WallBounds, zeroes each outward velocity component.
+func (w *ParticleWorld) clampToBounds() {
+	b := w.Bounds
+	for _, p := range w.particles {
+		if b.Contains(p.Position) {
+			continue
+		}
+
+		if w.WallBounds {
+			p.Velocity.X = wallVelocity(p.Position.X, p.Velocity.X, b.Min.X, b.Max.X)
+			p.Velocity.Y = wallVelocity(p.Position.Y, p.Velocity.Y, b.Min.Y, b.Max.Y)
+			p.Velocity.Z = wallVelocity(p.Position.Z, p.Velocity.Z, b.Min.Z, b.Max.Z)
+		}
+		p.Position = b.Clamp(p.Position)
+	}
+}
+
+// wallVelocity returns the velocity along one axis after hitting a wall at min or max, zeroing it if
+// the position is past a wall and the velocity is carrying it further out.
+func wallVelocity(position, velocity, min, max float64) float64 {
+	if (position < min && velocity < 0) || (position > max && velocity > 0) {
+		return 0
+	}
+	return velocity
+}
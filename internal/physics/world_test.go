@@ -0,0 +1,606 @@
+package physics
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+func TestWorldAutoDespawn(t *testing.T) {
+	tests := []struct {
+		name        string
+		autoDespawn bool
+		pool        bool
+		wantRemoved bool
+	}{
+		{"despawns expired", true, false, true},
+		{"despawns expired into pool", true, true, true},
+		{"disabled keeps expired", false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewParticleWorld(0, 0)
+			w.AutoDespawn = tt.autoDespawn
+			if tt.pool {
+				w.Pool = NewParticlePool(0)
+			}
+
+			mortal := newTestParticle(math64.Vector3{}, math64.Vector3{}, 1)
+			mortal.Lifetime = 0.5
+			immortal := newTestParticle(math64.Vector3{}, math64.Vector3{}, 1)
+			gravity := NewGravityGenerator(math64.NewVector3(0, -10, 0))
+			w.AddParticle(mortal)
+			w.AddParticle(immortal)
+			w.AddForce(mortal, gravity)
+			w.AddForce(immortal, gravity)
+
+			for i := 0; i < 3; i++ {
+				if err := w.RunPhysics(0.25); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			particles := w.Particles()
+			found := false
+			for _, p := range particles {
+				found = found || p == mortal
+			}
+			if found == tt.wantRemoved {
+				t.Errorf("expired particle in world = %v, want %v", found, !tt.wantRemoved)
+			}
+			wantParticles, wantGenerators := 2, 1
+			if tt.wantRemoved {
+				wantParticles, wantGenerators = 1, 0
+			}
+			if n := len(particles); n != wantParticles {
+				t.Errorf("world has %d particles, want %d", n, wantParticles)
+			}
+			if n := len(w.Registry.GeneratorsFor(mortal)); n != wantGenerators {
+				t.Errorf("expired particle has %d generators, want %d", n, wantGenerators)
+			}
+			if n := len(w.Registry.GeneratorsFor(immortal)); n != 1 {
+				t.Errorf("live particle has %d generators, want 1", n)
+			}
+			if tt.pool && w.Pool.Available() != 1 {
+				t.Errorf("pool has %d particles available, want the despawned one", w.Pool.Available())
+			}
+		})
+	}
+}
+
+func TestConcurrentWorldSpawnWhileStepping(t *testing.T) {
+	const spawners, perSpawner = 4, 100
+	w := NewConcurrentParticleWorld(0, 0)
+	gravity := NewGravityGenerator(math64.NewVector3(0, -9.81, 0))
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			if err := w.RunPhysics(0.016); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	spawned := make([][]*Particle, spawners)
+	for s := 0; s < spawners; s++ {
+		wg.Add(1)
+		go func(s int) {
+			defer wg.Done()
+			for i := 0; i < perSpawner; i++ {
+				p := newTestParticle(math64.NewVector3(float64(s), float64(i), 0), math64.Vector3{}, 1)
+				w.AddParticle(p)
+				w.AddForce(p, gravity)
+				spawned[s] = append(spawned[s], p)
+				// Remove every other particle again, so removal races with stepping too.
+				if i%2 == 1 {
+					w.RemoveForce(p, gravity)
+					w.RemoveParticle(p)
+				}
+			}
+		}(s)
+	}
+	wg.Wait()
+	<-done
+
+	particles := w.Particles()
+	if want := spawners * perSpawner / 2; len(particles) != want {
+		t.Fatalf("world has %d particles, want %d", len(particles), want)
+	}
+	inWorld := make(map[*Particle]bool, len(particles))
+	for _, p := range particles {
+		if inWorld[p] {
+			t.Fatalf("particle %p is in the world twice", p)
+		}
+		inWorld[p] = true
+	}
+	for s := range spawned {
+		for i, p := range spawned[s] {
+			kept := i%2 == 0
+			if inWorld[p] != kept {
+				t.Errorf("spawner %d particle %d in world = %v, want %v", s, i, inWorld[p], kept)
+			}
+			wantGenerators := 0
+			if kept {
+				wantGenerators = 1
+			}
+			if n := len(w.Registry.GeneratorsFor(p)); n != wantGenerators {
+				t.Errorf("spawner %d particle %d has %d generators, want %d", s, i, n, wantGenerators)
+			}
+		}
+	}
+}
+
+func TestWorldBounds(t *testing.T) {
+	tests := []struct {
+		name         string
+		velocity     math64.Vector3
+		wall         bool
+		wantPosition math64.Vector3
+		wantVelocity math64.Vector3
+	}{
+		{"inside", math64.NewVector3(1, 0, -1), false, math64.NewVector3(1, 0, -1), math64.NewVector3(1, 0, -1)},
+		{"clamped", math64.NewVector3(30, 0, 0), false, math64.NewVector3(10, 0, 0), math64.NewVector3(30, 0, 0)},
+		{"clamped by a wall", math64.NewVector3(30, 2, 0), true, math64.NewVector3(10, 2, 0), math64.NewVector3(0, 2, 0)},
+		{"clamped past two walls", math64.NewVector3(0, -40, 300), true, math64.NewVector3(0, -10, 200), math64.Vector3{}},
+		{"wall inside", math64.NewVector3(3, 4, 5), true, math64.NewVector3(3, 4, 5), math64.NewVector3(3, 4, 5)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bounds := math64.NewAABB(math64.NewVector3(-10, -10, -200), math64.NewVector3(10, 10, 200))
+			w := NewParticleWorld(0, 0)
+			w.Bounds = &bounds
+			w.WallBounds = tt.wall
+			p := newTestParticle(math64.Vector3{}, tt.velocity, 1)
+			w.AddParticle(p)
+
+			if err := w.RunPhysics(1); err != nil {
+				t.Fatal(err)
+			}
+			if !p.Position.ApproxEqual(tt.wantPosition, 1e-9) {
+				t.Errorf("position = %v, want %v", p.Position, tt.wantPosition)
+			}
+			if !p.Velocity.ApproxEqual(tt.wantVelocity, 1e-9) {
+				t.Errorf("velocity = %v, want %v", p.Velocity, tt.wantVelocity)
+			}
+		})
+	}
+}
+
+func TestWorldClock(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps int
+		dt    float64
+	}{
+		{"no steps", 0, 0.016},
+		{"one step", 1, 0.5},
+		{"exact steps", 64, 0.125},
+		{"many small steps", 1000, 0.01},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewParticleWorld(0, 0)
+			w.AddParticle(newTestParticle(math64.Vector3{}, math64.NewVector3(1, 0, 0), 1))
+			for i := 0; i < tt.steps; i++ {
+				if err := w.RunPhysics(tt.dt); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got, want := w.Elapsed(), float64(tt.steps)*tt.dt; !math64.ApproxEqual(got, want, 1e-9) {
+				t.Errorf("Elapsed() = %v, want %v", got, want)
+			}
+			if got := w.StepCount(); got != uint64(tt.steps) {
+				t.Errorf("StepCount() = %v, want %v", got, tt.steps)
+			}
+		})
+	}
+}
+
+func TestWorldClockSkipsFailedSteps(t *testing.T) {
+	w := NewParticleWorld(0, 0)
+	w.RunPhysics(0.5)
+	if err := w.RunPhysics(-1); err == nil {
+		t.Fatal("RunPhysics(-1) succeeded, want an error")
+	}
+	if w.Elapsed() != 0.5 || w.StepCount() != 1 {
+		t.Errorf("after a failed step, Elapsed() = %v and StepCount() = %v, want 0.5 and 1", w.Elapsed(), w.StepCount())
+	}
+}
+
+func TestWorldsStepIndependently(t *testing.T) {
+	// GravityGenerator scales with the square of the distance from the origin, so the particles
+	// start 1 away from it to feel exactly the configured gravity.
+	gravity := NewGravityGenerator(math64.NewVector3(0, -10, 0))
+	a, b := NewParticleWorld(0, 0), NewParticleWorld(0, 0)
+	inA := newTestParticle(math64.NewVector3(1, 0, 0), math64.Vector3{}, 1)
+	inB := newTestParticle(math64.NewVector3(0, 0, 1), math64.Vector3{}, 2)
+	unregistered := newTestParticle(math64.NewVector3(-1, 0, 0), math64.Vector3{}, 1)
+	a.AddParticle(inA)
+	a.AddParticle(unregistered)
+	b.AddParticle(inB)
+	a.AddForce(inA, gravity)
+	b.AddForce(inB, gravity)
+
+	tests := []struct {
+		name       string
+		world      *ParticleWorld
+		wantA      math64.Vector3
+		wantB      math64.Vector3
+		wantStepsA uint64
+		wantStepsB uint64
+	}{
+		{"step a", a, math64.NewVector3(0, -1, 0), math64.Vector3{}, 1, 0},
+		{"step b", b, math64.NewVector3(0, -1, 0), math64.NewVector3(0, -1, 0), 1, 1},
+		{"step a again", a, math64.NewVector3(0, -2, 0), math64.NewVector3(0, -1, 0), 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.world.RunPhysics(0.1); err != nil {
+				t.Fatal(err)
+			}
+			if !inA.Velocity.ApproxEqual(tt.wantA, 1e-9) || !inB.Velocity.ApproxEqual(tt.wantB, 1e-9) {
+				t.Errorf("velocities = %v and %v, want %v and %v", inA.Velocity, inB.Velocity, tt.wantA, tt.wantB)
+			}
+			if !unregistered.Velocity.IsZero() {
+				t.Errorf("gravity moved a particle it isn't registered to, to %v", unregistered.Velocity)
+			}
+			if a.StepCount() != tt.wantStepsA || b.StepCount() != tt.wantStepsB {
+				t.Errorf("step counts = %d and %d, want %d and %d", a.StepCount(), b.StepCount(), tt.wantStepsA, tt.wantStepsB)
+			}
+		})
+	}
+}
+
+// thinFloor is a floor of the given thickness below y = 0. Unlike GroundContactGenerator, a
+// particle that ends a step beneath it has passed through it and gets no contact.
+type thinFloor struct {
+	particle  *Particle
+	thickness float64
+}
+
+func (f thinFloor) AddContact(contacts []ParticleContact) int {
+	y := f.particle.Position.Y
+	if len(contacts) == 0 || y > 0 || y < -f.thickness {
+		return 0
+	}
+	contacts[0] = ParticleContact{
+		Particles:     [2]*Particle{f.particle, nil},
+		ContactNormal: math64.NewVector3(0, 1, 0),
+		Penetration:   -y,
+	}
+	return 1
+}
+
+func TestWorldSubstepsPreventTunneling(t *testing.T) {
+	tests := []struct {
+		name         string
+		substeps     int
+		wantTunneled bool
+	}{
+		{"unset is a single step", 0, true},
+		{"single step tunnels", 1, true},
+		{"substeps catch the floor", 10, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewParticleWorld(1, 1)
+			w.Substeps = tt.substeps
+			// At 30 m/s the particle crosses 3 m in one 0.1 s step, but only 0.3 m per substep.
+			p := newTestParticle(math64.NewVector3(0, 1, 0), math64.NewVector3(0, -30, 0), 1)
+			w.AddParticle(p)
+			w.AddContactGenerator(thinFloor{particle: p, thickness: 0.5})
+
+			if err := w.RunPhysics(0.1); err != nil {
+				t.Fatal(err)
+			}
+
+			if tunneled := p.Position.Y < -0.5; tunneled != tt.wantTunneled {
+				t.Errorf("tunneled = %v (y = %v), want %v", tunneled, p.Position.Y, tt.wantTunneled)
+			}
+			if divisions := w.StepDivisions(); divisions != max(tt.substeps, 1) {
+				t.Errorf("StepDivisions() = %d, want %d", divisions, max(tt.substeps, 1))
+			}
+		})
+	}
+}
+
+func TestWorldNearestParticle(t *testing.T) {
+	positions := []math64.Vector3{
+		math64.NewVector3(3, 0, 0),
+		math64.NewVector3(0, 4, 0),
+		math64.NewVector3(-3, 0, 0),
+		math64.NewVector3(1, 1, 1),
+	}
+
+	tests := []struct {
+		name         string
+		particles    int
+		point        math64.Vector3
+		wantIndex    int
+		wantDistance float64
+	}{
+		{"empty world", 0, math64.Vector3{}, -1, math.Inf(1)},
+		{"single particle", 1, math64.NewVector3(0, 4, 0), 0, 5},
+		{"closest of several", 4, math64.NewVector3(0, 5, 0), 1, 1},
+		{"on a particle", 4, math64.NewVector3(1, 1, 1), 3, 0},
+		{"tie goes to the first added", 3, math64.NewVector3(0, -4, 0), 0, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewParticleWorld(0, 0)
+			var particles []*Particle
+			for _, pos := range positions[:tt.particles] {
+				p := newTestParticle(pos, math64.Vector3{}, 1)
+				particles = append(particles, p)
+				w.AddParticle(p)
+			}
+
+			got, distance := w.NearestParticle(tt.point)
+
+			var want *Particle
+			if tt.wantIndex >= 0 {
+				want = particles[tt.wantIndex]
+			}
+			if got != want {
+				t.Errorf("NearestParticle(%v) = %v, want %v", tt.point, got, want)
+			}
+			if distance != tt.wantDistance && !math64.ApproxEqual(distance, tt.wantDistance, 1e-12) {
+				t.Errorf("distance = %v, want %v", distance, tt.wantDistance)
+			}
+		})
+	}
+}
+
+// inOrderSolver is a ContactSolver that resolves the first Limit contacts in the order they were
+// generated, rather than most severe first, recording each call's contacts.
+type inOrderSolver struct {
+	Limit int
+	calls [][]ParticleContact
+}
+
+func (s *inOrderSolver) Resolve(contacts []ParticleContact, duration float64) {
+	s.calls = append(s.calls, append([]ParticleContact(nil), contacts...))
+	for i := range contacts[:min(s.Limit, len(contacts))] {
+		contacts[i].resolve(duration, 1)
+	}
+}
+
+var _ ContactSolver = (*ParticleContactResolver)(nil)
+
+func TestWorldSolver(t *testing.T) {
+	tests := []struct {
+		name        string
+		solver      *inOrderSolver
+		wantBounced int // Index of the one particle whose contact is resolved.
+	}{
+		// With a single iteration, the built-in resolver only resolves the faster impact.
+		{"built-in resolves the most severe", nil, 1},
+		{"custom resolves in order", &inOrderSolver{Limit: 1}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewParticleWorld(2, 1)
+			if tt.solver != nil {
+				w.Solver = tt.solver
+			}
+			slow := newTestParticle(math64.NewVector3(0, -0.1, 0), math64.NewVector3(0, -1, 0), 1)
+			fast := newTestParticle(math64.NewVector3(1, -0.1, 0), math64.NewVector3(0, -5, 0), 1)
+			particles := []*Particle{slow, fast}
+			w.AddParticle(slow)
+			w.AddParticle(fast)
+			w.AddContactGenerator(NewGroundContactGenerator(particles, 0, 0.5, 0))
+
+			if err := w.RunPhysics(0.01); err != nil {
+				t.Fatal(err)
+			}
+
+			for i, p := range particles {
+				if bounced := p.Velocity.Y > 0; bounced != (i == tt.wantBounced) {
+					t.Errorf("particle %d moving %v, bounced = %v, want %v", i, p.Velocity, bounced, i == tt.wantBounced)
+				}
+			}
+			if tt.solver != nil {
+				if len(tt.solver.calls) != 1 || len(tt.solver.calls[0]) != 2 {
+					t.Fatalf("solver called with %d batches, want one of 2 contacts", len(tt.solver.calls))
+				}
+				for i, c := range tt.solver.calls[0] {
+					if c.Particles[0] != particles[i] {
+						t.Errorf("contact %d is for %v, want %v", i, c.Particles[0].Position, particles[i].Position)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestWorldSolverSkippedWithoutContacts(t *testing.T) {
+	w := NewParticleWorld(2, 1)
+	solver := &inOrderSolver{Limit: 1}
+	w.Solver = solver
+	p := newTestParticle(math64.NewVector3(0, 1, 0), math64.Vector3{}, 1)
+	w.AddParticle(p)
+	w.AddContactGenerator(NewGroundContactGenerator([]*Particle{p}, 0, 0.5, 0))
+
+	if err := w.RunPhysics(0.01); err != nil {
+		t.Fatal(err)
+	}
+	if len(solver.calls) != 0 {
+		t.Errorf("solver called %d times without contacts, want 0", len(solver.calls))
+	}
+}
+
+func TestWorldApplyGlobal(t *testing.T) {
+	tests := []struct {
+		name  string
+		apply func(w *ParticleWorld)
+		// wantVelocity gives the velocity of a particle of the given mass after one 0.5 s step.
+		wantVelocity func(mass float64) math64.Vector3
+	}{
+		{
+			"force",
+			func(w *ParticleWorld) { w.ApplyGlobalForce(math64.NewVector3(0, 4, 0)) },
+			func(mass float64) math64.Vector3 { return math64.NewVector3(0, 2/mass, 0) },
+		},
+		{
+			"impulse",
+			func(w *ParticleWorld) { w.ApplyGlobalImpulse(math64.NewVector3(2, 0, 0)) },
+			func(mass float64) math64.Vector3 { return math64.NewVector3(2/mass, 0, 0) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewParticleWorld(0, 0)
+			masses := []float64{2, 2, 4}
+			var particles []*Particle
+			for i, mass := range masses {
+				p := newTestParticle(math64.NewVector3(float64(i), 0, 0), math64.Vector3{}, mass)
+				particles = append(particles, p)
+				w.AddParticle(p)
+			}
+			anchor := newTestParticle(math64.NewVector3(0, 5, 0), math64.Vector3{}, 0)
+			w.AddParticle(anchor)
+
+			w.StartFrame()
+			tt.apply(w)
+			if err := w.RunPhysics(0.5); err != nil {
+				t.Fatal(err)
+			}
+
+			for i, p := range particles {
+				if want := tt.wantVelocity(masses[i]); !p.Velocity.ApproxEqual(want, 1e-12) {
+					t.Errorf("particle %d of mass %v moving %v, want %v", i, masses[i], p.Velocity, want)
+				}
+			}
+			if !anchor.Velocity.IsZero() || !anchor.forceAccumulator.IsZero() || anchor.Position != math64.NewVector3(0, 5, 0) {
+				t.Errorf("infinite-mass particle at %v moving %v with force %v, want it untouched",
+					anchor.Position, anchor.Velocity, anchor.forceAccumulator)
+			}
+		})
+	}
+}
+
+func TestWorldApplyGlobalForceLastsOneStep(t *testing.T) {
+	tests := []struct {
+		name        string
+		substeps    int
+		maxDistance float64
+		// firstStep is the duration of the first piece RunPhysics(0.5) is split into, the only
+		// one that feels the force.
+		firstStep float64
+	}{
+		{"unsplit", 0, 0, 0.5},
+		{"substeps", 4, 0, 0.125},
+		{"split by MaxStepDistance", 0, 1, 0.1},
+		{"substeps split by MaxStepDistance", 2, 1, 0.25 / 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewParticleWorld(0, 0)
+			w.Substeps = tt.substeps
+			w.MaxStepDistance = tt.maxDistance
+			// Moving at 10 m/s along X, so a MaxStepDistance of 1 splits a 0.5 s step in five, and a
+			// 0.25 s substep in three.
+			p := newTestParticle(math64.Vector3{}, math64.NewVector3(10, 0, 0), 1)
+			w.AddParticle(p)
+
+			w.StartFrame()
+			w.ApplyGlobalForce(math64.NewVector3(0, 2, 0))
+			for i := 0; i < 3; i++ {
+				if err := w.RunPhysics(0.5); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if want := 2 * tt.firstStep; !math64.ApproxEqual(p.Velocity.Y, want, 1e-12) {
+				t.Errorf("Y velocity = %v, want %v from a single %v s step of force", p.Velocity.Y, want, tt.firstStep)
+			}
+		})
+	}
+}
+
+func TestWorldMaxStepDistance(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxDistance   float64
+		substeps      int
+		speed         float64
+		wantDivisions int
+	}{
+		{"unset", 0, 1, 30, 1},
+		{"slow particle", 1, 1, 5, 1},
+		{"exactly the limit", 1, 1, 10, 1},
+		{"just over the limit", 1, 1, 11, 2},
+		{"fast particle", 0.5, 1, 30, 6},
+		{"split within substeps", 0.5, 2, 30, 6},
+		{"capped", 1e-6, 1, 30, maxStepDivisions},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewParticleWorld(0, 0)
+			w.MaxStepDistance = tt.maxDistance
+			w.Substeps = tt.substeps
+			fast := newTestParticle(math64.Vector3{}, math64.NewVector3(tt.speed, 0, 0), 1)
+			slow := newTestParticle(math64.Vector3{}, math64.NewVector3(0, 0, 1), 1)
+			// An immovable particle's velocity doesn't move it, so it doesn't cause splitting.
+			anchor := newTestParticle(math64.Vector3{}, math64.NewVector3(1000, 0, 0), 0)
+			w.AddParticle(fast)
+			w.AddParticle(slow)
+			w.AddParticle(anchor)
+
+			if err := w.RunPhysics(0.1); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := w.StepDivisions(); got != tt.wantDivisions {
+				t.Errorf("StepDivisions() = %d, want %d", got, tt.wantDivisions)
+			}
+			// Splitting takes the same displacement in smaller steps.
+			if want := math64.NewVector3(tt.speed*0.1, 0, 0); !fast.Position.ApproxEqual(want, 1e-12) {
+				t.Errorf("fast particle at %v, want %v", fast.Position, want)
+			}
+			if want := math64.NewVector3(0, 0, 0.1); !slow.Position.ApproxEqual(want, 1e-12) {
+				t.Errorf("slow particle at %v, want %v", slow.Position, want)
+			}
+			if tt.wantDivisions < maxStepDivisions {
+				if limit := max(tt.maxDistance, tt.speed*0.1); fast.StepDistance() > limit+1e-12 {
+					t.Errorf("last step moved %v, want at most %v", fast.StepDistance(), limit)
+				}
+			}
+		})
+	}
+}
+
+func TestWorldMaxStepDistancePreventsTunneling(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxDistance  float64
+		wantTunneled bool
+	}{
+		{"unset", 0, true},
+		{"longer than the step", 5, true},
+		{"thinner than the floor", 0.4, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewParticleWorld(1, 1)
+			w.MaxStepDistance = tt.maxDistance
+			p := newTestParticle(math64.NewVector3(0, 1, 0), math64.NewVector3(0, -30, 0), 1)
+			w.AddParticle(p)
+			w.AddContactGenerator(thinFloor{particle: p, thickness: 0.5})
+
+			if err := w.RunPhysics(0.1); err != nil {
+				t.Fatal(err)
+			}
+
+			if tunneled := p.Position.Y < -0.5; tunneled != tt.wantTunneled {
+				t.Errorf("tunneled = %v (y = %v), want %v", tunneled, p.Position.Y, tt.wantTunneled)
+			}
+		})
+	}
+}
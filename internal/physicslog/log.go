@@ -3,10 +3,17 @@
 package physicslog
 
 import (
+	"fmt"
+	"io"
 	"log"
 	"os"
 	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/user54778/cyclone/internal/math64"
 )
 
 // Level represents the severity level for a log entry.
@@ -35,40 +42,137 @@ func (l Level) String() string {
 	}
 }
 
+// ParseLevel returns the severity level named by s, one of "info", "error", "fatal" or "off",
+// in any case, such as from a command-line flag.
+func ParseLevel(s string) (Level, error) {
+	for _, l := range []Level{LevelInfo, LevelError, LevelFatal, LevelOff} {
+		if strings.EqualFold(s, l.String()) {
+			return l, nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("physicslog: unknown level %q", s)
+}
+
 // PhysicsLogger is a type that implements a basic logger.
 type PhysicsLogger struct {
-	logger   *log.Logger // Logger is guaranteed to be serial.
-	minLevel Level       // The minimum severity level log entries are written for
+	logger         *log.Logger // Logger is guaranteed to be serial.
+	minLevel       Level       // The minimum severity level log entries are written for
+	floatPrecision int         // Significant digits for float attributes, or -1 for the shortest exact form.
+
+	mu      sync.RWMutex  // Guards entries, so Close can't close it while an entry is being queued.
+	entries chan string   // Formatted entries waiting to be written in async mode, or nil if synchronous.
+	done    chan struct{} // Closed once the async writer has written every queued entry.
 }
 
+// defaultAsyncBuffer is the number of entries SetAsync queues when no buffer size is given.
+const defaultAsyncBuffer = 256
+
 // NewPhysicsLogger creates a PhysicsLogger object with a specified logging level.
 // It writes to os.Stdout by default.
 func NewPhysicsLogger(level Level) *PhysicsLogger {
+	return newPhysicsLogger(os.Stdout, level)
+}
+
+// NewPhysicsLoggerMulti creates a PhysicsLogger object with a specified logging level that writes
+// every entry to all of the given writers, such as os.Stdout and a log file. Writers are written
+// in order, and an entry that fails to write to one is not written to those after it. With no
+// writers, entries are discarded.
+func NewPhysicsLoggerMulti(level Level, writers ...io.Writer) *PhysicsLogger {
+	return newPhysicsLogger(io.MultiWriter(writers...), level)
+}
+
+// newPhysicsLogger creates a PhysicsLogger object with a specified logging level that writes to w.
+func newPhysicsLogger(w io.Writer, level Level) *PhysicsLogger {
 	return &PhysicsLogger{
-		logger:   log.New(os.Stdout, "", 0),
-		minLevel: level,
+		logger:         log.New(w, "", 0),
+		minLevel:       level,
+		floatPrecision: -1,
+	}
+}
+
+// SetFloatPrecision makes the logger format float attributes with a fixed number of significant
+// digits, like %.6g for a precision of 6, so logs have a consistent, machine-readable layout. A
+// precision of zero or less restores the default: the shortest form that represents the value exactly.
+func (p *PhysicsLogger) SetFloatPrecision(precision int) {
+	if precision <= 0 {
+		precision = -1
+	}
+	p.floatPrecision = precision
+}
+
+// SetAsync switches the logger to asynchronous mode: entries are formatted when they are logged,
+// then queued, and written by a background goroutine, so a slow writer doesn't hold up the caller.
+// Up to bufferSize entries can be queued, or 256 if bufferSize is zero or less; once the queue is
+// full, logging blocks until there is room. Entries are still written in the order they were logged.
+//
+// Call Close to write the remaining entries and stop the goroutine. Calling SetAsync on a logger
+// that is already asynchronous does nothing.
+func (p *PhysicsLogger) SetAsync(bufferSize int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.entries != nil {
+		return
 	}
+	if bufferSize <= 0 {
+		bufferSize = defaultAsyncBuffer
+	}
+
+	entries := make(chan string, bufferSize)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for entry := range entries {
+			p.logger.Print(entry)
+		}
+	}()
+
+	p.entries, p.done = entries, done
+}
+
+// Close writes every entry still queued by an asynchronous logger, and waits for its background
+// goroutine to exit. The logger then returns to writing synchronously, so entries logged after
+// Close are not lost. Close on a synchronous logger does nothing. It always returns nil, and
+// exists so the logger satisfies io.Closer.
+func (p *PhysicsLogger) Close() error {
+	p.mu.Lock()
+	entries, done := p.entries, p.done
+	p.entries, p.done = nil, nil
+	p.mu.Unlock()
+
+	if entries == nil {
+		return nil
+	}
+	// No entry can be mid-send now: senders hold the read lock while queueing.
+	close(entries)
+	<-done
+	return nil
 }
 
 // LogInfo logs a message at INFO level.
-func (p *PhysicsLogger) LogInfo(message string) {
-	p.log(LevelInfo, message)
+//
+// Any attributes are given as alternating keys and values, and are appended to the message as
+// key=value pairs, e.g. LogInfo("step", "dt", 0.016, "particles", 12).
+func (p *PhysicsLogger) LogInfo(message string, attrs ...any) {
+	p.log(LevelInfo, message, attrs)
 }
 
-// LogError logs a message at ERROR level.
-func (p *PhysicsLogger) LogError(message string) {
-	p.log(LevelError, message)
+// LogError logs a message at ERROR level, with attributes as in LogInfo.
+func (p *PhysicsLogger) LogError(message string, attrs ...any) {
+	p.log(LevelError, message, attrs)
 }
 
-// LogFatal logs a message at FATAL level. It also terminates the goroutine it
-// was called on with os.Exit.
-func (p *PhysicsLogger) LogFatal(message string) {
-	p.log(LevelFatal, message)
+// LogFatal logs a message at FATAL level, with attributes as in LogInfo, then ends the whole
+// program with os.Exit(1), once an asynchronous logger has written every queued entry. Deferred
+// calls, in this and every other goroutine, are not run.
+func (p *PhysicsLogger) LogFatal(message string, attrs ...any) {
+	p.log(LevelFatal, message, attrs)
+	p.Close()
 	os.Exit(1)
 }
 
 // log formats and writes a log entry with the specified message and log entry.
-func (p *PhysicsLogger) log(level Level, message string) {
+func (p *PhysicsLogger) log(level Level, message string, attrs []any) {
 	if level < p.minLevel || level == LevelOff {
 		return
 	}
@@ -80,5 +184,47 @@ func (p *PhysicsLogger) log(level Level, message string) {
 	}
 
 	t := time.Now().UTC().Format(time.RFC3339)
-	p.logger.Printf("[%s %s] %s %s", level.String(), t, message, trace)
+	entry := fmt.Sprintf("[%s %s] %s%s %s", level.String(), t, message, p.formatAttrs(attrs), trace)
+
+	p.mu.RLock()
+	if p.entries != nil {
+		p.entries <- entry
+		p.mu.RUnlock()
+		return
+	}
+	p.mu.RUnlock()
+
+	p.logger.Print(entry)
+}
+
+// formatAttrs formats alternating keys and values as " key=value" pairs. A trailing value without
+// a key is given the key "!BADKEY".
+func (p *PhysicsLogger) formatAttrs(attrs []any) string {
+	var b strings.Builder
+	for i := 0; i < len(attrs); i += 2 {
+		key, value := "!BADKEY", attrs[i]
+		if i+1 < len(attrs) {
+			key, value = fmt.Sprint(attrs[i]), attrs[i+1]
+		}
+		b.WriteByte(' ')
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(p.formatValue(value))
+	}
+	return b.String()
+}
+
+// formatValue formats a single attribute value, using the logger's float precision for floats.
+// Vectors are written in math64's canonical "x,y,z" form, with each component formatted as a float.
+func (p *PhysicsLogger) formatValue(value any) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'g', p.floatPrecision, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', p.floatPrecision, 32)
+	case math64.Vector3:
+		return p.formatValue(v.X) + "," + p.formatValue(v.Y) + "," + p.formatValue(v.Z)
+	default:
+		return fmt.Sprint(v)
+	}
 }
@@ -0,0 +1,238 @@
+package physicslog
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user54778/cyclone/internal/math64"
+)
+
+// loggedAttrs logs message with attrs at INFO level on a logger with the given float precision,
+// and returns the entry with its level and timestamp prefix removed.
+func loggedAttrs(t *testing.T, precision int, message string, attrs ...any) string {
+	t.Helper()
+	var buf bytes.Buffer
+	logger := newPhysicsLogger(&buf, LevelInfo)
+	logger.SetFloatPrecision(precision)
+	logger.LogInfo(message, attrs...)
+
+	entry := strings.TrimSpace(buf.String())
+	_, rest, ok := strings.Cut(entry, "] ")
+	if !ok {
+		t.Fatalf("entry %q has no level prefix", entry)
+	}
+	return rest
+}
+
+func TestFloatPrecision(t *testing.T) {
+	tests := []struct {
+		name      string
+		precision int
+		attrs     []any
+		want      string
+	}{
+		{"default is shortest exact", 0, []any{"dt", 0.016, "third", 1.0 / 3}, "step dt=0.016 third=0.3333333333333333"},
+		{"fixed precision", 6, []any{"dt", 0.016, "third", 1.0 / 3}, "step dt=0.016 third=0.333333"},
+		{"large and small", 3, []any{"big", 123456.0, "small", 0.000012345}, "step big=1.23e+05 small=1.23e-05"},
+		{"float32", 4, []any{"f", float32(math.Pi)}, "step f=3.142"},
+		{"vector", 4, []any{"pos", math64.NewVector3(math.Pi, -1.0/3, 2)}, "step pos=3.142,-0.3333,2"},
+		{"vector default", 0, []any{"g", math64.NewVector3(0, -9.81, 0)}, "step g=0,-9.81,0"},
+		{"non-floats untouched", 2, []any{"n", 12345, "s", "abc"}, "step n=12345 s=abc"},
+		{"missing key", 2, []any{1.2345}, "step !BADKEY=1.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := loggedAttrs(t, tt.precision, "step", tt.attrs...); got != tt.want {
+				t.Errorf("logged %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Level
+		wantErr bool
+	}{
+		{"info", LevelInfo, false},
+		{"error", LevelError, false},
+		{"fatal", LevelFatal, false},
+		{"off", LevelOff, false},
+		{"ERROR", LevelError, false},
+		{"Off", LevelOff, false},
+		{"", LevelInfo, true},
+		{"debug", LevelInfo, true},
+		{"verbose", LevelInfo, true},
+		{"2", LevelInfo, true},
+		{" info", LevelInfo, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLevel(tt.in)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseLevel(%q) = %v, %v, want %v with error %v", tt.in, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewPhysicsLoggerMulti(t *testing.T) {
+	tests := []struct {
+		name     string
+		minLevel Level
+		writers  int
+		wantInfo bool
+		wantErr  bool
+	}{
+		{"info to one", LevelInfo, 1, true, true},
+		{"info to three", LevelInfo, 3, true, true},
+		{"error filters info", LevelError, 2, false, true},
+		{"fatal filters both", LevelFatal, 2, false, false},
+		{"off", LevelOff, 2, false, false},
+		{"no writers", LevelInfo, 0, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bufs := make([]*bytes.Buffer, tt.writers)
+			writers := make([]io.Writer, tt.writers)
+			for i := range bufs {
+				bufs[i] = new(bytes.Buffer)
+				writers[i] = bufs[i]
+			}
+
+			logger := NewPhysicsLoggerMulti(tt.minLevel, writers...)
+			logger.LogInfo("info entry")
+			logger.LogError("error entry")
+
+			for i, buf := range bufs {
+				got := buf.String()
+				if strings.Contains(got, "info entry") != tt.wantInfo {
+					t.Errorf("writer %d: info logged = %v, want %v; got %q", i, !tt.wantInfo, tt.wantInfo, got)
+				}
+				if strings.Contains(got, "error entry") != tt.wantErr {
+					t.Errorf("writer %d: error logged = %v, want %v; got %q", i, !tt.wantErr, tt.wantErr, got)
+				}
+			}
+			for i := 1; i < len(bufs); i++ {
+				if bufs[i].String() != bufs[0].String() {
+					t.Errorf("writer %d got %q, want the same as writer 0, %q", i, bufs[i], bufs[0])
+				}
+			}
+		})
+	}
+}
+
+// slowWriter is a writer that sleeps before every write, so an async logger's queue backs up.
+type slowWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *slowWriter) Write(b []byte) (int, error) {
+	time.Sleep(time.Millisecond)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(b)
+}
+
+func (w *slowWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestAsyncClose(t *testing.T) {
+	tests := []struct {
+		name       string
+		bufferSize int
+		entries    int
+	}{
+		{"default buffer", 0, 20},
+		{"fits in buffer", 64, 20},
+		{"overflows buffer", 2, 20},
+		{"nothing logged", 4, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goroutines := runtime.NumGoroutine()
+			var w slowWriter
+			logger := newPhysicsLogger(&w, LevelInfo)
+			logger.SetAsync(tt.bufferSize)
+			logger.SetAsync(tt.bufferSize) // A second call mustn't start another writer.
+
+			for i := 0; i < tt.entries; i++ {
+				logger.LogInfo("entry", "i", i)
+			}
+			if err := logger.Close(); err != nil {
+				t.Fatalf("Close() = %v", err)
+			}
+
+			// Close returns only once everything queued is written, in order.
+			lines := strings.Split(strings.TrimSpace(w.String()), "\n")
+			if tt.entries == 0 {
+				lines = nil
+			}
+			if len(lines) != tt.entries {
+				t.Fatalf("wrote %d entries, want %d", len(lines), tt.entries)
+			}
+			for i, line := range lines {
+				if want := "entry i=" + strconv.Itoa(i); !strings.HasSuffix(strings.TrimSpace(line), want) {
+					t.Errorf("line %d = %q, want suffix %q", i, line, want)
+				}
+			}
+
+			// The writer goroutine has exited by the time Close returns.
+			if got := runtime.NumGoroutine(); got > goroutines {
+				t.Errorf("%d goroutines after Close, %d before SetAsync", got, goroutines)
+			}
+		})
+	}
+}
+
+func TestAsyncLogsAfterClose(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newPhysicsLogger(&buf, LevelInfo)
+	logger.SetAsync(4)
+	logger.Close()
+	if err := logger.Close(); err != nil {
+		t.Fatalf("second Close() = %v", err)
+	}
+
+	// The logger writes synchronously again, so the entry is written before LogInfo returns.
+	logger.LogInfo("after close")
+	if !strings.Contains(buf.String(), "after close") {
+		t.Fatalf("entry logged after Close was lost: %q", buf.String())
+	}
+}
+
+func TestAsyncConcurrentLogging(t *testing.T) {
+	var w slowWriter
+	logger := newPhysicsLogger(&w, LevelInfo)
+	logger.SetAsync(8)
+
+	const goroutines, perGoroutine = 8, 10
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				logger.LogInfo("entry")
+			}
+		}()
+	}
+	wg.Wait()
+	logger.Close()
+
+	if got := strings.Count(w.String(), "entry"); got != goroutines*perGoroutine {
+		t.Fatalf("wrote %d entries, want %d", got, goroutines*perGoroutine)
+	}
+}